@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+var (
+	ErrAppendOnly       = errors.New("audit events cannot be changed")
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// Event is a row of the append-only audit log. Before and After hold the
+// book as JSON.
+type Event struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	Principal string `gorm:"index"`
+	Method    string
+	BookID    string `gorm:"index"`
+	Type      string
+	Before    []byte
+	After     []byte
+	RequestID string
+	Time      time.Time `gorm:"index"`
+}
+
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+func (Event) BeforeUpdate(*gorm.DB) error {
+	return ErrAppendOnly
+}
+
+func (Event) BeforeDelete(*gorm.DB) error {
+	return ErrAppendOnly
+}
+
+func Models() []any {
+	return []any{&Event{}}
+}
+
+// Write records the change described by event, attributed to the call
+// stored in the context of tx. tx must be the transaction that applies
+// the change.
+func Write(tx *gorm.DB, event *pb.BookEvent) error {
+	info := FromContext(tx.Statement.Context)
+
+	row := &Event{
+		Principal: info.Principal,
+		Method:    info.Method,
+		BookID:    event.GetAfter().GetId(),
+		Type:      event.GetType().String(),
+		RequestID: info.RequestID,
+		Time:      event.GetTime().AsTime(),
+	}
+	if row.BookID == "" {
+		row.BookID = event.GetBefore().GetId()
+	}
+
+	var err error
+	if row.Before, err = marshalBook(event.GetBefore()); err != nil {
+		return err
+	}
+	if row.After, err = marshalBook(event.GetAfter()); err != nil {
+		return err
+	}
+
+	if err := tx.Create(row).Error; err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+
+	return nil
+}
+
+func marshalBook(book *pb.Book) ([]byte, error) {
+	if book == nil {
+		return nil, nil
+	}
+
+	return protojson.Marshal(book)
+}
+
+func unmarshalBook(data []byte) (*pb.Book, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	book := &pb.Book{}
+	if err := protojson.Unmarshal(data, book); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// List returns the events matching req, oldest first, and the token of
+// the next page.
+func (s *Store) List(ctx context.Context, req *pb.ListAuditEventsRequest) ([]*pb.AuditEvent, string, error) {
+	query := s.db.WithContext(ctx).Order("id")
+
+	if req.GetBookId() != "" {
+		query = query.Where("book_id = ?", req.GetBookId())
+	}
+	if req.GetPrincipal() != "" {
+		query = query.Where("principal = ?", req.GetPrincipal())
+	}
+	if req.GetStartTime() != nil {
+		query = query.Where("time >= ?", req.GetStartTime().AsTime())
+	}
+	if req.GetEndTime() != nil {
+		query = query.Where("time < ?", req.GetEndTime().AsTime())
+	}
+	if req.GetPageToken() != "" {
+		after, err := strconv.ParseUint(req.GetPageToken(), 10, 64)
+		if err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+		query = query.Where("id > ?", after)
+	}
+
+	size := int(req.GetPageSize())
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	size = min(size, MaxPageSize)
+
+	// One extra row tells whether there is a next page.
+	var rows []*Event
+	if err := query.Limit(size + 1).Find(&rows).Error; err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(rows) > size {
+		rows = rows[:size]
+		next = strconv.FormatUint(rows[size-1].ID, 10)
+	}
+
+	events := make([]*pb.AuditEvent, len(rows))
+	for i, row := range rows {
+		before, err := unmarshalBook(row.Before)
+		if err != nil {
+			return nil, "", err
+		}
+		after, err := unmarshalBook(row.After)
+		if err != nil {
+			return nil, "", err
+		}
+
+		events[i] = &pb.AuditEvent{
+			Id:        row.ID,
+			Principal: row.Principal,
+			Method:    row.Method,
+			BookId:    row.BookID,
+			Type:      pb.BookEvent_Type(pb.BookEvent_Type_value[row.Type]),
+			Before:    before,
+			After:     after,
+			Time:      timestamppb.New(row.Time),
+			RequestId: row.RequestID,
+		}
+	}
+
+	return events, next, nil
+}
@@ -0,0 +1,102 @@
+package audit_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/database"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/pb"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	opts := database.DefaultOptions()
+	opts.Migrations = []func(*gorm.DB) error{
+		func(db *gorm.DB) error { return db.AutoMigrate(audit.Models()...) },
+	}
+
+	return dbtest.Open(t, opts).DB
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+	store := audit.NewStore(db)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	alice := audit.NewContext(context.Background(), audit.Info{Principal: "alice", Method: "/BookService/UpdateBook", RequestID: "r1"})
+	changes := []struct {
+		ctx   context.Context
+		event *pb.BookEvent
+	}{
+		{alice, &pb.BookEvent{Type: pb.BookEvent_CREATED, After: &pb.Book{Id: "1", Price: 100}}},
+		{alice, &pb.BookEvent{Type: pb.BookEvent_UPDATED, Before: &pb.Book{Id: "1", Price: 100}, After: &pb.Book{Id: "1", Price: 90}}},
+		{context.Background(), &pb.BookEvent{Type: pb.BookEvent_DELETED, Before: &pb.Book{Id: "2"}}},
+	}
+	for i, change := range changes {
+		change.event.Time = timestamppb.New(start.Add(time.Duration(i) * time.Hour))
+		assert.NoError(t, audit.Write(db.WithContext(change.ctx), change.event))
+	}
+
+	t.Run("Filter", func(t *testing.T) {
+		events, next, err := store.List(context.Background(), &pb.ListAuditEventsRequest{BookId: "1", Principal: "alice"})
+		assert.NoError(t, err)
+		assert.Empty(t, next)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, pb.BookEvent_UPDATED, events[1].Type)
+			assert.Equal(t, int32(100), events[1].Before.Price)
+			assert.Equal(t, int32(90), events[1].After.Price)
+			assert.Equal(t, "r1", events[1].RequestId)
+			assert.Equal(t, "/BookService/UpdateBook", events[1].Method)
+		}
+
+		events, _, err = store.List(context.Background(), &pb.ListAuditEventsRequest{
+			StartTime: timestamppb.New(start.Add(time.Hour)),
+			EndTime:   timestamppb.New(start.Add(2 * time.Hour)),
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, pb.BookEvent_UPDATED, events[0].Type)
+		}
+
+		events, _, err = store.List(context.Background(), &pb.ListAuditEventsRequest{Principal: audit.Anonymous})
+		assert.NoError(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, "2", events[0].BookId)
+			assert.Nil(t, events[0].After)
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		var ids []uint64
+		req := &pb.ListAuditEventsRequest{PageSize: 2}
+		for {
+			events, next, err := store.List(context.Background(), req)
+			assert.NoError(t, err)
+			for _, event := range events {
+				ids = append(ids, event.Id)
+			}
+			if next == "" {
+				break
+			}
+			req.PageToken = next
+		}
+		assert.Equal(t, []uint64{1, 2, 3}, ids)
+
+		_, _, err := store.List(context.Background(), &pb.ListAuditEventsRequest{PageToken: "x"})
+		assert.ErrorIs(t, err, audit.ErrInvalidPageToken)
+	})
+
+	t.Run("Append Only", func(t *testing.T) {
+		err := db.Model(&audit.Event{}).Where("id = ?", 1).Update("principal", "mallory").Error
+		assert.ErrorIs(t, err, audit.ErrAppendOnly)
+
+		err = db.Where("id = ?", 1).Delete(&audit.Event{}).Error
+		assert.ErrorIs(t, err, audit.ErrAppendOnly)
+	})
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	RequestIDKey = "x-request-id"
+
+	Anonymous = "anonymous"
+)
+
+// Info describes the call that makes a change.
+type Info struct {
+	// Principal is the caller the Authenticator of the interceptors
+	// verified, or Anonymous. It is never taken from request metadata, so
+	// reviews and idempotency keys can rely on it.
+	Principal string
+	Method    string
+	RequestID string
+}
+
+type infoKey struct{}
+
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext returns the call info stored by the interceptors. Changes
+// made outside of an RPC are attributed to Anonymous.
+func FromContext(ctx context.Context) Info {
+	info, ok := ctx.Value(infoKey{}).(Info)
+	if !ok || info.Principal == "" {
+		info.Principal = Anonymous
+	}
+
+	return info
+}
+
+// Principals is a set of principals allowed to do something only some
+// callers may, e.g. register webhooks.
+type Principals map[string]bool
+
+// ParsePrincipals reads a comma-separated list of principals.
+func ParsePrincipals(list string) Principals {
+	principals := Principals{}
+	for _, principal := range strings.Split(list, ",") {
+		if principal = strings.TrimSpace(principal); principal != "" {
+			principals[principal] = true
+		}
+	}
+
+	return principals
+}
+
+// Allows reports whether the caller of ctx is one of the principals.
+// Anonymous callers never are.
+func (p Principals) Allows(ctx context.Context) bool {
+	principal := FromContext(ctx).Principal
+
+	return principal != Anonymous && p[principal]
+}
+
+// Authenticator returns the verified identity of the caller of an RPC, or
+// "" if the caller is not authenticated.
+type Authenticator func(ctx context.Context) string
+
+// PeerCertificate authenticates the caller by the client certificate the
+// TLS handshake verified: the principal is its subject common name. Calls
+// without a verified certificate are anonymous.
+func PeerCertificate(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+func callInfo(ctx context.Context, method string, authenticate Authenticator) Info {
+	info := Info{Principal: Anonymous, Method: method}
+
+	if principal := authenticate(ctx); principal != "" {
+		info.Principal = principal
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDKey); len(values) > 0 && values[0] != "" {
+		info.RequestID = values[0]
+	} else {
+		info.RequestID = uuid.New().String()
+	}
+
+	return info
+}
+
+// UnaryServerInterceptor stores the call info in the context, with the
+// principal authenticate returns, and returns the request id in the
+// x-request-id response header.
+func UnaryServerInterceptor(authenticate Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		call := callInfo(ctx, info.FullMethod, authenticate)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, call.RequestID))
+
+		return handler(NewContext(ctx, call), req)
+	}
+}
+
+func StreamServerInterceptor(authenticate Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		call := callInfo(ss.Context(), info.FullMethod, authenticate)
+		ss.SetHeader(metadata.Pairs(RequestIDKey, call.RequestID))
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: NewContext(ss.Context(), call)})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
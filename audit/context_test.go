@@ -0,0 +1,69 @@
+package audit_test
+
+import (
+	"bookstoregrpc/audit"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func withPeer(state tls.ConnectionState) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func TestPeerCertificate(t *testing.T) {
+	t.Parallel()
+	alice := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}
+
+	assert.Equal(t, "", audit.PeerCertificate(context.Background()))
+	assert.Equal(t, "", audit.PeerCertificate(peer.NewContext(context.Background(), &peer.Peer{})))
+	assert.Equal(t, "alice", audit.PeerCertificate(withPeer(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{alice}}})))
+
+	// A certificate the handshake did not verify proves nothing.
+	assert.Equal(t, "", audit.PeerCertificate(withPeer(tls.ConnectionState{PeerCertificates: []*x509.Certificate{alice}})))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+	interceptor := audit.UnaryServerInterceptor(audit.PeerCertificate)
+	info := &grpc.UnaryServerInfo{FullMethod: "/ReviewService/DeleteReview"}
+
+	principal := func(ctx context.Context) string {
+		var got string
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			got = audit.FromContext(ctx).Principal
+			return nil, nil
+		})
+		assert.NoError(t, err)
+		return got
+	}
+
+	// The x-principal header clients used to send is not trusted.
+	spoofed := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-principal", "alice"))
+	assert.Equal(t, audit.Anonymous, principal(spoofed))
+
+	bob := &x509.Certificate{Subject: pkix.Name{CommonName: "bob"}}
+	verified := metadata.NewIncomingContext(withPeer(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{bob}}}), metadata.Pairs("x-principal", "alice"))
+	assert.Equal(t, "bob", principal(verified))
+}
+
+func TestPrincipals(t *testing.T) {
+	t.Parallel()
+	admins := audit.ParsePrincipals(" alice, bob,,")
+	assert.Len(t, admins, 2)
+
+	assert.True(t, admins.Allows(audit.NewContext(context.Background(), audit.Info{Principal: "alice"})))
+	assert.False(t, admins.Allows(audit.NewContext(context.Background(), audit.Info{Principal: "carol"})))
+	assert.False(t, admins.Allows(context.Background()))
+
+	// Listing anonymous does not let every caller in.
+	assert.False(t, audit.ParsePrincipals(audit.Anonymous).Allows(context.Background()))
+}
@@ -0,0 +1,18 @@
+// Package cache provides byte caches with per-entry expiry: an in-process
+// LRU and a client for a Redis-compatible server.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values by key until they expire. Implementations are safe
+// for concurrent use.
+type Cache interface {
+	// Get returns the value of key and false if it is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
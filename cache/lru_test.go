@@ -0,0 +1,62 @@
+package cache_test
+
+import (
+	"bookstoregrpc/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("Entries", func(t *testing.T) {
+		c := cache.NewLRU(2, 0)
+		assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+
+		_, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+
+		assert.NoError(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+		assert.Equal(t, 2, c.Len())
+		_, ok, _ = c.Get(ctx, "b")
+		assert.False(t, ok, "b is the least recently used")
+		value, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		c := cache.NewLRU(0, 10)
+		assert.NoError(t, c.Set(ctx, "a", []byte("12345"), time.Minute))
+		assert.NoError(t, c.Set(ctx, "b", []byte("12345"), time.Minute))
+		assert.Equal(t, 1, c.Len())
+
+		assert.NoError(t, c.Set(ctx, "big", []byte("12345678901"), time.Minute))
+		_, ok, _ := c.Get(ctx, "big")
+		assert.False(t, ok)
+		_, ok, _ = c.Get(ctx, "b")
+		assert.True(t, ok)
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		c := cache.NewLRU(0, 0)
+		assert.NoError(t, c.Set(ctx, "a", []byte("1"), 10*time.Millisecond))
+		time.Sleep(20 * time.Millisecond)
+		_, ok, _ := c.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := cache.NewLRU(0, 0)
+		assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.NoError(t, c.Delete(ctx, "a", "missing"))
+		_, ok, _ := c.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+}
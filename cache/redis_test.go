@@ -0,0 +1,121 @@
+package cache_test
+
+import (
+	"bookstoregrpc/cache"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startRedis serves GET, SET and DEL from a map over the Redis protocol.
+func startRedis(t *testing.T, password string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	values := map[string]string{}
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		authenticated := password == ""
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			args := make([]string, n)
+			for i := range args {
+				line, _ := r.ReadString('\n')
+				size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+				buf := make([]byte, size+2)
+				io.ReadFull(r, buf)
+				args[i] = string(buf[:size])
+			}
+
+			mu.Lock()
+			switch {
+			case args[0] == "AUTH" && args[1] == password:
+				authenticated = true
+				io.WriteString(conn, "+OK\r\n")
+			case !authenticated:
+				io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+			case args[0] == "GET":
+				if value, ok := values[args[1]]; ok {
+					fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+				} else {
+					io.WriteString(conn, "$-1\r\n")
+				}
+			case args[0] == "SET":
+				values[args[1]] = args[2]
+				io.WriteString(conn, "+OK\r\n")
+			case args[0] == "DEL":
+				deleted := 0
+				for _, key := range args[1:] {
+					if _, ok := values[key]; ok {
+						delete(values, key)
+						deleted++
+					}
+				}
+				fmt.Fprintf(conn, ":%d\r\n", deleted)
+			default:
+				io.WriteString(conn, "-ERR unknown command\r\n")
+			}
+			mu.Unlock()
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestRedis(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	opts := cache.DefaultRedisOptions()
+	opts.Password = "secret"
+	c := cache.NewRedis(startRedis(t, "secret"), opts)
+	defer c.Close()
+
+	_, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	value := []byte("line\r\nbreak")
+	assert.NoError(t, c.Set(ctx, "a", value, time.Minute))
+	got, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, value, got)
+
+	assert.NoError(t, c.Delete(ctx, "a", "b"))
+	_, ok, err = c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	wrong := cache.NewRedis(startRedis(t, "secret"), cache.DefaultRedisOptions())
+	_, _, err = wrong.Get(ctx, "a")
+	assert.ErrorContains(t, err, "NOAUTH")
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"gopkg.in/yaml.v3"
+)
+
+// startServer serves the books of a test database and returns a function
+// that runs bookctl against it, and the authorization metadata of the last
+// call.
+func startServer(t *testing.T) (run func(args ...string) (string, error), authorization *[]string) {
+	authorization = new([]string)
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		*authorization = md.Get("authorization")
+		return handler(ctx, req)
+	}))
+	pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(dbtest.New(t).DB)))
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	run = func(args ...string) (string, error) {
+		a := &app{dialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		}}
+		defer a.close()
+
+		var out bytes.Buffer
+		cmd := newRootCommand(a)
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{"--addr", "passthrough:///bufnet"}, args...))
+		err := cmd.Execute()
+
+		return out.String(), err
+	}
+
+	return run, authorization
+}
+
+func TestCommands(t *testing.T) {
+	run, authorization := startServer(t)
+
+	out, err := run("create", "--id", "karamazov", "--title", "Братья Карамазовы", "--author", "Ф. М. Достоевский", "--price", "1000 RUB", "--tag", "novel", "--tag", "classic")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "ID:")
+	assert.Contains(t, out, "1000.00 RUB")
+	assert.Contains(t, out, "classic, novel")
+
+	_, err = run("create", "--id", "demons", "--title", "Бесы", "--author", "Ф. М. Достоевский", "--price", "650.50")
+	assert.NoError(t, err)
+	_, err = run("create", "--id", "war", "--title", "Война и мир", "--author", "Л. Н. Толстой", "--price", "900 RUB", "--year", "1869")
+	assert.NoError(t, err)
+
+	t.Run("Get JSON", func(t *testing.T) {
+		out, err := run("get", "demons", "-o", "json", "--token", "secret")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Bearer secret"}, *authorization)
+
+		var book map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(out), &book))
+		assert.Equal(t, "Бесы", book["title"])
+		assert.Equal(t, map[string]any{"currencyCode": "RUB", "units": "650", "nanos": float64(500_000_000)}, book["listPrice"])
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		out, err := run("update", "karamazov", "--price", "1200 RUB", "-o", "json")
+		assert.NoError(t, err)
+
+		var book map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(out), &book))
+		assert.Equal(t, "Братья Карамазовы", book["title"])
+		assert.Equal(t, "1200", book["listPrice"].(map[string]any)["units"])
+		assert.Equal(t, []any{"classic", "novel"}, book["tags"])
+
+		_, err = run("update", "karamazov")
+		assert.ErrorContains(t, err, "nothing to update")
+	})
+
+	t.Run("List YAML", func(t *testing.T) {
+		out, err := run("list", "-o", "yaml")
+		assert.NoError(t, err)
+
+		var books []struct {
+			ID    string `yaml:"id"`
+			Title string `yaml:"title"`
+		}
+		assert.NoError(t, yaml.Unmarshal([]byte(out), &books))
+		assert.Len(t, books, 3)
+		assert.Contains(t, out, "- id: ")
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		out, err := run("search", "--author", "Ф. М. Достоевский", "--min-price", "700 RUB")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "TITLE")
+		assert.Contains(t, out, "Братья Карамазовы")
+		assert.NotContains(t, out, "Бесы")
+		assert.NotContains(t, out, "Война и мир")
+
+		_, err = run("search", "--sort", "newest")
+		assert.ErrorContains(t, err, "unknown sort")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		out, err := run("delete", "war", "demons")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "Война и мир")
+		assert.Contains(t, out, "Бесы")
+
+		_, err = run("get", "war")
+		assert.Equal(t, "NotFound: record not found", errorMessage(err))
+	})
+
+	t.Run("Invalid Output", func(t *testing.T) {
+		_, err := run("list", "-o", "xml")
+		assert.ErrorContains(t, err, "unknown output format")
+	})
+}
+
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	price, err := parseMoney("1200.50 usd")
+	assert.NoError(t, err)
+	assert.Equal(t, money.Format(money.New("USD", 1200, 500_000_000)), money.Format(price))
+
+	price, err = parseMoney("300")
+	assert.NoError(t, err)
+	assert.Equal(t, money.DefaultCurrency, price.CurrencyCode)
+
+	for _, s := range []string{"", "abc RUB", "1 RUB extra"} {
+		_, err := parseMoney(s)
+		assert.Error(t, err, s)
+	}
+}
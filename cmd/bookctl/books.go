@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/sample"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sessionTokenKey is database.SessionTokenKey: sending it back lets the
+// read after a write see the write even when reads go to a replica.
+const sessionTokenKey = "x-session-token"
+
+// bookFlags are the book fields settable by create and update.
+type bookFlags struct {
+	author      string
+	title       string
+	price       string
+	isbn        string
+	description string
+	publisher   string
+	year        int32
+	language    string
+	pages       int32
+	cover       string
+	authorIDs   []string
+	categoryIDs []string
+	tags        []string
+
+	set *pflag.FlagSet
+}
+
+// register adds the flags to cmd. They are kept in a set of their own
+// so that apply and changed see only them.
+func (f *bookFlags) register(cmd *cobra.Command) {
+	flags := pflag.NewFlagSet("book", pflag.ContinueOnError)
+	flags.StringVar(&f.author, "author", "", "author as displayed")
+	flags.StringVar(&f.title, "title", "", "title")
+	flags.StringVar(&f.price, "price", "", `list price, e.g. "1200.50 RUB" (the currency defaults to `+money.DefaultCurrency+`)`)
+	flags.StringVar(&f.isbn, "isbn", "", "ISBN-10 or ISBN-13")
+	flags.StringVar(&f.description, "description", "", "description")
+	flags.StringVar(&f.publisher, "publisher", "", "publisher")
+	flags.Int32Var(&f.year, "year", 0, "publication year")
+	flags.StringVar(&f.language, "language", "", "language code, e.g. ru")
+	flags.Int32Var(&f.pages, "pages", 0, "page count")
+	flags.StringVar(&f.cover, "cover", "", "cover URL")
+	flags.StringSliceVar(&f.authorIDs, "author-id", nil, "ids of the book's authors, repeatable")
+	flags.StringSliceVar(&f.categoryIDs, "category", nil, "ids of the book's categories, repeatable")
+	flags.StringSliceVar(&f.tags, "tag", nil, "tags, repeatable")
+
+	f.set = flags
+	cmd.Flags().AddFlagSet(flags)
+}
+
+// changed reports whether any of the flags was set on the command line.
+func (f *bookFlags) changed() bool {
+	changed := false
+	f.set.VisitAll(func(flag *pflag.Flag) { changed = changed || flag.Changed })
+
+	return changed
+}
+
+// apply copies the flags set on the command line to book and leaves the
+// other fields alone.
+func (f *bookFlags) apply(book *pb.Book) error {
+	if f.set.Changed("price") {
+		price, err := parseMoney(f.price)
+		if err != nil {
+			return err
+		}
+		book.ListPrice = price
+	}
+
+	set := func(name string, apply func()) {
+		if f.set.Changed(name) {
+			apply()
+		}
+	}
+	set("author", func() { book.Author = f.author })
+	set("title", func() { book.Title = f.title })
+	set("isbn", func() { book.Isbn = f.isbn })
+	set("description", func() { book.Description = f.description })
+	set("publisher", func() { book.Publisher = f.publisher })
+	set("year", func() { book.PublicationYear = f.year })
+	set("language", func() { book.Language = f.language })
+	set("pages", func() { book.PageCount = f.pages })
+	set("cover", func() { book.CoverUrl = f.cover })
+	set("author-id", func() { book.AuthorIds = f.authorIDs })
+	set("category", func() { book.CategoryIds = f.categoryIDs })
+	set("tag", func() { book.Tags = f.tags })
+
+	return nil
+}
+
+// bookFields maps the flags to the Book fields they set.
+var bookFields = map[string]string{
+	"author":      "author",
+	"title":       "title",
+	"price":       "list_price",
+	"isbn":        "isbn",
+	"description": "description",
+	"publisher":   "publisher",
+	"year":        "publication_year",
+	"language":    "language",
+	"pages":       "page_count",
+	"cover":       "cover_url",
+	"author-id":   "author_ids",
+	"category":    "category_ids",
+	"tag":         "tags",
+}
+
+// mask returns the update mask of the flags set on the command line.
+func (f *bookFlags) mask() *fieldmaskpb.FieldMask {
+	mask := &fieldmaskpb.FieldMask{}
+	f.set.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			mask.Paths = append(mask.Paths, bookFields[flag.Name])
+		}
+	})
+
+	return mask
+}
+
+// parseMoney parses an amount with an optional currency code, e.g.
+// "1200.50 RUB".
+func parseMoney(s string) (*pb.Money, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid price %q, want an amount and a currency code", s)
+	}
+
+	amount, err := money.ParseAmount(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q: %w", s, err)
+	}
+	currency := money.DefaultCurrency
+	if len(fields) == 2 {
+		currency = strings.ToUpper(fields[1])
+	}
+
+	return amount.Money(currency), nil
+}
+
+func newCreateCommand(a *app) *cobra.Command {
+	var (
+		fields  bookFlags
+		id      string
+		example bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a book",
+		Example: `  bookctl create --title "Бесы" --author "Ф. М. Достоевский" --price "650 RUB"
+  bookctl create --sample --title "Черновик"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd.Context())
+			defer cancel()
+
+			book := &pb.Book{}
+			if example {
+				book = sample.NewBook()
+			}
+			if err := fields.apply(book); err != nil {
+				return err
+			}
+			book.Id = id
+			if book.Id == "" {
+				book.Id = sample.RandomID()
+			}
+
+			var header metadata.MD
+			res, err := client.CreateBook(ctx, &pb.CreateBookRequest{Book: book}, grpc.Header(&header))
+			if err != nil {
+				return err
+			}
+			if tokens := header.Get(sessionTokenKey); len(tokens) > 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, sessionTokenKey, tokens[0])
+			}
+
+			created, err := client.ReadBook(ctx, &pb.ReadBookRequest{Id: res.GetId()})
+			if err != nil {
+				return err
+			}
+
+			return printBook(cmd.OutOrStdout(), a.output, created.GetBook())
+		},
+	}
+
+	fields.register(cmd)
+	cmd.Flags().StringVar(&id, "id", "", "id of the new book, a random UUID by default")
+	cmd.Flags().BoolVar(&example, "sample", false, "fill the fields not given with random sample data")
+
+	return cmd
+}
+
+func newGetCommand(a *app) *cobra.Command {
+	var asOf string
+
+	cmd := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a book",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &pb.ReadBookRequest{Id: args[0]}
+			if asOf != "" {
+				t, err := time.Parse(time.RFC3339, asOf)
+				if err != nil {
+					return fmt.Errorf("invalid --as-of: %w", err)
+				}
+				req.AsOf = timestamppb.New(t)
+			}
+
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd.Context())
+			defer cancel()
+
+			res, err := client.ReadBook(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return printBook(cmd.OutOrStdout(), a.output, res.GetBook())
+		},
+	}
+
+	cmd.Flags().StringVar(&asOf, "as-of", "", "show the book as it was at this RFC 3339 time")
+
+	return cmd
+}
+
+func newListCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all books",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd.Context())
+			defer cancel()
+
+			stream, err := client.ReadBooks(ctx, &emptypb.Empty{})
+			if err != nil {
+				return err
+			}
+
+			books, err := recvBooks(stream.Recv)
+			if err != nil {
+				return err
+			}
+
+			return printBooks(cmd.OutOrStdout(), a.output, books)
+		},
+	}
+}
+
+func newUpdateCommand(a *app) *cobra.Command {
+	var fields bookFlags
+
+	cmd := &cobra.Command{
+		Use:     "update ID",
+		Short:   "Change fields of a book",
+		Long:    "Change the fields given as flags; the others keep their current values.",
+		Example: `  bookctl update 3f2a... --price "720 RUB" --tag classic --tag novel`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !fields.changed() {
+				return errors.New("nothing to update, set at least one field flag")
+			}
+
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd.Context())
+			defer cancel()
+
+			book := &pb.Book{Id: args[0]}
+			if err := fields.apply(book); err != nil {
+				return err
+			}
+
+			res, err := client.UpdateBook(ctx, &pb.UpdateBookRequest{Id: args[0], Book: book, UpdateMask: fields.mask()})
+			if err != nil {
+				return err
+			}
+
+			return printBook(cmd.OutOrStdout(), a.output, res.GetBook())
+		},
+	}
+
+	fields.register(cmd)
+
+	return cmd
+}
+
+func newDeleteCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete ID...",
+		Short: "Delete books",
+		Long:  "Delete the books one by one and show the deleted ones; stops at the first error.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd.Context())
+			defer cancel()
+
+			var deleted []*pb.Book
+			for _, id := range args {
+				res, err := client.DeleteBook(ctx, &pb.DeleteBookRequest{Id: id})
+				if err != nil {
+					// Still show what was deleted before the failure.
+					printBooks(cmd.OutOrStdout(), a.output, deleted)
+					return fmt.Errorf("delete %s: %w", id, err)
+				}
+				deleted = append(deleted, res.GetBook())
+			}
+
+			return printBooks(cmd.OutOrStdout(), a.output, deleted)
+		},
+	}
+}
+
+// searchFlags are the search filter flags; prices are parsed like --price.
+type searchFlags struct {
+	filter            pb.Filter
+	minPrice          string
+	maxPrice          string
+	minEffectivePrice string
+	maxEffectivePrice string
+	sort              string
+}
+
+func (f *searchFlags) request() (*pb.SearchBookRequest, error) {
+	req := &pb.SearchBookRequest{Filter: &f.filter}
+
+	for _, bound := range []struct {
+		value string
+		name  string
+		field **pb.Money
+	}{
+		{f.minPrice, "min-price", &f.filter.MinPrice},
+		{f.maxPrice, "max-price", &f.filter.MaxPrice},
+		{f.minEffectivePrice, "min-effective-price", &f.filter.MinEffectivePrice},
+		{f.maxEffectivePrice, "max-effective-price", &f.filter.MaxEffectivePrice},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		price, err := parseMoney(bound.value)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", bound.name, err)
+		}
+		*bound.field = price
+	}
+
+	switch f.sort {
+	case "":
+	case "rating-desc":
+		req.Sort = pb.SearchBookRequest_RATING_DESC
+	case "rating-asc":
+		req.Sort = pb.SearchBookRequest_RATING_ASC
+	default:
+		return nil, fmt.Errorf("unknown sort %q, want rating-desc or rating-asc", f.sort)
+	}
+
+	return req, nil
+}
+
+func newSearchCommand(a *app) *cobra.Command {
+	var search searchFlags
+
+	cmd := &cobra.Command{
+		Use:     "search",
+		Short:   "Search books",
+		Example: `  bookctl search --author "Достоевский" --min-price "300 RUB" --sort rating-desc`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req, err := search.request()
+			if err != nil {
+				return err
+			}
+
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd.Context())
+			defer cancel()
+
+			stream, err := client.SearchBook(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			books, err := recvBooks(stream.Recv)
+			if err != nil {
+				return err
+			}
+
+			return printBooks(cmd.OutOrStdout(), a.output, books)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&search.filter.Author, "author", "", "author text, or a name or alias of one of the authors")
+	flags.StringVar(&search.filter.AuthorId, "author-id", "", "id of one of the authors")
+	flags.StringVar(&search.filter.Isbn, "isbn", "", "ISBN")
+	flags.StringVar(&search.filter.Publisher, "publisher", "", "publisher")
+	flags.Int32Var(&search.filter.PublicationYear, "year", 0, "publication year")
+	flags.StringVar(&search.filter.Language, "language", "", "language code")
+	flags.StringVar(&search.filter.CategoryId, "category", "", "category id, including its subcategories")
+	flags.StringVar(&search.filter.Tag, "tag", "", "tag")
+	flags.Float64Var(&search.filter.MinRating, "min-rating", 0, "minimum average rating")
+	flags.StringVar(&search.minPrice, "min-price", "", "minimum list price, inclusive")
+	flags.StringVar(&search.maxPrice, "max-price", "", "maximum list price, inclusive")
+	flags.StringVar(&search.minEffectivePrice, "min-effective-price", "", "minimum price after discounts, inclusive")
+	flags.StringVar(&search.maxEffectivePrice, "max-effective-price", "", "maximum price after discounts, inclusive")
+	flags.StringVar(&search.sort, "sort", "", "rating-desc or rating-asc")
+
+	return cmd
+}
+
+// recvBooks reads a stream of responses carrying a book until it ends.
+func recvBooks[T interface{ GetBook() *pb.Book }](recv func() (T, error)) ([]*pb.Book, error) {
+	var books []*pb.Book
+	for {
+		res, err := recv()
+		if errors.Is(err, io.EOF) {
+			return books, nil
+		}
+		if err != nil {
+			return books, err
+		}
+		books = append(books, res.GetBook())
+	}
+}
@@ -0,0 +1,196 @@
+// Command bookctl manages the books of a bookstore server over gRPC.
+package main
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/tracing"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultAddress = "localhost:8080"
+	defaultTimeout = 10 * time.Second
+)
+
+// app holds the global flags and the connection shared by the commands.
+type app struct {
+	address            string
+	useTLS             bool
+	caFile             string
+	certFile           string
+	keyFile            string
+	serverName         string
+	insecureSkipVerify bool
+	token              string
+	timeout            time.Duration
+	output             string
+
+	// dialOptions are added to the options built from the flags.
+	dialOptions []grpc.DialOption
+	conn        *grpc.ClientConn
+}
+
+func main() {
+	shutdown, err := tracing.Init(context.Background(), "bookctl")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bookctl:", err)
+		os.Exit(1)
+	}
+
+	a := &app{}
+	err = newRootCommand(a).Execute()
+	a.close()
+	shutdown(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bookctl:", errorMessage(err))
+		os.Exit(1)
+	}
+}
+
+func newRootCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "bookctl",
+		Short:         "Manage the books of a bookstore server",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			// Read here rather than as the flag default, which --help prints.
+			if a.token == "" {
+				a.token = os.Getenv("BOOKCTL_TOKEN")
+			}
+
+			switch a.output {
+			case formatTable, formatJSON, formatYAML:
+				return nil
+			default:
+				return fmt.Errorf("unknown output format %q, want table, json or yaml", a.output)
+			}
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&a.address, "addr", envOr("BOOKCTL_ADDR", defaultAddress), "server address (env BOOKCTL_ADDR)")
+	flags.BoolVar(&a.useTLS, "tls", false, "connect with TLS")
+	flags.StringVar(&a.caFile, "tls-ca", "", "PEM file with the CA certificates to trust instead of the system ones (implies --tls)")
+	flags.StringVar(&a.certFile, "tls-cert", "", "PEM file with the client certificate that authenticates bookctl (implies --tls)")
+	flags.StringVar(&a.keyFile, "tls-key", "", "PEM file with the key of --tls-cert")
+	flags.StringVar(&a.serverName, "tls-server-name", "", "server name to verify the certificate against (implies --tls)")
+	flags.BoolVar(&a.insecureSkipVerify, "tls-skip-verify", false, "do not verify the server certificate (implies --tls)")
+	flags.StringVar(&a.token, "token", "", "bearer token sent in the authorization metadata (env BOOKCTL_TOKEN)")
+	flags.DurationVar(&a.timeout, "timeout", defaultTimeout, "deadline of each command, 0 for none")
+	flags.StringVarP(&a.output, "output", "o", formatTable, "output format: table, json or yaml")
+
+	cmd.AddCommand(
+		newCreateCommand(a),
+		newGetCommand(a),
+		newListCommand(a),
+		newUpdateCommand(a),
+		newDeleteCommand(a),
+		newSearchCommand(a),
+	)
+
+	return cmd
+}
+
+// client connects on first use; gRPC dials lazily, so this does not fail
+// when the server is down.
+func (a *app) client() (pb.BookServiceClient, error) {
+	if a.conn == nil {
+		creds, err := a.transportCredentials()
+		if err != nil {
+			return nil, err
+		}
+
+		opts := append([]grpc.DialOption{
+			grpc.WithTransportCredentials(creds),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		}, a.dialOptions...)
+		a.conn, err = grpc.NewClient(a.address, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pb.NewBookServiceClient(a.conn), nil
+}
+
+func (a *app) transportCredentials() (credentials.TransportCredentials, error) {
+	if !a.useTLS && a.caFile == "" && a.certFile == "" && a.serverName == "" && !a.insecureSkipVerify {
+		return insecure.NewCredentials(), nil
+	}
+
+	config := &tls.Config{
+		ServerName:         a.serverName,
+		InsecureSkipVerify: a.insecureSkipVerify,
+	}
+	if a.caFile != "" {
+		pem, err := os.ReadFile(a.caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", a.caFile)
+		}
+	}
+	if a.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(a.certFile, a.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(config), nil
+}
+
+// context returns the context of one command: with the deadline from
+// --timeout and the token in the outgoing metadata.
+func (a *app) context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if a.timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, a.timeout)
+	}
+	if a.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+a.token)
+	}
+
+	return ctx, cancel
+}
+
+func (a *app) close() {
+	if a.conn != nil {
+		a.conn.Close()
+	}
+}
+
+// errorMessage drops the "rpc error: code = ... desc =" wrapping of status
+// errors.
+func errorMessage(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return fmt.Sprintf("%s: %s", s.Code(), s.Message())
+	}
+
+	return err.Error()
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
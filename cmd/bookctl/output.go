@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// printBooks writes books as a table with a row per book, or as a JSON or
+// YAML list.
+func printBooks(w io.Writer, format string, books []*pb.Book) error {
+	if format != formatTable {
+		return encode(w, format, books)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tAUTHOR\tPRICE\tYEAR\tRATING")
+	for _, book := range books {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			book.Id, book.Title, book.Author, price(book), year(book), rating(book))
+	}
+
+	return tw.Flush()
+}
+
+// printBook writes one book with all its fields, a line per field in the
+// table format.
+func printBook(w io.Writer, format string, book *pb.Book) error {
+	if format != formatTable {
+		return encode(w, format, book)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, field := range [][2]string{
+		{"ID", book.Id},
+		{"Title", book.Title},
+		{"Author", book.Author},
+		{"Price", price(book)},
+		{"ISBN", book.Isbn},
+		{"Publisher", book.Publisher},
+		{"Year", year(book)},
+		{"Language", book.Language},
+		{"Pages", fmt.Sprint(book.PageCount)},
+		{"Rating", rating(book)},
+		{"Authors", strings.Join(book.AuthorIds, ", ")},
+		{"Categories", strings.Join(book.CategoryIds, ", ")},
+		{"Tags", strings.Join(book.Tags, ", ")},
+		{"Cover", book.CoverUrl},
+		{"Description", book.Description},
+	} {
+		fmt.Fprintf(tw, "%s:\t%s\n", field[0], field[1])
+	}
+
+	return tw.Flush()
+}
+
+// price shows the list price and, when a rule discounts it, the price
+// after the discount.
+func price(book *pb.Book) string {
+	if book.ListPrice == nil {
+		return ""
+	}
+	if book.PriceRuleId != "" {
+		return fmt.Sprintf("%s, sale %s", money.Format(book.ListPrice), money.Format(book.EffectivePrice))
+	}
+
+	return money.Format(book.ListPrice)
+}
+
+func year(book *pb.Book) string {
+	if book.PublicationYear == 0 {
+		return ""
+	}
+
+	return fmt.Sprint(book.PublicationYear)
+}
+
+func rating(book *pb.Book) string {
+	if book.RatingCount == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%.1f (%d)", book.RatingAverage, book.RatingCount)
+}
+
+// encode writes a book or a list of books in the protobuf JSON mapping,
+// or as the equivalent YAML.
+func encode(w io.Writer, format string, v any) error {
+	data, err := marshal(v)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return err
+		}
+		out.WriteByte('\n')
+		_, err := out.WriteTo(w)
+		return err
+	case formatYAML:
+		// JSON is YAML: decoding it into a node keeps the field order of
+		// the JSON mapping, and dropping the flow styles prints it as
+		// block YAML.
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		blockStyle(&node)
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func marshal(v any) ([]byte, error) {
+	books, ok := v.([]*pb.Book)
+	if !ok {
+		return protojson.Marshal(v.(*pb.Book))
+	}
+
+	list := make([]json.RawMessage, len(books))
+	for i, book := range books {
+		var err error
+		if list[i], err = protojson.Marshal(book); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(list)
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
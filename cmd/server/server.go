@@ -1,30 +1,324 @@
 package main
 
 import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/cache"
 	"bookstoregrpc/database"
+	"bookstoregrpc/gateway"
+	"bookstoregrpc/idempotency"
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/order"
+	"bookstoregrpc/outbox"
 	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"bookstoregrpc/review"
 	"bookstoregrpc/service"
+	"bookstoregrpc/tracing"
+	"bookstoregrpc/webhook"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"log"
-	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"gorm.io/driver/postgres"
 )
 
 func main() {
-	db := database.InitDB()
-	ps := service.NewPostgresStore(db)
+	ctx := context.Background()
+
+	shutdown, err := tracing.Init(ctx, "bookstore-server")
+	if err != nil {
+		log.Fatal("Cannot init tracing ", err)
+	}
+	defer shutdown(ctx)
+
+	shutdownMetrics, err := tracing.InitMetrics(ctx, "bookstore-server")
+	if err != nil {
+		log.Fatal("Cannot init metrics ", err)
+	}
+	defer shutdownMetrics(ctx)
+
+	opts := database.DefaultOptions()
+	for _, dsn := range replicaDSNs() {
+		opts.Replicas = append(opts.Replicas, postgres.Open(dsn))
+	}
+	handle, err := database.Open(ctx, postgres.Open(database.EnvDSN()), opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer handle.Close()
+	go handle.Watch(ctx, 10*time.Second)
+
+	db := handle.DB
+	webhooks := webhook.NewStore(db, webhook.StoreOptions{Admins: audit.ParsePrincipals(os.Getenv("WEBHOOK_ADMINS"))})
+	dispatcher := webhook.NewDispatcher(webhooks, webhook.DefaultDispatcherOptions())
+	go dispatcher.Run(ctx)
+	sinks := append(outboxSinks(), dispatcher)
+	for _, sink := range sinks {
+		go outbox.NewRelay(db, sink, outbox.DefaultRelayOptions()).Run(ctx)
+	}
+
+	keys := idempotency.NewStore(db, idempotencyTTL())
+	go keys.Run(ctx, time.Hour)
+
+	ps := service.NewCachedStore(service.NewPostgresStore(db), bookCache(), bookCacheTTL())
 	BookServer := service.NewBookServer(ps)
+	reviews := review.NewStore(db, audit.ParsePrincipals(os.Getenv("REVIEW_MODERATORS")))
+	reviews.OnRatingChange(ps.Invalidate)
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			audit.UnaryServerInterceptor(audit.PeerCertificate),
+			database.UnaryServerInterceptor(maxReplicaLag(), writeMethods...),
+			idempotency.UnaryServerInterceptor(keys, "/BookService/CreateBook", "/BookService/UpdateBook", "/BookService/DeleteBook"),
+		),
+		grpc.ChainStreamInterceptor(audit.StreamServerInterceptor(audit.PeerCertificate), database.StreamServerInterceptor(maxReplicaLag(), writeMethods...)),
+	)
 	pb.RegisterBookServiceServer(grpcServer, BookServer)
+	pb.RegisterAuditServiceServer(grpcServer, service.NewAuditServer(audit.NewStore(db)))
+	pb.RegisterWebhookServiceServer(grpcServer, service.NewWebhookServer(webhooks))
+	pb.RegisterAuthorServiceServer(grpcServer, service.NewAuthorServer(service.NewPostgresAuthorStore(db)))
+	pb.RegisterCategoryServiceServer(grpcServer, service.NewCategoryServer(service.NewPostgresCategoryStore(db)))
+	pb.RegisterInventoryServiceServer(grpcServer, service.NewInventoryServer(inventory.NewStore(db)))
+	pb.RegisterOrderServiceServer(grpcServer, service.NewOrderServer(order.NewStore(db)))
+	pb.RegisterReviewServiceServer(grpcServer, service.NewReviewServer(reviews))
+	pb.RegisterPricingServiceServer(grpcServer, service.NewPricingServer(pricing.NewStore(db)))
 
-	address := "0.0.0.0:8080"
-	listener, err := net.Listen("tcp", address)
+	webHandler, err := gateway.NewWebHandler(grpcServer, allowedOrigins())
 	if err != nil {
-		log.Fatal("Cannot start server", err)
+		log.Fatal("Cannot create gRPC-Web handler ", err)
 	}
 
-	if err := grpcServer.Serve(listener); err != nil {
+	tlsConfig, err := serverTLSConfig()
+	if err != nil {
+		log.Fatal("Cannot load TLS config ", err)
+	}
+
+	go runGateway(ctx, "localhost:8080", "0.0.0.0:8081", gatewayCredentials(tlsConfig))
+
+	server := &http.Server{
+		Addr:      "0.0.0.0:8080",
+		Handler:   webHandler,
+		Protocols: gateway.Protocols(),
+		TLSConfig: tlsConfig,
+	}
+
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil {
 		log.Fatal("Cannot start server", err)
 	}
 }
+
+// serverTLSConfig reads TLS_CERT_FILE and TLS_KEY_FILE, the certificate
+// the server listens with, and TLS_CLIENT_CA_FILE, the CAs whose client
+// certificates authenticate callers. Without TLS_CERT_FILE the server
+// listens in plaintext and every caller is anonymous.
+func serverTLSConfig() (*tls.Config, error) {
+	certFile := os.Getenv("TLS_CERT_FILE")
+	if certFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, os.Getenv("TLS_KEY_FILE"))
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if caFile := os.Getenv("TLS_CLIENT_CA_FILE"); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in " + caFile)
+		}
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return config, nil
+}
+
+// gatewayCredentials returns the credentials the gateway dials the server
+// with. The gateway runs in the same process, so over TLS it trusts
+// exactly the server's own certificate. It sends no client certificate:
+// REST callers are anonymous.
+func gatewayCredentials(server *tls.Config) credentials.TransportCredentials {
+	if server == nil {
+		return insecure.NewCredentials()
+	}
+
+	leaf := server.Certificates[0].Certificate[0]
+	return credentials.NewTLS(&tls.Config{
+		// The certificate is pinned below instead of verified against a
+		// CA and host name.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 || !bytes.Equal(state.PeerCertificates[0].Raw, leaf) {
+				return errors.New("gateway: unexpected server certificate")
+			}
+			return nil
+		},
+	})
+}
+
+// allowedOrigins reads CORS_ALLOWED_ORIGINS, a comma-separated list of
+// origins allowed to call the API from a browser.
+func allowedOrigins() []string {
+	origins := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if origins == "" {
+		return []string{"*"}
+	}
+
+	return strings.Split(origins, ",")
+}
+
+// writeMethods are the calls that change data. They return a session
+// token, which makes the caller's next reads go to the primary.
+var writeMethods = []string{
+	"/BookService/CreateBook",
+	"/BookService/UpdateBook",
+	"/BookService/DeleteBook",
+	"/BookService/BatchCreateBooks",
+	"/BookService/BatchUpdateBooks",
+	"/BookService/BatchDeleteBooks",
+	"/BookService/ImportBooks",
+	"/BookService/BookSession",
+	"/BookService/RollbackBook",
+	"/AuthorService/CreateAuthor",
+	"/AuthorService/UpdateAuthor",
+	"/AuthorService/DeleteAuthor",
+	"/CategoryService/CreateCategory",
+	"/CategoryService/UpdateCategory",
+	"/CategoryService/DeleteCategory",
+	"/InventoryService/AdjustStock",
+	"/InventoryService/ReserveStock",
+	"/InventoryService/ReleaseReservation",
+	"/InventoryService/SetLowStockThreshold",
+	"/OrderService/CreateCart",
+	"/OrderService/SetCartItem",
+	"/OrderService/PlaceOrder",
+	"/OrderService/PayOrder",
+	"/OrderService/ShipOrder",
+	"/OrderService/CancelOrder",
+	"/ReviewService/CreateReview",
+	"/ReviewService/UpdateReview",
+	"/ReviewService/DeleteReview",
+	"/ReviewService/ModerateReview",
+	"/PricingService/CreatePriceRule",
+	"/PricingService/DeletePriceRule",
+	"/WebhookService/CreateWebhook",
+	"/WebhookService/DeleteWebhook",
+}
+
+// replicaDSNs reads DB_REPLICA_DSNS, a comma-separated list of read
+// replica DSNs.
+func replicaDSNs() []string {
+	dsns := os.Getenv("DB_REPLICA_DSNS")
+	if dsns == "" {
+		return nil
+	}
+
+	return strings.Split(dsns, ",")
+}
+
+// maxReplicaLag reads REPLICA_MAX_LAG, how long after a write a client
+// sending its session token reads from the primary.
+func maxReplicaLag() time.Duration {
+	lag, err := time.ParseDuration(os.Getenv("REPLICA_MAX_LAG"))
+	if err != nil || lag <= 0 {
+		return database.DefaultMaxReplicaLag
+	}
+
+	return lag
+}
+
+// bookCache returns the cache of ReadBook: Redis at REDIS_ADDR (with
+// REDIS_PASSWORD) when it is set, otherwise an in-process LRU of
+// BOOK_CACHE_SIZE books.
+func bookCache() cache.Cache {
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		opts := cache.DefaultRedisOptions()
+		opts.Password = os.Getenv("REDIS_PASSWORD")
+		return cache.NewRedis(addr, opts)
+	}
+
+	size, err := strconv.Atoi(os.Getenv("BOOK_CACHE_SIZE"))
+	if err != nil || size <= 0 {
+		size = 10000
+	}
+
+	return cache.NewLRU(size, 64<<20)
+}
+
+// bookCacheTTL reads BOOK_CACHE_TTL, how long a book stays cached.
+func bookCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("BOOK_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		return service.DefaultBookCacheTTL
+	}
+
+	return ttl
+}
+
+// idempotencyTTL reads IDEMPOTENCY_TTL, how long an idempotency key is
+// remembered, e.g. "12h".
+func idempotencyTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil || ttl <= 0 {
+		return idempotency.DefaultTTL
+	}
+
+	return ttl
+}
+
+// outboxSinks returns the sinks configured by OUTBOX_WEBHOOK_URL and
+// OUTBOX_FILE.
+func outboxSinks() []outbox.Sink {
+	var sinks []outbox.Sink
+	if url := os.Getenv("OUTBOX_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, outbox.NewWebhookSink(url))
+	}
+	if path := os.Getenv("OUTBOX_FILE"); path != "" {
+		sinks = append(sinks, outbox.NewFileSink(path))
+	}
+
+	return sinks
+}
+
+func runGateway(ctx context.Context, grpcAddress, httpAddress string, creds credentials.TransportCredentials) {
+	conn, err := grpc.NewClient(grpcAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		log.Fatal("Cannot connect gateway to gRPC server ", err)
+	}
+	defer conn.Close()
+
+	handler, err := gateway.NewHandler(ctx, conn)
+	if err != nil {
+		log.Fatal("Cannot create gateway ", err)
+	}
+
+	if err := http.ListenAndServe(httpAddress, handler); err != nil {
+		log.Fatal("Cannot start gateway ", err)
+	}
+}
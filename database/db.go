@@ -1,33 +1,281 @@
 package database
 
 import (
-	"bookstoregrpc/pb"
+	"bookstoregrpc/audit"
+	"bookstoregrpc/idempotency"
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
+	"bookstoregrpc/order"
+	"bookstoregrpc/outbox"
+	"bookstoregrpc/pricing"
+	"bookstoregrpc/review"
+	"bookstoregrpc/revision"
+	"bookstoregrpc/webhook"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+	otelgorm "gorm.io/plugin/opentelemetry/tracing"
 )
 
-var db *gorm.DB
-
-func InitDB() *gorm.DB {
-	dsn := fmt.Sprintf("host=db user=%s password=%s dbname=%s port=5432 sslmode=disable",
+// EnvDSN returns the DSN of the primary database, configured by DB_USER,
+// DB_PASSWORD and DB_NAME.
+func EnvDSN() string {
+	return fmt.Sprintf("host=db user=%s password=%s dbname=%s port=5432 sslmode=disable",
 		os.Getenv("DB_USER"),
 		os.Getenv("DB_PASSWORD"),
 		os.Getenv("DB_NAME"),
 	)
-	var err error
+}
+
+// RetryOptions bounds how long InitDB waits for the database to accept
+// connections.
+type RetryOptions struct {
+	// MinBackoff and MaxBackoff bound the delay between attempts; the
+	// delay doubles after every failure.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxWait is how long to keep trying before giving up.
+	MaxWait time.Duration
+}
+
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+		MaxWait:    2 * time.Minute,
+	}
+}
+
+// Retry calls fn until it succeeds, ctx is done or opts.MaxWait has
+// passed, and returns the last error of fn.
+func Retry(ctx context.Context, opts RetryOptions, fn func() error) error {
+	deadline := time.Now().Add(opts.MaxWait)
+	backoff := opts.MinBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		wait := min(backoff, time.Until(deadline))
+		if wait <= 0 {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		log.Printf("database not ready (attempt %d): %v, retrying in %s", attempt, err, wait)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, opts.MaxBackoff)
+	}
+}
+
+// DB is an open database: the primary and its read replicas. It owns
+// their connection pools until Close.
+type DB struct {
+	*gorm.DB
+	opts  Options
+	pools []*sql.DB
+}
 
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// PoolOptions configure the connection pool of the primary and of every
+// replica. Zero values keep the database/sql defaults.
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+	ConnMaxLifetime time.Duration
+}
+
+type Options struct {
+	// Replicas receive the reads (see UseReplicas).
+	Replicas []gorm.Dialector
+	// Logger and NamingStrategy are passed to gorm; nil keeps its
+	// defaults.
+	Logger         logger.Interface
+	NamingStrategy schema.Namer
+	Pool           PoolOptions
+	Retry          RetryOptions
+	// Migrations run in order on the primary once it is connected.
+	Migrations []func(*gorm.DB) error
+}
+
+func DefaultOptions() Options {
+	return Options{
+		// Connections broken by a database restart are dropped by
+		// database/sql when they fail; recycling them bounds how long a
+		// half-open connection can stay in the pool.
+		Pool: PoolOptions{
+			MaxIdleConns:    10,
+			ConnMaxIdleTime: 5 * time.Minute,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Retry:      DefaultRetryOptions(),
+		Migrations: []func(*gorm.DB) error{Migrate},
+	}
+}
+
+// Open connects to the primary database, retrying while it is starting,
+// and runs the migrations.
+func Open(ctx context.Context, primary gorm.Dialector, opts Options) (*DB, error) {
+	var db *DB
+	err := Retry(ctx, opts.Retry, func() error {
+		var err error
+		db, err = connect(primary, opts)
+		return err
+	})
 	if err != nil {
-		log.Fatal("Could not connect to DB", err)
+		return nil, fmt.Errorf("could not connect to DB: %w", err)
 	}
 
-	if err := db.AutoMigrate(&pb.Book{}); err != nil {
-		log.Fatal("Error when creating DB", err)
+	if err := db.Use(otelgorm.NewPlugin(otelgorm.WithoutMetrics())); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not enable DB tracing: %w", err)
+	}
+
+	migrator := db.WithContext(UsePrimary(ctx))
+	for _, migrate := range opts.Migrations {
+		if err := migrate(migrator); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("could not migrate DB: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+func connect(primary gorm.Dialector, opts Options) (*DB, error) {
+	config := &gorm.Config{
+		TranslateError: true,
+		Logger:         opts.Logger,
+		NamingStrategy: opts.NamingStrategy,
+	}
+	db := &DB{opts: opts}
+
+	for _, dialector := range append([]gorm.Dialector{primary}, opts.Replicas...) {
+		conn, err := gorm.Open(dialector, config)
+		// gorm keeps the pool open when the first ping fails.
+		if conn != nil {
+			if pool, poolErr := conn.DB(); poolErr == nil {
+				opts.Pool.apply(pool)
+				db.pools = append(db.pools, pool)
+			}
+		}
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		if db.DB == nil {
+			db.DB = conn
+		}
+	}
+
+	if err := useReplicas(db.DB, opts.Replicas, db.pools[1:]); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("replicas: %w", err)
+	}
+
+	return db, nil
+}
+
+func (o PoolOptions) apply(pool *sql.DB) {
+	if o.MaxOpenConns > 0 {
+		pool.SetMaxOpenConns(o.MaxOpenConns)
+	}
+	if o.MaxIdleConns > 0 {
+		pool.SetMaxIdleConns(o.MaxIdleConns)
+	}
+	if o.ConnMaxIdleTime > 0 {
+		pool.SetConnMaxIdleTime(o.ConnMaxIdleTime)
+	}
+	if o.ConnMaxLifetime > 0 {
+		pool.SetConnMaxLifetime(o.ConnMaxLifetime)
+	}
+}
+
+// Close closes the connection pools of the primary and the replicas.
+func (db *DB) Close() error {
+	var errs []error
+	for _, pool := range db.pools {
+		errs = append(errs, pool.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Watch pings the primary every interval until ctx is done. When a ping
+// fails it drops the idle connections, which may have been broken by a
+// database restart or failover, so that the next queries open new ones.
+func (db *DB) Watch(ctx context.Context, interval time.Duration) {
+	pool := db.pools[0]
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := pool.PingContext(pingCtx)
+		cancel()
+
+		switch {
+		case err != nil && healthy:
+			log.Printf("database connection lost: %v", err)
+			pool.SetMaxIdleConns(0)
+			healthy = false
+		case err == nil && !healthy:
+			log.Printf("database connection restored")
+			pool.SetMaxIdleConns(max(db.opts.Pool.MaxIdleConns, 2))
+			healthy = true
+		}
+	}
+}
+
+// Migrate creates or updates every table the service uses.
+func Migrate(db *gorm.DB) error {
+	models := model.Models()
+	models = append(models, outbox.Models()...)
+	models = append(models, webhook.Models()...)
+	models = append(models, audit.Models()...)
+	models = append(models, revision.Models()...)
+	models = append(models, inventory.Models()...)
+	models = append(models, order.Models()...)
+	models = append(models, review.Models()...)
+	models = append(models, pricing.Models()...)
+	models = append(models, idempotency.Models()...)
+
+	if err := db.AutoMigrate(models...); err != nil {
+		return err
+	}
+
+	err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn ON books (isbn) WHERE isbn <> ''").Error
+	if err != nil {
+		return err
 	}
 
-	return db
+	// Rows written before prices had a currency only have the integer price.
+	return db.Model(&model.Book{}).
+		Where("price_currency IS NULL OR price_currency = ''").
+		Updates(map[string]any{
+			"price_currency": money.DefaultCurrency,
+			"price_amount":   gorm.Expr("price"),
+		}).Error
 }
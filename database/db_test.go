@@ -0,0 +1,85 @@
+package database_test
+
+import (
+	"bookstoregrpc/database"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	opts := database.RetryOptions{MinBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, MaxWait: time.Second}
+	errDown := errors.New("connection refused")
+
+	t.Run("Succeeds", func(t *testing.T) {
+		attempts := 0
+		err := database.Retry(ctx, opts, func() error {
+			attempts++
+			if attempts < 4 {
+				return errDown
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("Max wait", func(t *testing.T) {
+		opts := opts
+		opts.MaxWait = 20 * time.Millisecond
+
+		start := time.Now()
+		err := database.Retry(ctx, opts, func() error { return errDown })
+		assert.ErrorIs(t, err, errDown)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := database.Retry(ctx, opts, func() error { return errDown })
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, errDown)
+	})
+}
+
+func TestOpen_unreachable(t *testing.T) {
+	t.Parallel()
+	opts := database.DefaultOptions()
+	opts.Retry = database.RetryOptions{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, MaxWait: 50 * time.Millisecond}
+
+	db, err := database.Open(context.Background(), postgres.Open("host=127.0.0.1 port=1 user=bookstore dbname=bookstore sslmode=disable connect_timeout=1"), opts)
+	assert.Error(t, err)
+	assert.Nil(t, db)
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	first := dbtest.New(t)
+	assert.NoError(t, first.Create(&model.Book{ID: "karamazov", Title: "Братья Карамазовы"}).Error)
+
+	// A second database in the same process is independent of the first.
+	second := dbtest.New(t)
+	var count int64
+	assert.NoError(t, second.Model(&model.Book{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+	assert.NoError(t, first.Model(&model.Book{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	opts := database.DefaultOptions()
+	opts.Migrations = nil
+	assert.False(t, dbtest.Open(t, opts).Migrator().HasTable(&model.Book{}))
+
+	assert.NoError(t, first.Close())
+	assert.Error(t, first.Exec("SELECT 1").Error)
+}
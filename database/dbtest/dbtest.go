@@ -0,0 +1,37 @@
+// Package dbtest opens isolated, migrated SQLite databases for tests.
+package dbtest
+
+import (
+	"bookstoregrpc/database"
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+)
+
+// New returns an in-memory database private to t and migrated with
+// database.Migrate. It is closed when t finishes.
+func New(t testing.TB) *database.DB {
+	return Open(t, database.DefaultOptions())
+}
+
+// Open is New with opts, e.g. to change the migrations or the logger.
+// Connections are not retried.
+func Open(t testing.TB, opts database.Options) *database.DB {
+	t.Helper()
+
+	opts.Retry = database.RetryOptions{}
+	// Every connection to a private in-memory database gets a new, empty
+	// database, so the pool keeps exactly one that never expires.
+	opts.Pool = database.PoolOptions{MaxOpenConns: 1, MaxIdleConns: 1}
+	dsn := "file:testdb_" + strings.ReplaceAll(t.Name(), "/", "_") + "?mode=memory&cache=private"
+
+	db, err := database.Open(context.Background(), sqlite.Open(dsn), opts)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type primaryKey struct{}
+
+// UsePrimary returns a context whose queries all go to the primary, for
+// callers that must see their own recent writes.
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// useReplicas sends the reads of db to the replicas, chosen at random, and
+// keeps writes, transactions and locking reads on the primary. Reads with
+// a UsePrimary context also stay on the primary. pools are the open
+// pools of the replicas, owned by the caller.
+func useReplicas(db *gorm.DB, replicas []gorm.Dialector, pools []*sql.DB) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	opened := make([]gorm.Dialector, len(replicas))
+	for i, replica := range replicas {
+		opened[i] = openedDialector{Dialector: replica, pool: pools[i]}
+	}
+	err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: opened,
+		Policy:   dbresolver.RandomPolicy{},
+	}))
+	if err != nil {
+		return err
+	}
+
+	readYourWrites := func(tx *gorm.DB) {
+		if tx.Statement.Context != nil && usesPrimary(tx.Statement.Context) {
+			dbresolver.Write.ModifyStatement(tx.Statement)
+		}
+	}
+	if err := db.Callback().Query().After("gorm:db_resolver").Before("gorm:query").Register("bookstore:read_your_writes", readYourWrites); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("gorm:db_resolver").Before("gorm:row").Register("bookstore:read_your_writes", readYourWrites); err != nil {
+		return err
+	}
+
+	return db.Callback().Raw().After("gorm:db_resolver").Before("gorm:raw").Register("bookstore:read_your_writes", readYourWrites)
+}
+
+// openedDialector hands an already open pool to dbresolver, which would
+// otherwise open its own that nothing closes.
+type openedDialector struct {
+	gorm.Dialector
+	pool gorm.ConnPool
+}
+
+func (d openedDialector) Initialize(db *gorm.DB) error {
+	db.ConnPool = d.pool
+	return nil
+}
@@ -0,0 +1,128 @@
+package database_test
+
+import (
+	"bookstoregrpc/database"
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// initReplicatedDB returns a primary with one replica that never catches
+// up: both are migrated, but rows written to the primary stay there.
+func initReplicatedDB(t *testing.T) *gorm.DB {
+	dir := t.TempDir()
+	primary := sqlite.Open(filepath.Join(dir, "primary.db"))
+	replica := sqlite.Open(filepath.Join(dir, "replica.db"))
+
+	opts := database.DefaultOptions()
+	opts.Retry = database.RetryOptions{}
+
+	replicaDB, err := database.Open(context.Background(), replica, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, replicaDB.Close())
+
+	opts.Replicas = []gorm.Dialector{replica}
+	db, err := database.Open(context.Background(), primary, opts)
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db.DB
+}
+
+func TestUseReplicas(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initReplicatedDB(t)
+
+	assert.NoError(t, db.WithContext(ctx).Create(&model.Book{ID: "karamazov", Title: "Братья Карамазовы"}).Error)
+
+	var count int64
+	assert.NoError(t, db.WithContext(ctx).Model(&model.Book{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count, "reads go to the replica")
+
+	assert.NoError(t, db.WithContext(database.UsePrimary(ctx)).Model(&model.Book{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&model.Book{}).Count(&count).Error
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count, "transactions stay on the primary")
+}
+
+func TestSessionToken(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initReplicatedDB(t)
+
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(database.UnaryServerInterceptor(time.Minute, "/BookService/CreateBook", "/BookService/DeleteBook")),
+		grpc.StreamInterceptor(database.StreamServerInterceptor(time.Minute, "/BookService/ImportBooks")),
+	)
+	pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+	go s.Serve(listener)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	books := pb.NewBookServiceClient(conn)
+
+	var header metadata.MD
+	_, err = books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: "karamazov", Title: "Братья Карамазовы"}}, grpc.Header(&header))
+	assert.NoError(t, err)
+	token := header.Get(database.SessionTokenKey)
+	assert.Len(t, token, 1)
+
+	_, err = books.ReadBook(ctx, &pb.ReadBookRequest{Id: "karamazov"})
+	assert.Error(t, err, "the replica has not caught up")
+
+	header = nil
+	withToken := metadata.AppendToOutgoingContext(ctx, database.SessionTokenKey, token[0])
+	res, err := books.ReadBook(withToken, &pb.ReadBookRequest{Id: "karamazov"}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Equal(t, "Братья Карамазовы", res.GetBook().GetTitle())
+	assert.Empty(t, header.Get(database.SessionTokenKey), "reads do not return a token")
+
+	stale := database.NewSessionToken(time.Now().Add(-time.Hour))
+	_, err = books.ReadBook(metadata.AppendToOutgoingContext(ctx, database.SessionTokenKey, stale), &pb.ReadBookRequest{Id: "karamazov"})
+	assert.Error(t, err)
+
+	_, err = books.ReadBook(metadata.AppendToOutgoingContext(ctx, database.SessionTokenKey, "garbage"), &pb.ReadBookRequest{Id: "karamazov"})
+	assert.Error(t, err)
+
+	forged := database.NewSessionToken(time.Now().Add(24 * time.Hour))
+	_, err = books.ReadBook(metadata.AppendToOutgoingContext(ctx, database.SessionTokenKey, forged), &pb.ReadBookRequest{Id: "karamazov"})
+	assert.Error(t, err, "tokens from the future are ignored")
+
+	header = nil
+	_, err = books.DeleteBook(ctx, &pb.DeleteBookRequest{Id: "missing"}, grpc.Header(&header))
+	assert.Error(t, err)
+	assert.Empty(t, header.Get(database.SessionTokenKey), "failed writes do not return a token")
+
+	header = nil
+	_, err = books.ListTags(ctx, &pb.ListTagsRequest{}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Empty(t, header.Get(database.SessionTokenKey), "only the listed methods are writes")
+}
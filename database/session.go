@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// SessionTokenKey is the metadata key of the session token. Calls that
+	// change data return a token in this header (in the trailer for
+	// streaming calls); sending it back with later calls makes them read
+	// from the primary while replicas may still lag behind.
+	SessionTokenKey = "x-session-token"
+
+	DefaultMaxReplicaLag = 5 * time.Second
+
+	// maxClockSkew is how far in the future a token may be dated, for
+	// tokens issued by another server instance.
+	maxClockSkew = time.Second
+)
+
+// NewSessionToken returns the token of a write made at t.
+//
+// The token only records when the write happened, so reading from the
+// primary for maxLag after it is read-your-writes only while the replicas
+// lag less than that.
+func NewSessionToken(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+// sessionContext marks ctx to read from the primary when the incoming
+// session token is younger than maxLag. Tokens dated in the future are
+// ignored, so a forged one cannot keep a client on the primary.
+func sessionContext(ctx context.Context, maxLag time.Duration) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(SessionTokenKey)
+	if len(values) == 0 {
+		return ctx
+	}
+
+	millis, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return ctx
+	}
+	age := time.Since(time.UnixMilli(millis))
+	if age < -maxClockSkew || age >= maxLag {
+		return ctx
+	}
+
+	return UsePrimary(ctx)
+}
+
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+
+	return set
+}
+
+// UnaryServerInterceptor gives read-your-writes consistency to clients
+// that send back the session token returned by their last write. Successful
+// calls of the writes methods (full method names) return a token.
+func UnaryServerInterceptor(maxLag time.Duration, writes ...string) grpc.UnaryServerInterceptor {
+	isWrite := methodSet(writes)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		res, err := handler(sessionContext(ctx, maxLag), req)
+		if err == nil && isWrite[info.FullMethod] {
+			grpc.SetHeader(ctx, metadata.Pairs(SessionTokenKey, NewSessionToken(time.Now())))
+		}
+
+		return res, err
+	}
+}
+
+func StreamServerInterceptor(maxLag time.Duration, writes ...string) grpc.StreamServerInterceptor {
+	isWrite := methodSet(writes)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: sessionContext(ss.Context(), maxLag)})
+		if err == nil && isWrite[info.FullMethod] {
+			ss.SetTrailer(metadata.Pairs(SessionTokenKey, NewSessionToken(time.Now())))
+		}
+
+		return err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
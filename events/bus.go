@@ -0,0 +1,164 @@
+package events
+
+import (
+	"bookstoregrpc/pb"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultHistorySize = 1024
+	DefaultBufferSize  = 64
+)
+
+var (
+	// ErrSequenceUnavailable means the events after the requested sequence
+	// are no longer kept in the history, or the sequence comes from another
+	// epoch, so the subscriber has to resync.
+	ErrSequenceUnavailable = errors.New("events after this sequence are not available")
+	// ErrSlowSubscriber closes a subscription whose buffer is full.
+	ErrSlowSubscriber = errors.New("subscriber is too slow")
+	ErrClosed         = errors.New("subscription closed")
+)
+
+// Bus fans book change events out to subscribers and keeps the last events
+// so that a subscriber can resume after a reconnect. Sequences are only
+// meaningful within the epoch of the bus, a random id it gets when it is
+// created.
+type Bus struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []*pb.BookEvent
+	size    int
+	subs    map[*Subscription]struct{}
+}
+
+func NewBus(historySize int) *Bus {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+
+	return &Bus{
+		epoch: uuid.New().String(),
+		size:  historySize,
+		subs:  make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next sequence number to the event and delivers it to
+// every subscriber without blocking. Subscribers that cannot keep up are
+// dropped with ErrSlowSubscriber. The event must not be modified afterwards.
+func (b *Bus) Publish(event *pb.BookEvent) *pb.BookEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.Sequence = b.seq
+	event.Epoch = b.epoch
+	if event.Time == nil {
+		event.Time = timestamppb.Now()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			b.remove(sub, ErrSlowSubscriber)
+		}
+	}
+
+	return event
+}
+
+// Subscribe returns a subscription receiving the events after since from
+// the history followed by new events. since == 0 skips the history;
+// otherwise epoch must be the epoch of the bus.
+func (b *Bus) Subscribe(since uint64, epoch string, buffer int) (*Subscription, error) {
+	if buffer <= 0 {
+		buffer = DefaultBufferSize
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if (since > 0 && epoch != b.epoch) || since > b.seq {
+		return nil, ErrSequenceUnavailable
+	}
+
+	var replay []*pb.BookEvent
+	if since > 0 && since < b.seq {
+		if b.history[0].Sequence > since+1 {
+			return nil, ErrSequenceUnavailable
+		}
+		replay = b.history[since+1-b.history[0].Sequence:]
+	}
+
+	sub := &Subscription{
+		bus: b,
+		ch:  make(chan *pb.BookEvent, len(replay)+buffer),
+	}
+	for _, event := range replay {
+		sub.ch <- event
+	}
+	b.subs[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// Epoch returns the epoch the sequence numbers of the bus belong to.
+func (b *Bus) Epoch() string {
+	return b.epoch
+}
+
+// Sequence returns the sequence number of the last published event.
+func (b *Bus) Sequence() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.seq
+}
+
+func (b *Bus) remove(sub *Subscription, err error) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.ch)
+}
+
+type Subscription struct {
+	bus *Bus
+	ch  chan *pb.BookEvent
+	// err is set under bus.mu before ch is closed.
+	err error
+}
+
+// Events is closed when the subscription ends; Err then tells why.
+func (s *Subscription) Events() <-chan *pb.BookEvent {
+	return s.ch
+}
+
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s, ErrClosed)
+}
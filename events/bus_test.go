@@ -0,0 +1,89 @@
+package events_test
+
+import (
+	"bookstoregrpc/events"
+	"bookstoregrpc/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishAndSubscribe(t *testing.T) {
+	bus := events.NewBus(3)
+
+	sub, err := bus.Subscribe(0, "", 10)
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	book := &pb.Book{Id: "1", Author: "test", Title: "test", Price: 100}
+	event := bus.Publish(&pb.BookEvent{Type: pb.BookEvent_CREATED, After: book})
+	assert.Equal(t, uint64(1), event.Sequence)
+
+	assert.Equal(t, bus.Epoch(), event.Epoch)
+
+	got := <-sub.Events()
+	assert.Equal(t, event, got)
+	assert.Equal(t, book, got.After)
+	assert.Nil(t, got.Before)
+}
+
+func TestResume(t *testing.T) {
+	bus := events.NewBus(3)
+	for range 5 {
+		bus.Publish(&pb.BookEvent{Type: pb.BookEvent_UPDATED, Before: &pb.Book{}, After: &pb.Book{}})
+	}
+	assert.Equal(t, uint64(5), bus.Sequence())
+
+	t.Run("Replay From History", func(t *testing.T) {
+		sub, err := bus.Subscribe(2, bus.Epoch(), 10)
+		assert.NoError(t, err)
+		defer sub.Close()
+
+		for _, seq := range []uint64{3, 4, 5} {
+			assert.Equal(t, seq, (<-sub.Events()).Sequence)
+		}
+	})
+
+	t.Run("Sequence Too Old", func(t *testing.T) {
+		_, err := bus.Subscribe(1, bus.Epoch(), 10)
+		assert.ErrorIs(t, err, events.ErrSequenceUnavailable)
+	})
+
+	t.Run("Sequence Ahead", func(t *testing.T) {
+		_, err := bus.Subscribe(42, bus.Epoch(), 10)
+		assert.ErrorIs(t, err, events.ErrSequenceUnavailable)
+	})
+
+	t.Run("Sequence From Before Restart", func(t *testing.T) {
+		// The new bus numbers its events from 1 again, so sequence 2 of the
+		// old one would replay the wrong events.
+		restarted := events.NewBus(3)
+		for range 5 {
+			restarted.Publish(&pb.BookEvent{Type: pb.BookEvent_UPDATED, Before: &pb.Book{}, After: &pb.Book{}})
+		}
+		assert.NotEqual(t, bus.Epoch(), restarted.Epoch())
+
+		_, err := restarted.Subscribe(4, bus.Epoch(), 10)
+		assert.ErrorIs(t, err, events.ErrSequenceUnavailable)
+		_, err = restarted.Subscribe(4, "", 10)
+		assert.ErrorIs(t, err, events.ErrSequenceUnavailable)
+	})
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	bus := events.NewBus(10)
+
+	sub, err := bus.Subscribe(0, "", 1)
+	assert.NoError(t, err)
+
+	bus.Publish(&pb.BookEvent{Type: pb.BookEvent_DELETED, Before: &pb.Book{}})
+	bus.Publish(&pb.BookEvent{Type: pb.BookEvent_DELETED, Before: &pb.Book{}})
+
+	<-sub.Events()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, sub.Err(), events.ErrSlowSubscriber)
+
+	sub.Close()
+	assert.ErrorIs(t, sub.Err(), events.ErrSlowSubscriber)
+}
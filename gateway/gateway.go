@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"bookstoregrpc/idempotency"
+	"bookstoregrpc/openapi"
+	"bookstoregrpc/pb"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+// NewHandler returns an HTTP handler translating REST/JSON requests into
+// calls of the bookstore services over conn. Streaming RPCs (GET /v1/books
+// and GET /v1/books:search) are written as newline-delimited JSON, one
+// {"result": {...}} object per line. The OpenAPI spec is served at
+// /openapi.json.
+func NewHandler(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(incomingHeader))
+
+	if err := pb.RegisterBookServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterWebhookServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterAuditServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterAuthorServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterCategoryServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterInventoryServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterOrderServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterReviewServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterPricingServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	err := mux.HandlePath(http.MethodGet, "/openapi.json", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi.Spec)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
+
+// incomingHeader forwards the Idempotency-Key header to the gRPC call in
+// addition to the headers runtime forwards by default.
+func incomingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, idempotency.Header) {
+		return idempotency.Header, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,165 @@
+package gateway_test
+
+import (
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/gateway"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newBookGRPCServer(t *testing.T) *grpc.Server {
+	db := dbtest.New(t).DB
+
+	s := grpc.NewServer()
+	pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+
+	return s
+}
+
+func startGateway(t *testing.T) *httptest.Server {
+	listener := bufconn.Listen(1024 * 1024)
+	s := newBookGRPCServer(t)
+	go s.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	handler, err := gateway.NewHandler(context.Background(), conn)
+	assert.NoError(t, err)
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		ts.Close()
+		conn.Close()
+		s.Stop()
+	})
+
+	return ts
+}
+
+func do(t *testing.T, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { res.Body.Close() })
+
+	return res
+}
+
+func TestBooksREST(t *testing.T) {
+	ts := startGateway(t)
+
+	books := []string{
+		`{"id": "1", "author": "Л. Н. Толстой", "title": "Война и мир", "price": 900}`,
+		`{"id": "2", "author": "Л. Н. Толстой", "title": "Анна Каренина", "price": 400}`,
+		`{"id": "3", "author": "А. П. Чехов", "title": "Чайка", "price": 300}`,
+	}
+	for _, book := range books {
+		res := do(t, http.MethodPost, ts.URL+"/v1/books", book)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	t.Run("Get Book", func(t *testing.T) {
+		res := do(t, http.MethodGet, ts.URL+"/v1/books/1", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body struct{ Book pb.Book }
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "Война и мир", body.Book.Title)
+	})
+
+	t.Run("Update Book", func(t *testing.T) {
+		res := do(t, http.MethodPatch, ts.URL+"/v1/books/3", `{"author": "А. П. Чехов", "title": "Вишнёвый сад", "price": 350}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body struct{ Book pb.Book }
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "Вишнёвый сад", body.Book.Title)
+	})
+
+	t.Run("Patch One Field", func(t *testing.T) {
+		res := do(t, http.MethodPatch, ts.URL+"/v1/books/1", `{"title": "Война и мир. Том 1"}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		res = do(t, http.MethodGet, ts.URL+"/v1/books/1", "")
+		var body struct{ Book pb.Book }
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "Война и мир. Том 1", body.Book.Title)
+		assert.Equal(t, "Л. Н. Толстой", body.Book.Author)
+		assert.Equal(t, int32(900), body.Book.Price)
+
+		res = do(t, http.MethodPatch, ts.URL+"/v1/books/1", `{"listPrice": {"currencyCode": "RUB", "units": "950"}}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		body.Book = pb.Book{}
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "Война и мир. Том 1", body.Book.Title)
+		assert.Equal(t, int32(950), body.Book.Price)
+
+		res = do(t, http.MethodPatch, ts.URL+"/v1/books/1?updateMask=rating", `{"title": "x"}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Search Books", func(t *testing.T) {
+		query := url.Values{"filter.author": {"Л. Н. Толстой"}, "filter.price": {"500"}}
+		res := do(t, http.MethodGet, ts.URL+"/v1/books:search?"+query.Encode(), "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var lines int
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			var line struct{ Result struct{ Book pb.Book } }
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			assert.Equal(t, "1", line.Result.Book.Id)
+			lines++
+		}
+		assert.Equal(t, 1, lines)
+	})
+
+	t.Run("Delete Book", func(t *testing.T) {
+		res := do(t, http.MethodDelete, ts.URL+"/v1/books/2", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		res = do(t, http.MethodGet, ts.URL+"/v1/books/2", "")
+		assert.NotEqual(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("List Books", func(t *testing.T) {
+		res := do(t, http.MethodGet, ts.URL+"/v1/books", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var lines int
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			lines++
+		}
+		assert.Equal(t, 2, lines)
+	})
+
+	t.Run("OpenAPI Spec", func(t *testing.T) {
+		res := do(t, http.MethodGet, ts.URL+"/openapi.json", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	})
+}
@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/database"
+	"bookstoregrpc/idempotency"
+	"net/http"
+
+	connectcors "connectrpc.com/cors"
+	"connectrpc.com/vanguard/vanguardgrpc"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+)
+
+// NewWebHandler serves every service registered on server over native gRPC,
+// gRPC-Web and the Connect protocol, so browsers can call BookService
+// without a separate proxy. Serve it with HTTP/1.1 and h2c enabled (see
+// Protocols) to accept all three on one port. Cross-origin requests are
+// allowed from allowedOrigins; "*" allows any origin.
+func NewWebHandler(server *grpc.Server, allowedOrigins []string) (http.Handler, error) {
+	// The transcoder turns Connect and gRPC-Web JSON into proto for the
+	// server (vanguard's JSON codec emits unpopulated fields and discards
+	// unknown ones), so nothing is added to the process-wide gRPC codecs.
+	transcoder, err := vanguardgrpc.NewTranscoder(server)
+	if err != nil {
+		return nil, err
+	}
+
+	c := cors.New(cors.Options{
+		AllowedOrigins: allowedOrigins,
+		AllowedMethods: connectcors.AllowedMethods(),
+		// Browsers may send and read the session token that sends their
+		// reads after a write to the primary, and read the request id.
+		AllowedHeaders: append(connectcors.AllowedHeaders(), idempotency.Header, database.SessionTokenKey),
+		ExposedHeaders: append(connectcors.ExposedHeaders(), database.SessionTokenKey, audit.RequestIDKey),
+		MaxAge:         7200,
+	})
+
+	return c.Handler(transcoder), nil
+}
+
+// Protocols returns the protocol set NewWebHandler needs: HTTP/1.1 for
+// gRPC-Web and Connect, and HTTP/2 for native gRPC, over TLS or
+// unencrypted (h2c).
+func Protocols() *http.Protocols {
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &protocols
+}
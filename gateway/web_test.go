@@ -0,0 +1,131 @@
+package gateway_test
+
+import (
+	"bookstoregrpc/gateway"
+	"bookstoregrpc/pb"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func startWeb(t *testing.T) *httptest.Server {
+	s := newBookGRPCServer(t)
+
+	handler, err := gateway.NewWebHandler(s, []string{"https://shop.example"})
+	assert.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(handler)
+	ts.Config.Protocols = gateway.Protocols()
+	ts.Start()
+	t.Cleanup(func() {
+		ts.Close()
+		s.Stop()
+	})
+
+	return ts
+}
+
+func TestWebProtocols(t *testing.T) {
+	ctx := context.Background()
+	ts := startWeb(t)
+
+	t.Run("Native gRPC over h2c", func(t *testing.T) {
+		conn, err := grpc.NewClient(strings.TrimPrefix(ts.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		client := pb.NewBookServiceClient(conn)
+		res, err := client.CreateBook(ctx, &pb.CreateBookRequest{
+			Book: &pb.Book{Id: "1", Author: "Н. В. Гоголь", Title: "Ревизор", Price: 400},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", res.GetId())
+	})
+
+	t.Run("Connect unary JSON", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/BookService/ReadBook", strings.NewReader(`{"id": "1"}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Connect-Protocol-Version", "1")
+
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Contains(t, string(body), "Ревизор")
+	})
+
+	streamingClients := map[string][]connect.ClientOption{
+		"Connect":  nil,
+		"gRPC-Web": {connect.WithGRPCWeb()},
+	}
+	for name, opts := range streamingClients {
+		t.Run(name+" server streaming", func(t *testing.T) {
+			client := connect.NewClient[emptypb.Empty, pb.ReadBooksResponse](http.DefaultClient, ts.URL+"/BookService/ReadBooks", opts...)
+
+			stream, err := client.CallServerStream(ctx, connect.NewRequest(&emptypb.Empty{}))
+			assert.NoError(t, err)
+			defer stream.Close()
+
+			var titles []string
+			for stream.Receive() {
+				titles = append(titles, stream.Msg().GetBook().GetTitle())
+			}
+			assert.NoError(t, stream.Err())
+			assert.Equal(t, []string{"Ревизор"}, titles)
+		})
+	}
+
+	t.Run("No global codec", func(t *testing.T) {
+		_, err := gateway.NewWebHandler(grpc.NewServer(), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, encoding.GetCodec("json"))
+	})
+
+	t.Run("CORS preflight", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodOptions, ts.URL+"/BookService/ReadBook", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Origin", "https://shop.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "connect-protocol-version,content-type,x-session-token")
+
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, "https://shop.example", res.Header.Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, res.Header.Get("Access-Control-Allow-Headers"), "x-session-token")
+
+		req.Header.Set("Origin", "https://evil.example")
+		res, err = http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		res.Body.Close()
+		assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("CORS exposed headers", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/BookService/ReadBook", strings.NewReader("{}"))
+		assert.NoError(t, err)
+		req.Header.Set("Origin", "https://shop.example")
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		res.Body.Close()
+		exposed := strings.ToLower(res.Header.Get("Access-Control-Expose-Headers"))
+		assert.Contains(t, exposed, "x-session-token")
+		assert.Contains(t, exposed, "x-request-id")
+	})
+}
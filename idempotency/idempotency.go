@@ -0,0 +1,248 @@
+// Package idempotency lets clients retry mutating RPCs safely: a call
+// carrying an idempotency-key header is executed once, and retries with
+// the same key get the original response.
+package idempotency
+
+import (
+	"bookstoregrpc/audit"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	Header = "idempotency-key"
+
+	DefaultTTL = 24 * time.Hour
+	// MaxKeyLength is the longest idempotency key accepted.
+	MaxKeyLength = 255
+)
+
+// Key is a used idempotency key. Keys are scoped to the principal of the
+// call. Response is empty while the first call is still running.
+type Key struct {
+	Principal   string `gorm:"primaryKey"`
+	Key         string `gorm:"primaryKey"`
+	Method      string
+	RequestHash []byte
+	Response    []byte
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+func (Key) TableName() string {
+	return "idempotency_keys"
+}
+
+func Models() []any {
+	return []any{&Key{}}
+}
+
+type Store struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewStore returns a store keeping keys for ttl after their first use.
+func NewStore(db *gorm.DB, ttl time.Duration) *Store {
+	return &Store{db: db, ttl: ttl}
+}
+
+// claim records key for a new call. It returns the existing key and false
+// if the key is already in use.
+func (s *Store) claim(ctx context.Context, key *Key) (*Key, bool, error) {
+	var existing Key
+	claimed := false
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Where("principal = ? AND key = ? AND expires_at <= ?", key.Principal, key.Key, now).
+			Delete(&Key{}).Error
+		if err != nil {
+			return err
+		}
+
+		key.ExpiresAt = now.Add(s.ttl)
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			claimed = true
+			return nil
+		}
+
+		return tx.Where("principal = ? AND key = ?", key.Principal, key.Key).First(&existing).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return key, true, nil
+	}
+
+	return &existing, false, nil
+}
+
+func (s *Store) finish(ctx context.Context, key *Key, response []byte) error {
+	return s.db.WithContext(ctx).Model(&Key{}).
+		Where("principal = ? AND key = ?", key.Principal, key.Key).
+		Update("response", response).Error
+}
+
+// release frees a key whose call failed, so that it can be retried.
+func (s *Store) release(ctx context.Context, key *Key) error {
+	return s.db.WithContext(ctx).
+		Where("principal = ? AND key = ? AND response IS NULL", key.Principal, key.Key).
+		Delete(&Key{}).Error
+}
+
+// Purge deletes the expired keys and returns how many were deleted.
+func (s *Store) Purge(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&Key{})
+	return result.RowsAffected, result.Error
+}
+
+// Run purges expired keys every interval until ctx is done.
+func (s *Store) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := s.Purge(ctx); err != nil {
+				log.Printf("idempotency purge: %v", err)
+			}
+		}
+	}
+}
+
+// UnaryServerInterceptor makes the given methods idempotent for calls with
+// an idempotency-key header. The first successful response is stored and
+// returned again to retries with the same key and request; a retry with a
+// different request fails with FailedPrecondition. Failed calls are not
+// stored. It must run after the audit interceptor, which sets the
+// principal the keys are scoped to.
+func UnaryServerInterceptor(store *Store, methods ...string) grpc.UnaryServerInterceptor {
+	idempotent := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		idempotent[method] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(Header)
+		if !idempotent[info.FullMethod] || len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+		if len(values[0]) > MaxKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "%s is longer than %d characters", Header, MaxKeyLength)
+		}
+
+		hash, err := requestHash(info.FullMethod, req)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		key, claimed, err := store.claim(ctx, &Key{
+			Principal:   audit.FromContext(ctx).Principal,
+			Key:         values[0],
+			Method:      info.FullMethod,
+			RequestHash: hash,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if !claimed {
+			return replay(key, hash)
+		}
+
+		res, err := handler(ctx, req)
+		if err != nil {
+			if err := store.release(context.WithoutCancel(ctx), key); err != nil {
+				log.Printf("idempotency release %q: %v", key.Key, err)
+			}
+			return nil, err
+		}
+
+		response, err := marshalResponse(res)
+		if err == nil {
+			err = store.finish(context.WithoutCancel(ctx), key, response)
+		}
+		if err != nil {
+			// A key without a response would answer every retry with
+			// Aborted until it expires. Releasing it lets a retry run the
+			// call again instead.
+			log.Printf("idempotency finish %q: %v", key.Key, err)
+			if err := store.release(context.WithoutCancel(ctx), key); err != nil {
+				log.Printf("idempotency release %q: %v", key.Key, err)
+			}
+		}
+
+		return res, nil
+	}
+}
+
+func replay(key *Key, hash []byte) (any, error) {
+	if string(key.RequestHash) != string(hash) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s %q was used with a different request", Header, key.Key)
+	}
+	if len(key.Response) == 0 {
+		return nil, status.Errorf(codes.Aborted, "request with %s %q is in progress", Header, key.Key)
+	}
+
+	var response anypb.Any
+	if err := proto.Unmarshal(key.Response, &response); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res, err := response.UnmarshalNew()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return res, nil
+}
+
+func requestHash(method string, req any) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, errors.New("request is not a protobuf message")
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write(b)
+
+	return hash.Sum(nil), nil
+}
+
+func marshalResponse(res any) ([]byte, error) {
+	msg, ok := res.(proto.Message)
+	if !ok {
+		return nil, errors.New("response is not a protobuf message")
+	}
+	response, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(response)
+}
@@ -0,0 +1,171 @@
+package idempotency_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/idempotency"
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	return dbtest.New(t).DB
+}
+
+// principalKey carries the principal of a test client. The tests trust it
+// in place of a client certificate.
+const principalKey = "x-test-principal"
+
+func principalFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(principalKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func startServer(t *testing.T, db *gorm.DB, ttl time.Duration) pb.BookServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		audit.UnaryServerInterceptor(principalFromMetadata),
+		idempotency.UnaryServerInterceptor(idempotency.NewStore(db, ttl), "/BookService/CreateBook", "/BookService/UpdateBook"),
+	))
+	pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+	go s.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+	})
+
+	return pb.NewBookServiceClient(conn)
+}
+
+func withKey(principal, key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), principalKey, principal, idempotency.Header, key)
+}
+
+func countBooks(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	assert.NoError(t, db.Model(&model.Book{}).Count(&count).Error)
+	return count
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+	books := startServer(t, db, time.Hour)
+
+	book := &pb.Book{Id: "karamazov", Title: "Братья Карамазовы", Author: "Ф. М. Достоевский"}
+
+	t.Run("Replay", func(t *testing.T) {
+		first, err := books.CreateBook(withKey("alice", "create-1"), &pb.CreateBookRequest{Book: book})
+		assert.NoError(t, err)
+		retry, err := books.CreateBook(withKey("alice", "create-1"), &pb.CreateBookRequest{Book: book})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "karamazov", first.Id)
+		assert.Equal(t, first.Id, retry.Id)
+		assert.Equal(t, int64(1), countBooks(t, db))
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		_, err := books.CreateBook(withKey("alice", "create-1"), &pb.CreateBookRequest{Book: &pb.Book{Title: "Идиот"}})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		_, err = books.UpdateBook(withKey("alice", "create-1"), &pb.UpdateBookRequest{Id: "x", Book: book})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Principal", func(t *testing.T) {
+		_, err := books.CreateBook(withKey("bob", "create-1"), &pb.CreateBookRequest{Book: book})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("Without key", func(t *testing.T) {
+		_, err := books.CreateBook(context.Background(), &pb.CreateBookRequest{Book: book})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("Failed call is not stored", func(t *testing.T) {
+		req := &pb.UpdateBookRequest{Id: "idiot", Book: &pb.Book{Id: "idiot", Title: "Идиот"}}
+		_, err := books.UpdateBook(withKey("alice", "update-1"), req)
+		assert.Error(t, err)
+
+		_, err = books.CreateBook(context.Background(), &pb.CreateBookRequest{Book: &pb.Book{Id: "idiot", Title: "Идот"}})
+		assert.NoError(t, err)
+		_, err = books.UpdateBook(withKey("alice", "update-1"), req)
+		assert.NoError(t, err)
+	})
+}
+
+func TestUnaryServerInterceptor_finishFails(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+	books := startServer(t, db, time.Hour)
+
+	err := db.Callback().Update().Before("gorm:update").Register("fail_idempotency_finish", func(tx *gorm.DB) {
+		if tx.Statement.Table == "idempotency_keys" {
+			tx.AddError(errors.New("disk full"))
+		}
+	})
+	assert.NoError(t, err)
+
+	req := &pb.CreateBookRequest{Book: &pb.Book{Id: "demons", Title: "Бесы"}}
+	_, err = books.CreateBook(withKey("alice", "create"), req)
+	assert.NoError(t, err)
+
+	// The key was released, so the retry runs the call again rather than
+	// waiting for the key to expire.
+	_, err = books.CreateBook(withKey("alice", "create"), req)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestExpiry(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+	books := startServer(t, db, 50*time.Millisecond)
+
+	req := &pb.CreateBookRequest{Book: &pb.Book{Id: "demons", Title: "Бесы"}}
+	_, err := books.CreateBook(withKey("alice", "create"), req)
+	assert.NoError(t, err)
+	_, err = books.CreateBook(withKey("alice", "create"), req)
+	assert.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = books.CreateBook(withKey("alice", "create"), req)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = books.CreateBook(withKey("alice", "other"), &pb.CreateBookRequest{Book: &pb.Book{Id: "idiot", Title: "Идиот"}})
+	assert.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	purged, err := idempotency.NewStore(db, time.Hour).Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
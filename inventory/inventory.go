@@ -0,0 +1,362 @@
+// Package inventory keeps the stock of books per warehouse and the
+// reservations held against it.
+package inventory
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/outbox"
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultWarehouse is used when a stock change names no warehouse.
+const DefaultWarehouse = "main"
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+)
+
+// Stock is the stock of a book in a warehouse. The check constraints back
+// the conditional updates below: stock can never go negative nor be
+// reserved beyond what is on hand.
+type Stock struct {
+	BookID            string `gorm:"primaryKey"`
+	Warehouse         string `gorm:"primaryKey"`
+	OnHand            int64  `gorm:"check:chk_stock_on_hand,on_hand >= reserved"`
+	Reserved          int64  `gorm:"check:chk_stock_reserved,reserved >= 0"`
+	LowStockThreshold int64
+	UpdatedAt         time.Time
+}
+
+func (Stock) TableName() string {
+	return "stock_levels"
+}
+
+func (s *Stock) Available() int64 {
+	return s.OnHand - s.Reserved
+}
+
+func (s *Stock) Proto() *pb.StockLevel {
+	return &pb.StockLevel{
+		BookId:            s.BookID,
+		Warehouse:         s.Warehouse,
+		OnHand:            s.OnHand,
+		Reserved:          s.Reserved,
+		Available:         s.Available(),
+		LowStockThreshold: s.LowStockThreshold,
+	}
+}
+
+type Reservation struct {
+	ID        string `gorm:"primaryKey"`
+	BookID    string `gorm:"index"`
+	Warehouse string
+	Quantity  int64
+	CreatedAt time.Time
+}
+
+func (Reservation) TableName() string {
+	return "stock_reservations"
+}
+
+func (r *Reservation) Proto() *pb.Reservation {
+	return &pb.Reservation{
+		Id:         r.ID,
+		BookId:     r.BookID,
+		Warehouse:  r.Warehouse,
+		Quantity:   r.Quantity,
+		CreateTime: timestamppb.New(r.CreatedAt),
+	}
+}
+
+func Models() []any {
+	return []any{&Stock{}, &Reservation{}}
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Levels returns the stock of a book in every warehouse that has a level
+// for it, by warehouse name.
+func (s *Store) Levels(ctx context.Context, bookID string) ([]*pb.StockLevel, error) {
+	db := s.db.WithContext(ctx)
+	if err := bookExists(db, bookID); err != nil {
+		return nil, err
+	}
+
+	var rows []*Stock
+	if err := db.Where("book_id = ?", bookID).Order("warehouse").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	levels := make([]*pb.StockLevel, len(rows))
+	for i, row := range rows {
+		levels[i] = row.Proto()
+	}
+
+	return levels, nil
+}
+
+// Adjust adds delta copies to the stock on hand, or removes -delta copies
+// if they are available.
+func (s *Store) Adjust(ctx context.Context, bookID, warehouse string, delta int64) (level *pb.StockLevel, err error) {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		level, err = Adjust(tx, bookID, warehouse, delta)
+		return err
+	})
+
+	return level, err
+}
+
+// SetThreshold sets the low-stock threshold of a book in a warehouse.
+func (s *Store) SetThreshold(ctx context.Context, bookID, warehouse string, threshold int64) (*pb.StockLevel, error) {
+	if threshold < 0 {
+		return nil, fmt.Errorf("%w: threshold must not be negative", ErrInvalidQuantity)
+	}
+	if warehouse == "" {
+		warehouse = DefaultWarehouse
+	}
+
+	var row Stock
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bookExists(tx, bookID); err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "book_id"}, {Name: "warehouse"}},
+			DoUpdates: clause.AssignmentColumns([]string{"low_stock_threshold", "updated_at"}),
+		}).Create(&Stock{BookID: bookID, Warehouse: warehouse, LowStockThreshold: threshold}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("book_id = ? AND warehouse = ?", bookID, warehouse).First(&row).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return row.Proto(), nil
+}
+
+// Reserve holds quantity copies of a book. Without a warehouse, the copies
+// are taken from the warehouse with the most available.
+func (s *Store) Reserve(ctx context.Context, bookID, warehouse string, quantity int64) (reservation *pb.Reservation, err error) {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reservation, err = Reserve(tx, bookID, warehouse, quantity)
+		return err
+	})
+
+	return reservation, err
+}
+
+// Release returns the copies held by a reservation to the available stock.
+func (s *Store) Release(ctx context.Context, id string) (reservation *pb.Reservation, err error) {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reservation, err = Release(tx, id)
+		return err
+	})
+
+	return reservation, err
+}
+
+// Adjust is Store.Adjust within the transaction tx.
+func Adjust(tx *gorm.DB, bookID, warehouse string, delta int64) (*pb.StockLevel, error) {
+	if delta == 0 {
+		return nil, fmt.Errorf("%w: delta must not be zero", ErrInvalidQuantity)
+	}
+	if warehouse == "" {
+		warehouse = DefaultWarehouse
+	}
+	if err := bookExists(tx, bookID); err != nil {
+		return nil, err
+	}
+
+	if delta > 0 {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "book_id"}, {Name: "warehouse"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"on_hand":    gorm.Expr("stock_levels.on_hand + ?", delta),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&Stock{BookID: bookID, Warehouse: warehouse, OnHand: delta}).Error
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		res := tx.Model(&Stock{}).
+			Where("book_id = ? AND warehouse = ? AND on_hand - reserved >= ?", bookID, warehouse, -delta).
+			Update("on_hand", gorm.Expr("on_hand + ?", delta))
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil, ErrInsufficientStock
+		}
+	}
+
+	row, err := take(tx, bookID, warehouse, -delta)
+	if err != nil {
+		return nil, err
+	}
+
+	return row.Proto(), nil
+}
+
+// Reserve is Store.Reserve within the transaction tx.
+func Reserve(tx *gorm.DB, bookID, warehouse string, quantity int64) (*pb.Reservation, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if err := bookExists(tx, bookID); err != nil {
+		return nil, err
+	}
+
+	candidates := []string{warehouse}
+	if warehouse == "" {
+		err := tx.Model(&Stock{}).
+			Where("book_id = ? AND on_hand - reserved >= ?", bookID, quantity).
+			Order("on_hand - reserved DESC, warehouse").
+			Pluck("warehouse", &candidates).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A candidate may have been drained since it was listed, in which
+	// case the conditional update leaves it alone and the next is tried.
+	for _, warehouse := range candidates {
+		res := tx.Model(&Stock{}).
+			Where("book_id = ? AND warehouse = ? AND on_hand - reserved >= ?", bookID, warehouse, quantity).
+			Update("reserved", gorm.Expr("reserved + ?", quantity))
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+
+		if _, err := take(tx, bookID, warehouse, quantity); err != nil {
+			return nil, err
+		}
+
+		reservation := &Reservation{ID: uuid.New().String(), BookID: bookID, Warehouse: warehouse, Quantity: quantity}
+		if err := tx.Create(reservation).Error; err != nil {
+			return nil, err
+		}
+
+		return reservation.Proto(), nil
+	}
+
+	return nil, ErrInsufficientStock
+}
+
+// Release is Store.Release within the transaction tx.
+func Release(tx *gorm.DB, id string) (*pb.Reservation, error) {
+	reservation, err := claim(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Model(&Stock{}).
+		Where("book_id = ? AND warehouse = ?", reservation.BookID, reservation.Warehouse).
+		Update("reserved", gorm.Expr("reserved - ?", reservation.Quantity)).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation.Proto(), nil
+}
+
+// Commit takes the copies held by a reservation out of stock, e.g. when
+// they are shipped, and removes the reservation.
+func Commit(tx *gorm.DB, id string) (*pb.Reservation, error) {
+	reservation, err := claim(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Model(&Stock{}).
+		Where("book_id = ? AND warehouse = ?", reservation.BookID, reservation.Warehouse).
+		Updates(map[string]any{
+			"on_hand":  gorm.Expr("on_hand - ?", reservation.Quantity),
+			"reserved": gorm.Expr("reserved - ?", reservation.Quantity),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation.Proto(), nil
+}
+
+// claim removes a reservation and returns it. Only the caller that
+// removes it may move its copies, so a reservation is never released or
+// committed twice.
+func claim(tx *gorm.DB, id string) (*Reservation, error) {
+	var reservation Reservation
+	if err := tx.Where("id = ?", id).First(&reservation).Error; err != nil {
+		return nil, err
+	}
+
+	res := tx.Where("id = ?", id).Delete(&Reservation{})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &reservation, nil
+}
+
+// take reads the stock after taken copies were made unavailable and emits
+// a LOW_STOCK event if that made the stock fall to its threshold. The row
+// is locked by the update that preceded it, so the stock before the
+// change is the stock read plus taken.
+func take(tx *gorm.DB, bookID, warehouse string, taken int64) (*Stock, error) {
+	var row Stock
+	if err := tx.Where("book_id = ? AND warehouse = ?", bookID, warehouse).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	threshold := row.LowStockThreshold
+	if taken > 0 && threshold > 0 && row.Available() <= threshold && row.Available()+taken > threshold {
+		event := &pb.BookEvent{
+			Type:  pb.BookEvent_LOW_STOCK,
+			Stock: row.Proto(),
+			Time:  timestamppb.Now(),
+		}
+		if err := outbox.Write(tx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func bookExists(db *gorm.DB, bookID string) error {
+	var count int64
+	if err := db.Model(&model.Book{}).Where("id = ?", bookID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
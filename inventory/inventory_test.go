@@ -0,0 +1,108 @@
+package inventory_test
+
+import (
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/model"
+	"bookstoregrpc/outbox"
+	"bookstoregrpc/pb"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	return dbtest.New(t).DB
+}
+
+func TestInventory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := inventory.NewStore(db)
+
+	assert.NoError(t, db.Create(&model.Book{ID: "1", Title: "Мастер и Маргарита"}).Error)
+
+	t.Run("Adjust", func(t *testing.T) {
+		level, err := store.Adjust(ctx, "1", "", 5)
+		assert.NoError(t, err)
+		assert.Equal(t, inventory.DefaultWarehouse, level.Warehouse)
+		assert.Equal(t, int64(5), level.OnHand)
+
+		level, err = store.Adjust(ctx, "1", "", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), level.OnHand)
+
+		_, err = store.Adjust(ctx, "1", "", -9)
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+		_, err = store.Adjust(ctx, "1", "spb", -1)
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+		_, err = store.Adjust(ctx, "1", "", 0)
+		assert.ErrorIs(t, err, inventory.ErrInvalidQuantity)
+		_, err = store.Adjust(ctx, "missing", "", 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = store.Adjust(ctx, "1", "spb", 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Reserve", func(t *testing.T) {
+		reservation, err := store.Reserve(ctx, "1", "", 6)
+		assert.NoError(t, err)
+		assert.Equal(t, inventory.DefaultWarehouse, reservation.Warehouse)
+
+		// Reserved copies cannot be taken out of the warehouse.
+		_, err = store.Adjust(ctx, "1", "", -3)
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+
+		// Two copies are left in each warehouse.
+		_, err = store.Reserve(ctx, "1", "", 3)
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+		other, err := store.Reserve(ctx, "1", "spb", 2)
+		assert.NoError(t, err)
+
+		levels, err := store.Levels(ctx, "1")
+		assert.NoError(t, err)
+		if assert.Len(t, levels, 2) {
+			assert.Equal(t, int64(6), levels[0].Reserved)
+			assert.Equal(t, int64(2), levels[0].Available)
+			assert.Equal(t, int64(0), levels[1].Available)
+		}
+
+		released, err := store.Release(ctx, reservation.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), released.Quantity)
+		_, err = store.Release(ctx, reservation.Id)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = store.Release(ctx, other.Id)
+		assert.NoError(t, err)
+
+		levels, err = store.Levels(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), levels[0].Available)
+	})
+
+	t.Run("Low Stock", func(t *testing.T) {
+		_, err := store.SetThreshold(ctx, "1", "", 3)
+		assert.NoError(t, err)
+
+		_, err = store.Adjust(ctx, "1", "", -4)
+		assert.NoError(t, err)
+		_, err = store.Reserve(ctx, "1", "", 1)
+		assert.NoError(t, err)
+		// Already low: no second event.
+		_, err = store.Reserve(ctx, "1", "", 1)
+		assert.NoError(t, err)
+
+		var messages []*outbox.Message
+		assert.NoError(t, db.Where("type = ?", pb.BookEvent_LOW_STOCK.String()).Find(&messages).Error)
+		if assert.Len(t, messages, 1) {
+			event, err := messages[0].Event()
+			assert.NoError(t, err)
+			assert.Equal(t, "1", messages[0].BookID)
+			assert.Equal(t, int64(3), event.Stock.Available)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+// Package isbn validates and normalizes International Standard Book
+// Numbers.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrLength   = errors.New("isbn must have 10 or 13 digits")
+	ErrCharset  = errors.New("isbn may only contain digits, hyphens, spaces and a final X")
+	ErrPrefix   = errors.New("isbn-13 must start with 978 or 979")
+	ErrChecksum = errors.New("isbn check digit does not match")
+)
+
+// Normalize validates an ISBN-10 or ISBN-13, with or without hyphens and
+// spaces, and returns it as 13 digits.
+func Normalize(s string) (string, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+
+	switch len(digits) {
+	case 10:
+		if err := check10(digits); err != nil {
+			return "", err
+		}
+		return To13(digits), nil
+	case 13:
+		if err := check13(digits); err != nil {
+			return "", err
+		}
+		return digits, nil
+	default:
+		return "", ErrLength
+	}
+}
+
+// To13 converts a valid ISBN-10 to ISBN-13.
+func To13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	return body + string(checkDigit13(body))
+}
+
+func check10(s string) error {
+	sum := 0
+	for i, c := range s {
+		var d int
+		switch {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return ErrCharset
+		}
+		sum += (10 - i) * d
+	}
+
+	if sum%11 != 0 {
+		return ErrChecksum
+	}
+
+	return nil
+}
+
+func check13(s string) error {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return ErrCharset
+		}
+	}
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return ErrPrefix
+	}
+	if checkDigit13(s[:12]) != s[12] {
+		return ErrChecksum
+	}
+
+	return nil
+}
+
+// checkDigit13 returns the check digit of the first 12 digits of an
+// ISBN-13.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := range 12 {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
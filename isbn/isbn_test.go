@@ -0,0 +1,34 @@
+package isbn_test
+
+import (
+	"bookstoregrpc/isbn"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"978-0-306-40615-7", "9780306406157", nil},
+		{"9780306406157", "9780306406157", nil},
+		{"0-306-40615-2", "9780306406157", nil},
+		{"0 8044 2957 x", "9780804429573", nil},
+		{"979-10-90636-07-1", "9791090636071", nil},
+		{"978-0-306-40615-8", "", isbn.ErrChecksum},
+		{"0-306-40615-3", "", isbn.ErrChecksum},
+		{"977-0-306-40615-7", "", isbn.ErrPrefix},
+		{"X-306-40615-2", "", isbn.ErrCharset},
+		{"12345", "", isbn.ErrLength},
+		{"", "", isbn.ErrLength},
+	}
+
+	for _, tt := range tests {
+		got, err := isbn.Normalize(tt.in)
+		assert.ErrorIs(t, err, tt.err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
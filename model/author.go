@@ -0,0 +1,44 @@
+package model
+
+import (
+	"bookstoregrpc/pb"
+	"time"
+)
+
+type Author struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (Author) TableName() string {
+	return "authors"
+}
+
+type AuthorAlias struct {
+	AuthorID string `gorm:"primaryKey"`
+	Alias    string `gorm:"primaryKey;index"`
+}
+
+func (AuthorAlias) TableName() string {
+	return "author_aliases"
+}
+
+// BookAuthor links a book to one of its authors. Position keeps the order
+// in which the authors are credited.
+type BookAuthor struct {
+	BookID   string `gorm:"primaryKey"`
+	AuthorID string `gorm:"primaryKey;index"`
+	Position int
+	// Author only declares the foreign key that keeps referenced authors
+	// from being deleted.
+	Author *Author `gorm:"constraint:OnDelete:RESTRICT"`
+}
+
+func (BookAuthor) TableName() string {
+	return "book_authors"
+}
+
+func (a *Author) Proto(aliases []string) *pb.Author {
+	return &pb.Author{Id: a.ID, Name: a.Name, Aliases: aliases}
+}
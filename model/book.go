@@ -0,0 +1,114 @@
+// Package model holds the database representation of the API messages.
+package model
+
+import (
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+
+	"gorm.io/gorm"
+)
+
+// Book is the row of the books table. The price is kept twice: as an exact
+// amount with its currency, and as the legacy integer column that older
+// versions of the service read.
+type Book struct {
+	ID              string `gorm:"primaryKey"`
+	Author          string
+	Title           string
+	Price           int32
+	PriceCurrency   string       `gorm:"size:3"`
+	PriceAmount     money.Amount `gorm:"type:numeric(20,9)"`
+	Isbn            string
+	Description     string
+	Publisher       string
+	PublicationYear int32
+	Language        string
+	PageCount       int32
+	CoverURL        string
+	// The rating aggregates of the approved reviews, only changed by
+	// AddRating.
+	RatingCount   int64   `gorm:"not null;default:0"`
+	RatingSum     int64   `gorm:"not null;default:0"`
+	RatingAverage float64 `gorm:"not null;default:0;index"`
+}
+
+func (Book) TableName() string {
+	return "books"
+}
+
+func Models() []any {
+	return []any{&Book{}, &Author{}, &AuthorAlias{}, &BookAuthor{}, &Category{}, &BookCategory{}, &BookTag{}}
+}
+
+// NewBook converts a validated book, whose list price is set. The author
+// ids, categories and tags are stored as BookAuthor, BookCategory and
+// BookTag rows.
+func NewBook(book *pb.Book) *Book {
+	amount := money.AmountOf(book.GetListPrice())
+
+	return &Book{
+		ID:              book.GetId(),
+		Author:          book.GetAuthor(),
+		Title:           book.GetTitle(),
+		Price:           amount.Int32(),
+		PriceCurrency:   book.GetListPrice().GetCurrencyCode(),
+		PriceAmount:     amount,
+		Isbn:            book.GetIsbn(),
+		Description:     book.GetDescription(),
+		Publisher:       book.GetPublisher(),
+		PublicationYear: book.GetPublicationYear(),
+		Language:        book.GetLanguage(),
+		PageCount:       book.GetPageCount(),
+		CoverURL:        book.GetCoverUrl(),
+	}
+}
+
+// Proto converts the row without the author ids, categories and tags.
+func (b *Book) Proto() *pb.Book {
+	return &pb.Book{
+		Id:              b.ID,
+		Author:          b.Author,
+		Title:           b.Title,
+		Price:           b.PriceAmount.Int32(),
+		ListPrice:       b.PriceAmount.Money(b.PriceCurrency),
+		Isbn:            b.Isbn,
+		Description:     b.Description,
+		Publisher:       b.Publisher,
+		PublicationYear: b.PublicationYear,
+		Language:        b.Language,
+		PageCount:       b.PageCount,
+		CoverUrl:        b.CoverURL,
+		RatingAverage:   b.RatingAverage,
+		RatingCount:     b.RatingCount,
+	}
+}
+
+// AddRating adds count ratings summing to sum to the aggregates of a book,
+// or removes them if count is negative, in a single UPDATE so that
+// concurrent changes add up.
+func AddRating(tx *gorm.DB, bookID string, count, sum int64) error {
+	return tx.Model(&Book{}).Where("id = ?", bookID).Updates(map[string]any{
+		"rating_count": gorm.Expr("rating_count + ?", count),
+		"rating_sum":   gorm.Expr("rating_sum + ?", sum),
+		"rating_average": gorm.Expr("CASE WHEN rating_count + ? > 0 THEN (rating_sum + ?) * 1.0 / (rating_count + ?) ELSE 0 END",
+			count, sum, count),
+	}).Error
+}
+
+func NewBooks(books []*pb.Book) []*Book {
+	rows := make([]*Book, len(books))
+	for i, book := range books {
+		rows[i] = NewBook(book)
+	}
+
+	return rows
+}
+
+func Protos(rows []*Book) []*pb.Book {
+	books := make([]*pb.Book, len(rows))
+	for i, row := range rows {
+		books[i] = row.Proto()
+	}
+
+	return books
+}
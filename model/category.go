@@ -0,0 +1,44 @@
+package model
+
+import (
+	"bookstoregrpc/pb"
+	"time"
+)
+
+// Category is a node of the category tree. ParentID is empty for the
+// top-level categories.
+type Category struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string
+	ParentID  string `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+type BookCategory struct {
+	BookID     string `gorm:"primaryKey"`
+	CategoryID string `gorm:"primaryKey;index"`
+	// Category only declares the foreign key that keeps referenced
+	// categories from being deleted.
+	Category *Category `gorm:"constraint:OnDelete:RESTRICT"`
+}
+
+func (BookCategory) TableName() string {
+	return "book_categories"
+}
+
+type BookTag struct {
+	BookID string `gorm:"primaryKey"`
+	Tag    string `gorm:"primaryKey;index"`
+}
+
+func (BookTag) TableName() string {
+	return "book_tags"
+}
+
+func (c *Category) Proto() *pb.Category {
+	return &pb.Category{Id: c.ID, Name: c.Name, ParentId: c.ParentID}
+}
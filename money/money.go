@@ -0,0 +1,210 @@
+// Package money validates pb.Money values and stores them as exact
+// decimals.
+package money
+
+import (
+	"bookstoregrpc/pb"
+	"cmp"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of prices written by clients that only
+// know the legacy int32 price.
+const DefaultCurrency = "RUB"
+
+const nanosPerUnit = 1_000_000_000
+
+var (
+	ErrInvalidCurrency = errors.New("currency code must be three letters")
+	ErrInvalidNanos    = errors.New("nanos must be between -999999999 and 999999999 with the sign of units")
+	ErrInvalidDecimal  = errors.New("invalid decimal amount")
+)
+
+// Validate checks m and upper-cases its currency code in place.
+func Validate(m *pb.Money) error {
+	m.CurrencyCode = strings.ToUpper(m.CurrencyCode)
+	if len(m.CurrencyCode) != 3 {
+		return ErrInvalidCurrency
+	}
+	for _, c := range m.CurrencyCode {
+		if c < 'A' || c > 'Z' {
+			return ErrInvalidCurrency
+		}
+	}
+
+	if m.Nanos <= -nanosPerUnit || m.Nanos >= nanosPerUnit {
+		return ErrInvalidNanos
+	}
+	if (m.Units > 0 && m.Nanos < 0) || (m.Units < 0 && m.Nanos > 0) {
+		return ErrInvalidNanos
+	}
+
+	return nil
+}
+
+func New(currency string, units int64, nanos int32) *pb.Money {
+	return &pb.Money{CurrencyCode: currency, Units: units, Nanos: nanos}
+}
+
+// Amount is the currency-less value of a Money. It is stored in SQL as a
+// decimal string, so a NUMERIC column keeps it exact.
+type Amount struct {
+	Units int64
+	Nanos int32
+}
+
+func AmountOf(m *pb.Money) Amount {
+	return Amount{Units: m.GetUnits(), Nanos: m.GetNanos()}
+}
+
+func (a Amount) Money(currency string) *pb.Money {
+	return New(currency, a.Units, a.Nanos)
+}
+
+// Cmp returns -1, 0 or 1 as a is less than, equal to or greater than b.
+func (a Amount) Cmp(b Amount) int {
+	if a.Units != b.Units {
+		return cmp.Compare(a.Units, b.Units)
+	}
+
+	return cmp.Compare(a.Nanos, b.Nanos)
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return normalize(a.Units+b.Units, int64(a.Nanos)+int64(b.Nanos))
+}
+
+// Mul returns a times n.
+func (a Amount) Mul(n int64) Amount {
+	nanos := int64(a.Nanos) * n
+	return normalize(a.Units*n+nanos/nanosPerUnit, nanos%nanosPerUnit)
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return a.Add(Amount{Units: -b.Units, Nanos: -b.Nanos})
+}
+
+// Percent returns p percent of a, truncated to whole nanos.
+func (a Amount) Percent(p int64) Amount {
+	nanos := new(big.Int).Mul(big.NewInt(a.Units), big.NewInt(nanosPerUnit))
+	nanos.Add(nanos, big.NewInt(int64(a.Nanos)))
+	nanos.Mul(nanos, big.NewInt(p))
+	nanos.Quo(nanos, big.NewInt(100))
+
+	units, rem := new(big.Int).QuoRem(nanos, big.NewInt(nanosPerUnit), new(big.Int))
+
+	return Amount{Units: units.Int64(), Nanos: int32(rem.Int64())}
+}
+
+// normalize carries whole units out of nanos and gives both the same sign.
+func normalize(units, nanos int64) Amount {
+	units += nanos / nanosPerUnit
+	nanos %= nanosPerUnit
+	if units > 0 && nanos < 0 {
+		units--
+		nanos += nanosPerUnit
+	}
+	if units < 0 && nanos > 0 {
+		units++
+		nanos -= nanosPerUnit
+	}
+
+	return Amount{Units: units, Nanos: int32(nanos)}
+}
+
+// Int32 returns the whole units clamped to the int32 range, the value of
+// the legacy price field.
+func (a Amount) Int32() int32 {
+	return int32(max(min(a.Units, math.MaxInt32), math.MinInt32))
+}
+
+func (a Amount) String() string {
+	sign := ""
+	units, nanos := a.Units, int64(a.Nanos)
+	if units < 0 || nanos < 0 {
+		sign = "-"
+		units, nanos = -units, -nanos
+	}
+
+	return fmt.Sprintf("%s%d.%09d", sign, units, nanos)
+}
+
+// Format returns m for display, e.g. "12.50 RUB".
+func Format(m *pb.Money) string {
+	s := AmountOf(m).String()
+	for len(s) > 0 && s[len(s)-1] == '0' && s[len(s)-3] != '.' {
+		s = s[:len(s)-1]
+	}
+
+	return s + " " + m.GetCurrencyCode()
+}
+
+// ParseAmount parses a decimal with at most nine fractional digits.
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Amount{}, ErrInvalidDecimal
+	}
+	if len(frac) > 9 {
+		// NUMERIC(20,9) never has more digits; a float read back from
+		// SQLite may, and only noise is dropped.
+		frac = frac[:9]
+	}
+
+	var a Amount
+	if whole != "" {
+		units, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || units < 0 {
+			return Amount{}, ErrInvalidDecimal
+		}
+		a.Units = units
+	}
+	if frac != "" {
+		nanos, err := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 32)
+		if err != nil || nanos < 0 {
+			return Amount{}, ErrInvalidDecimal
+		}
+		a.Nanos = int32(nanos)
+	}
+
+	if negative {
+		a.Units, a.Nanos = -a.Units, -a.Nanos
+	}
+
+	return a, nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (a *Amount) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*a = Amount{}
+	case string:
+		*a, err = ParseAmount(v)
+	case []byte:
+		*a, err = ParseAmount(string(v))
+	case int64:
+		*a = Amount{Units: v}
+	case float64:
+		*a, err = ParseAmount(strconv.FormatFloat(v, 'f', 9, 64))
+	default:
+		err = fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+
+	return err
+}
@@ -0,0 +1,77 @@
+package money_test
+
+import (
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	m := &pb.Money{CurrencyCode: "usd", Units: 12, Nanos: 500_000_000}
+	assert.NoError(t, money.Validate(m))
+	assert.Equal(t, "USD", m.CurrencyCode)
+
+	assert.ErrorIs(t, money.Validate(&pb.Money{CurrencyCode: "RUBL"}), money.ErrInvalidCurrency)
+	assert.ErrorIs(t, money.Validate(&pb.Money{CurrencyCode: "RUB", Units: 1, Nanos: -1}), money.ErrInvalidNanos)
+	assert.ErrorIs(t, money.Validate(&pb.Money{CurrencyCode: "RUB", Nanos: 1_000_000_000}), money.ErrInvalidNanos)
+}
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		amount money.Amount
+		s      string
+	}{
+		{money.Amount{Units: 12, Nanos: 340_000_000}, "12.340000000"},
+		{money.Amount{Units: 0, Nanos: 1}, "0.000000001"},
+		{money.Amount{Units: -3, Nanos: -500_000_000}, "-3.500000000"},
+		{money.Amount{Units: 0, Nanos: -5}, "-0.000000005"},
+		{money.Amount{Units: 900}, "900.000000000"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.s, tt.amount.String())
+
+		parsed, err := money.ParseAmount(tt.s)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.amount, parsed)
+	}
+
+	t.Run("Format", func(t *testing.T) {
+		assert.Equal(t, "12.50 RUB", money.Format(money.New("RUB", 12, 500_000_000)))
+		assert.Equal(t, "0.000000001 USD", money.Format(money.New("USD", 0, 1)))
+		assert.Equal(t, "900.00 EUR", money.Format(money.New("EUR", 900, 0)))
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		var a money.Amount
+		assert.NoError(t, a.Scan([]byte("7.25")))
+		assert.Equal(t, money.Amount{Units: 7, Nanos: 250_000_000}, a)
+
+		assert.NoError(t, a.Scan(99.99))
+		assert.Equal(t, money.Amount{Units: 99, Nanos: 990_000_000}, a)
+
+		assert.NoError(t, a.Scan(int64(5)))
+		assert.Equal(t, money.Amount{Units: 5}, a)
+
+		assert.Error(t, a.Scan("abc"))
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		assert.Equal(t, -1, money.Amount{Units: 1}.Cmp(money.Amount{Units: 1, Nanos: 1}))
+		assert.Equal(t, 1, money.Amount{Units: 2}.Cmp(money.Amount{Units: 1, Nanos: 999}))
+		assert.Equal(t, 0, money.Amount{Units: 2}.Cmp(money.Amount{Units: 2}))
+		assert.Equal(t, int32(2147483647), money.Amount{Units: 1 << 40}.Int32())
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		assert.Equal(t, money.Amount{Units: 3, Nanos: 100_000_000}, money.Amount{Units: 1, Nanos: 600_000_000}.Add(money.Amount{Units: 1, Nanos: 500_000_000}))
+		assert.Equal(t, money.Amount{Units: 0, Nanos: 500_000_000}, money.Amount{Units: 1}.Add(money.Amount{Nanos: -500_000_000}))
+		assert.Equal(t, money.Amount{Units: 29, Nanos: 970_000_000}, money.Amount{Units: 9, Nanos: 990_000_000}.Mul(3))
+		assert.Equal(t, money.Amount{}, money.Amount{Units: 5, Nanos: 1}.Mul(0))
+		assert.Equal(t, money.Amount{Units: 8, Nanos: 500_000_000}, money.Amount{Units: 10}.Sub(money.Amount{Units: 1, Nanos: 500_000_000}))
+		assert.Equal(t, money.Amount{Units: 2, Nanos: 997_000_000}, money.Amount{Units: 9, Nanos: 990_000_000}.Percent(30))
+		assert.Equal(t, money.Amount{Nanos: 3}, money.Amount{Nanos: 10}.Percent(33))
+	})
+}
@@ -0,0 +1,8 @@
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI v2 document generated from proto/book_service.proto.
+//
+//go:embed bookstore.swagger.json
+var Spec []byte
@@ -0,0 +1,373 @@
+// Package order keeps shopping carts and the orders placed from them.
+package order
+
+import (
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCartPlaced        = errors.New("cart has already been placed")
+	ErrEmptyCart         = errors.New("cart is empty")
+	ErrMixedCurrencies   = errors.New("books in the cart are priced in different currencies")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+)
+
+type Cart struct {
+	ID string `gorm:"primaryKey"`
+	// OrderID is set when the cart is placed.
+	OrderID   string
+	CreatedAt time.Time
+}
+
+func (Cart) TableName() string {
+	return "carts"
+}
+
+type CartItem struct {
+	CartID   string `gorm:"primaryKey"`
+	BookID   string `gorm:"primaryKey"`
+	Quantity int64
+}
+
+func (CartItem) TableName() string {
+	return "cart_items"
+}
+
+// Order is a placed cart. A cart is placed at most once, which the unique
+// index on CartID enforces even for concurrent calls.
+type Order struct {
+	ID          string `gorm:"primaryKey"`
+	CartID      string `gorm:"uniqueIndex"`
+	Status      string
+	Currency    string       `gorm:"size:3"`
+	TotalAmount money.Amount `gorm:"type:numeric(20,9)"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Order) TableName() string {
+	return "orders"
+}
+
+// OrderItem is a line of an order with the title and effective price of
+// the book, after the price rules active then, at the time the order was
+// placed, and the reservation that holds its stock
+// until the order is shipped or cancelled.
+type OrderItem struct {
+	OrderID       string `gorm:"primaryKey"`
+	BookID        string `gorm:"primaryKey"`
+	Title         string
+	Quantity      int64
+	UnitAmount    money.Amount `gorm:"type:numeric(20,9)"`
+	ReservationID string
+}
+
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+func Models() []any {
+	return []any{&Cart{}, &CartItem{}, &Order{}, &OrderItem{}}
+}
+
+// transitions lists the statuses an order can move to from each status.
+var transitions = map[pb.Order_Status][]pb.Order_Status{
+	pb.Order_PAID:      {pb.Order_PENDING},
+	pb.Order_SHIPPED:   {pb.Order_PAID},
+	pb.Order_CANCELLED: {pb.Order_PENDING, pb.Order_PAID},
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) CreateCart(ctx context.Context) (*pb.Cart, error) {
+	cart := &Cart{ID: uuid.New().String()}
+	if err := s.db.WithContext(ctx).Create(cart).Error; err != nil {
+		return nil, err
+	}
+
+	return &pb.Cart{Id: cart.ID, CreateTime: timestamppb.New(cart.CreatedAt)}, nil
+}
+
+func (s *Store) GetCart(ctx context.Context, id string) (*pb.Cart, error) {
+	return getCart(s.db.WithContext(ctx), id)
+}
+
+// SetItem sets the quantity of a book in an open cart; 0 removes the book.
+func (s *Store) SetItem(ctx context.Context, cartID, bookID string, quantity int64) (cart *pb.Cart, err error) {
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity must not be negative", inventory.ErrInvalidQuantity)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := openCart(tx, cartID); err != nil {
+			return err
+		}
+
+		item := &CartItem{CartID: cartID, BookID: bookID, Quantity: quantity}
+		if quantity == 0 {
+			if err := tx.Delete(item).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Where("id = ?", bookID).First(&model.Book{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+		}
+
+		cart, err = getCart(tx, cartID)
+		return err
+	})
+
+	return cart, err
+}
+
+// Place turns an open cart into a pending order in one transaction: the
+// list prices of the books are copied into the order and their stock is
+// reserved. If the cart has been placed already, the existing order is
+// returned instead.
+func (s *Store) Place(ctx context.Context, cartID string) (*pb.Order, error) {
+	db := s.db.WithContext(ctx)
+
+	var orderID string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var cart Cart
+		if err := tx.Where("id = ?", cartID).First(&cart).Error; err != nil {
+			return err
+		}
+		if cart.OrderID != "" {
+			orderID = cart.OrderID
+			return nil
+		}
+
+		var items []*CartItem
+		if err := tx.Where("cart_id = ?", cartID).Order("book_id").Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return ErrEmptyCart
+		}
+
+		rules, err := pricing.Active(tx, time.Now())
+		if err != nil {
+			return err
+		}
+
+		order := &Order{ID: uuid.New().String(), CartID: cartID, Status: pb.Order_PENDING.String()}
+		lines := make([]*OrderItem, len(items))
+		var total money.Amount
+		for i, item := range items {
+			var row model.Book
+			if err := tx.Where("id = ?", item.BookID).First(&row).Error; err != nil {
+				return fmt.Errorf("book %s: %w", item.BookID, err)
+			}
+			book := row.Proto()
+			if err := pricing.ApplyStored(tx, rules, book); err != nil {
+				return fmt.Errorf("book %s: %w", item.BookID, err)
+			}
+			price := book.GetEffectivePrice()
+			if order.Currency == "" {
+				order.Currency = price.GetCurrencyCode()
+			}
+			if price.GetCurrencyCode() != order.Currency {
+				return ErrMixedCurrencies
+			}
+
+			reservation, err := inventory.Reserve(tx, item.BookID, "", item.Quantity)
+			if err != nil {
+				return fmt.Errorf("book %s: %w", item.BookID, err)
+			}
+
+			lines[i] = &OrderItem{
+				OrderID:       order.ID,
+				BookID:        item.BookID,
+				Title:         book.Title,
+				Quantity:      item.Quantity,
+				UnitAmount:    money.AmountOf(price),
+				ReservationID: reservation.Id,
+			}
+			total = total.Add(money.AmountOf(price).Mul(item.Quantity))
+		}
+		order.TotalAmount = total
+
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(lines).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&Cart{}).Where("id = ? AND order_id = ?", cartID, "").Update("order_id", order.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrCartPlaced
+		}
+		orderID = order.ID
+
+		return nil
+	})
+	// A concurrent call placed the cart first; its order is the result.
+	if errors.Is(err, gorm.ErrDuplicatedKey) || errors.Is(err, ErrCartPlaced) {
+		var order Order
+		if err := db.Where("cart_id = ?", cartID).First(&order).Error; err != nil {
+			return nil, err
+		}
+		orderID, err = order.ID, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return getOrder(db, orderID)
+}
+
+func (s *Store) GetOrder(ctx context.Context, id string) (*pb.Order, error) {
+	return getOrder(s.db.WithContext(ctx), id)
+}
+
+func (s *Store) Pay(ctx context.Context, id string) (*pb.Order, error) {
+	return s.transition(ctx, id, pb.Order_PAID, nil)
+}
+
+// Ship takes the reserved copies of the order out of stock.
+func (s *Store) Ship(ctx context.Context, id string) (*pb.Order, error) {
+	return s.transition(ctx, id, pb.Order_SHIPPED, inventory.Commit)
+}
+
+// Cancel releases the reserved copies of the order.
+func (s *Store) Cancel(ctx context.Context, id string) (*pb.Order, error) {
+	return s.transition(ctx, id, pb.Order_CANCELLED, inventory.Release)
+}
+
+// transition moves an order to status and applies settle to the
+// reservation of every item, in one transaction. The status is changed by
+// a conditional update, so of two concurrent transitions from the same
+// status only one succeeds.
+func (s *Store) transition(ctx context.Context, id string, status pb.Order_Status, settle func(*gorm.DB, string) (*pb.Reservation, error)) (*pb.Order, error) {
+	db := s.db.WithContext(ctx)
+
+	from := make([]string, len(transitions[status]))
+	for i, status := range transitions[status] {
+		from[i] = status.String()
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Order{}).Where("id = ? AND status IN ?", id, from).Update("status", status.String())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			var order Order
+			if err := tx.Where("id = ?", id).First(&order).Error; err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, status)
+		}
+
+		if settle == nil {
+			return nil
+		}
+
+		var items []*OrderItem
+		if err := tx.Where("order_id = ?", id).Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			if _, err := settle(tx, item.ReservationID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return getOrder(db, id)
+}
+
+// openCart returns an error unless the cart exists and has not been placed.
+func openCart(tx *gorm.DB, id string) error {
+	var cart Cart
+	if err := tx.Where("id = ?", id).First(&cart).Error; err != nil {
+		return err
+	}
+	if cart.OrderID != "" {
+		return ErrCartPlaced
+	}
+
+	return nil
+}
+
+func getCart(db *gorm.DB, id string) (*pb.Cart, error) {
+	var cart Cart
+	if err := db.Where("id = ?", id).First(&cart).Error; err != nil {
+		return nil, err
+	}
+
+	var items []*CartItem
+	if err := db.Where("cart_id = ?", id).Order("book_id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	res := &pb.Cart{Id: cart.ID, OrderId: cart.OrderID, CreateTime: timestamppb.New(cart.CreatedAt)}
+	for _, item := range items {
+		res.Items = append(res.Items, &pb.CartItem{BookId: item.BookID, Quantity: item.Quantity})
+	}
+
+	return res, nil
+}
+
+func getOrder(db *gorm.DB, id string) (*pb.Order, error) {
+	var order Order
+	if err := db.Where("id = ?", id).First(&order).Error; err != nil {
+		return nil, err
+	}
+
+	var items []*OrderItem
+	if err := db.Where("order_id = ?", id).Order("book_id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	res := &pb.Order{
+		Id:         order.ID,
+		CartId:     order.CartID,
+		Status:     pb.Order_Status(pb.Order_Status_value[order.Status]),
+		Total:      order.TotalAmount.Money(order.Currency),
+		CreateTime: timestamppb.New(order.CreatedAt),
+		UpdateTime: timestamppb.New(order.UpdatedAt),
+	}
+	for _, item := range items {
+		res.Items = append(res.Items, &pb.OrderItem{
+			BookId:    item.BookID,
+			Title:     item.Title,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitAmount.Money(order.Currency),
+		})
+	}
+
+	return res, nil
+}
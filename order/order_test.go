@@ -0,0 +1,187 @@
+package order_test
+
+import (
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
+	"bookstoregrpc/order"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	return dbtest.New(t).DB
+}
+
+func TestOrders(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := order.NewStore(db)
+	stock := inventory.NewStore(db)
+
+	books := []*pb.Book{
+		{Id: "1", Title: "Отцы и дети", ListPrice: money.New("RUB", 350, 500_000_000)},
+		{Id: "2", Title: "Обломов", ListPrice: money.New("RUB", 500, 0)},
+		{Id: "3", Title: "Dubliners", ListPrice: money.New("EUR", 12, 0)},
+	}
+	for _, book := range books {
+		assert.NoError(t, db.Create(model.NewBook(book)).Error)
+		_, err := stock.Adjust(ctx, book.Id, "", 3)
+		assert.NoError(t, err)
+	}
+
+	available := func(bookID string) int64 {
+		levels, err := stock.Levels(ctx, bookID)
+		assert.NoError(t, err)
+		return levels[0].Available
+	}
+
+	t.Run("Place", func(t *testing.T) {
+		cart, err := store.CreateCart(ctx)
+		assert.NoError(t, err)
+
+		_, err = store.Place(ctx, cart.Id)
+		assert.ErrorIs(t, err, order.ErrEmptyCart)
+
+		_, err = store.SetItem(ctx, cart.Id, "1", 2)
+		assert.NoError(t, err)
+		_, err = store.SetItem(ctx, cart.Id, "2", 1)
+		assert.NoError(t, err)
+		_, err = store.SetItem(ctx, cart.Id, "missing", 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		placed, err := store.Place(ctx, cart.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, pb.Order_PENDING, placed.Status)
+		assert.Equal(t, "1201.00 RUB", money.Format(placed.Total))
+		if assert.Len(t, placed.Items, 2) {
+			assert.Equal(t, "Отцы и дети", placed.Items[0].Title)
+			assert.Equal(t, int64(350), placed.Items[0].UnitPrice.Units)
+		}
+		assert.Equal(t, int64(1), available("1"))
+
+		// Prices are snapshots: later changes do not affect the order.
+		assert.NoError(t, db.Model(&model.Book{}).Where("id = ?", "1").Update("price_amount", money.Amount{Units: 999}).Error)
+
+		again, err := store.Place(ctx, cart.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, placed.Id, again.Id)
+		assert.Equal(t, placed.Total.Units, again.Total.Units)
+		assert.Equal(t, int64(1), available("1"))
+
+		_, err = store.SetItem(ctx, cart.Id, "1", 1)
+		assert.ErrorIs(t, err, order.ErrCartPlaced)
+
+		t.Run("Lifecycle", func(t *testing.T) {
+			_, err := store.Ship(ctx, placed.Id)
+			assert.ErrorIs(t, err, order.ErrInvalidTransition)
+
+			paid, err := store.Pay(ctx, placed.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, pb.Order_PAID, paid.Status)
+
+			shipped, err := store.Ship(ctx, placed.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, pb.Order_SHIPPED, shipped.Status)
+
+			levels, err := stock.Levels(ctx, "1")
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), levels[0].OnHand)
+			assert.Equal(t, int64(0), levels[0].Reserved)
+
+			_, err = store.Cancel(ctx, placed.Id)
+			assert.ErrorIs(t, err, order.ErrInvalidTransition)
+		})
+	})
+
+	t.Run("All Or Nothing", func(t *testing.T) {
+		cart, err := store.CreateCart(ctx)
+		assert.NoError(t, err)
+		_, err = store.SetItem(ctx, cart.Id, "2", 1)
+		assert.NoError(t, err)
+		_, err = store.SetItem(ctx, cart.Id, "1", 5)
+		assert.NoError(t, err)
+
+		_, err = store.Place(ctx, cart.Id)
+		assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+		assert.Equal(t, int64(2), available("2"))
+
+		_, err = store.SetItem(ctx, cart.Id, "1", 0)
+		assert.NoError(t, err)
+		_, err = store.SetItem(ctx, cart.Id, "3", 1)
+		assert.NoError(t, err)
+		_, err = store.Place(ctx, cart.Id)
+		assert.ErrorIs(t, err, order.ErrMixedCurrencies)
+		assert.Equal(t, int64(2), available("2"))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		cart, err := store.CreateCart(ctx)
+		assert.NoError(t, err)
+		_, err = store.SetItem(ctx, cart.Id, "3", 3)
+		assert.NoError(t, err)
+
+		placed, err := store.Place(ctx, cart.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), available("3"))
+
+		cancelled, err := store.Cancel(ctx, placed.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, pb.Order_CANCELLED, cancelled.Status)
+		assert.Equal(t, int64(3), available("3"))
+
+		_, err = store.Pay(ctx, placed.Id)
+		assert.ErrorIs(t, err, order.ErrInvalidTransition)
+		_, err = store.Pay(ctx, "missing")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestOrders_discount(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := order.NewStore(db)
+
+	assert.NoError(t, db.Create(&model.Category{ID: "classics", Name: "Классика"}).Error)
+	assert.NoError(t, db.Create(&model.Category{ID: "russian", Name: "Русская классика", ParentID: "classics"}).Error)
+	assert.NoError(t, db.Create(model.NewBook(&pb.Book{Id: "1", Title: "Обломов", ListPrice: money.New("RUB", 500, 0)})).Error)
+	assert.NoError(t, db.Create(&model.BookCategory{BookID: "1", CategoryID: "russian"}).Error)
+	_, err := inventory.NewStore(db).Adjust(ctx, "1", "", 3)
+	assert.NoError(t, err)
+
+	// The rule is on the parent category of the book's category.
+	rule, err := pricing.NewStore(db).Create(ctx, &pb.PriceRule{
+		Discount: &pb.PriceRule_PercentOff{PercentOff: 20},
+		Scope:    pb.PriceRule_CATEGORY,
+		ScopeId:  "classics",
+	})
+	assert.NoError(t, err)
+
+	cart, err := store.CreateCart(ctx)
+	assert.NoError(t, err)
+	_, err = store.SetItem(ctx, cart.Id, "1", 2)
+	assert.NoError(t, err)
+
+	placed, err := store.Place(ctx, cart.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "800.00 RUB", money.Format(placed.Total))
+	if assert.Len(t, placed.Items, 1) {
+		assert.Equal(t, int64(400), placed.Items[0].UnitPrice.Units)
+	}
+
+	// The discounted price is a snapshot: the sale ending does not change
+	// the order.
+	_, err = pricing.NewStore(db).Delete(ctx, rule.Id)
+	assert.NoError(t, err)
+	placed, err = store.GetOrder(ctx, placed.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "800.00 RUB", money.Format(placed.Total))
+}
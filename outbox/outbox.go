@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"bookstoregrpc/pb"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+)
+
+// Message is a book change event stored in the same transaction as the
+// change itself. Its ID is the durable sequence number of the event.
+type Message struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	Type      string
+	BookID    string `gorm:"index"`
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Cursor is the id of the last message a sink has acknowledged.
+type Cursor struct {
+	Sink      string `gorm:"primaryKey"`
+	LastID    uint64
+	UpdatedAt time.Time
+}
+
+func (Cursor) TableName() string {
+	return "outbox_cursors"
+}
+
+func Models() []any {
+	return []any{&Message{}, &Cursor{}}
+}
+
+// Write adds the event to the outbox. tx must be the transaction that
+// applies the change, so the event is stored if and only if it commits.
+func Write(tx *gorm.DB, event *pb.BookEvent) error {
+	payload, err := protojson.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+
+	bookID := event.GetAfter().GetId()
+	if bookID == "" {
+		bookID = event.GetBefore().GetId()
+	}
+	if bookID == "" {
+		bookID = event.GetStock().GetBookId()
+	}
+
+	msg := &Message{
+		Type:    event.GetType().String(),
+		BookID:  bookID,
+		Payload: payload,
+	}
+	if err := tx.Create(msg).Error; err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// Event decodes the message; its sequence is the message id.
+func (m *Message) Event() (*pb.BookEvent, error) {
+	event := &pb.BookEvent{}
+	if err := protojson.Unmarshal(m.Payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox message %d: %w", m.ID, err)
+	}
+	event.Sequence = m.ID
+
+	return event, nil
+}
@@ -0,0 +1,247 @@
+package outbox_test
+
+import (
+	"bookstoregrpc/database"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/outbox"
+	"bookstoregrpc/pb"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	opts := database.DefaultOptions()
+	opts.Migrations = []func(*gorm.DB) error{
+		func(db *gorm.DB) error { return db.AutoMigrate(outbox.Models()...) },
+	}
+
+	return dbtest.Open(t, opts).DB
+}
+
+func writeEvents(t *testing.T, db *gorm.DB, ids ...string) {
+	for _, id := range ids {
+		event := &pb.BookEvent{Type: pb.BookEvent_CREATED, After: &pb.Book{Id: id}}
+		assert.NoError(t, outbox.Write(db, event))
+	}
+}
+
+// flakySink fails the first failures deliveries.
+type flakySink struct {
+	name      string
+	failures  int
+	delivered []*pb.BookEvent
+}
+
+func (fs *flakySink) Name() string {
+	return fs.name
+}
+
+func (fs *flakySink) Deliver(_ context.Context, event *pb.BookEvent) error {
+	if fs.failures > 0 {
+		fs.failures--
+		return errors.New("sink is down")
+	}
+	fs.delivered = append(fs.delivered, event)
+
+	return nil
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		writeEvents(t, tx, "1")
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+	writeEvents(t, db, "2")
+
+	var messages []outbox.Message
+	assert.NoError(t, db.Find(&messages).Error)
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, "2", messages[0].BookID)
+		assert.Equal(t, pb.BookEvent_CREATED.String(), messages[0].Type)
+
+		event, err := messages[0].Event()
+		assert.NoError(t, err)
+		assert.Equal(t, messages[0].ID, event.Sequence)
+		assert.Equal(t, "2", event.After.Id)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	writeEvents(t, db, "1", "2", "3")
+
+	t.Run("Channel Sink", func(t *testing.T) {
+		ch := make(chan *pb.BookEvent, 3)
+		relay := outbox.NewRelay(db, outbox.NewChannelSink("test", ch), outbox.RelayOptions{BatchSize: 2})
+
+		n, err := relay.RunOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		n, err = relay.RunOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		n, err = relay.RunOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		close(ch)
+		var ids []string
+		for event := range ch {
+			ids = append(ids, event.After.Id)
+		}
+		assert.Equal(t, []string{"1", "2", "3"}, ids)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		sink := &flakySink{name: "retry", failures: 1}
+		relay := outbox.NewRelay(db, sink, outbox.DefaultRelayOptions())
+
+		n, err := relay.RunOnce(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, 0, n)
+
+		var cursor outbox.Cursor
+		assert.NoError(t, db.First(&cursor, "sink = ?", sink.Name()).Error)
+		assert.Equal(t, uint64(0), cursor.LastID)
+
+		n, err = relay.RunOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Len(t, sink.delivered, 3)
+
+		assert.NoError(t, db.First(&cursor, "sink = ?", sink.Name()).Error)
+		assert.Equal(t, sink.delivered[2].Sequence, cursor.LastID)
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		sink := &flakySink{name: "run", failures: 2}
+		relay := outbox.NewRelay(db, sink, outbox.RelayOptions{MinBackoff: 1, MaxBackoff: 2})
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() { done <- relay.Run(ctx) }()
+
+		assert.Eventually(t, func() bool {
+			var cursor outbox.Cursor
+			db.First(&cursor, "sink = ?", sink.Name())
+			return cursor.LastID == 3
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+		assert.Len(t, sink.delivered, 3)
+	})
+}
+
+func TestRelay_outOfOrderCommits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	sink := &flakySink{name: "gaps"}
+	relay := outbox.NewRelay(db, sink, outbox.RelayOptions{GapTimeout: time.Hour})
+
+	// commit stores a message with the id its transaction got on insert.
+	commit := func(id uint64, createdAt time.Time) {
+		payload, err := protojson.Marshal(&pb.BookEvent{Type: pb.BookEvent_CREATED, After: &pb.Book{Id: fmt.Sprint(id)}})
+		assert.NoError(t, err)
+		assert.NoError(t, db.Create(&outbox.Message{ID: id, Type: pb.BookEvent_CREATED.String(), Payload: payload, CreatedAt: createdAt}).Error)
+	}
+	delivered := func() []string {
+		var ids []string
+		for _, event := range sink.delivered {
+			ids = append(ids, event.After.Id)
+		}
+		return ids
+	}
+
+	// The transaction of message 2 commits after the one of message 3.
+	commit(1, time.Now())
+	commit(3, time.Now())
+	n, err := relay.RunOnce(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	commit(2, time.Now())
+	n, err = relay.RunOnce(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"1", "2", "3"}, delivered())
+
+	// Message 4 never commits: once the gap is old enough, 5 is delivered.
+	commit(5, time.Now().Add(-2*time.Hour))
+	n, err = relay.RunOnce(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"1", "2", "3", "5"}, delivered())
+}
+
+func TestSinks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	event := &pb.BookEvent{Sequence: 7, Type: pb.BookEvent_DELETED, Before: &pb.Book{Id: "1"}}
+
+	t.Run("Webhook Sink", func(t *testing.T) {
+		status := http.StatusInternalServerError
+		received := make(chan *pb.BookEvent, 1)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			got := &pb.BookEvent{}
+			assert.NoError(t, protojson.Unmarshal(body, got))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			if status == http.StatusOK {
+				received <- got
+			}
+			w.WriteHeader(status)
+		}))
+		defer ts.Close()
+
+		sink := outbox.NewWebhookSink(ts.URL)
+		assert.Error(t, sink.Deliver(ctx, event))
+
+		status = http.StatusOK
+		assert.NoError(t, sink.Deliver(ctx, event))
+		assert.Equal(t, uint64(7), (<-received).Sequence)
+	})
+
+	t.Run("File Sink", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.ndjson")
+		sink := outbox.NewFileSink(path)
+		assert.NoError(t, sink.Deliver(ctx, event))
+		assert.NoError(t, sink.Deliver(ctx, event))
+
+		f, err := os.Open(path)
+		assert.NoError(t, err)
+		defer f.Close()
+
+		var lines int
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			got := &pb.BookEvent{}
+			assert.NoError(t, protojson.Unmarshal(scanner.Bytes(), got))
+			assert.Equal(t, "1", got.Before.Id)
+			lines++
+		}
+		assert.Equal(t, 2, lines)
+	})
+}
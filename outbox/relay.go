@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RelayOptions struct {
+	// PollInterval is how long the relay waits when the outbox is empty.
+	PollInterval time.Duration
+	// MinBackoff and MaxBackoff bound the delay between retries of a
+	// failed delivery; the delay doubles after every failure.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BatchSize is the number of messages read per query.
+	BatchSize int
+	// GapTimeout is how long the relay waits for a missing id below a
+	// message it has read. A message gets its id when its transaction
+	// inserts it but becomes visible only when the transaction commits, so
+	// concurrent transactions can commit out of id order. A gap older than
+	// GapTimeout is taken to be a rolled-back transaction and skipped; it
+	// must be longer than any transaction that writes to the outbox.
+	GapTimeout time.Duration
+}
+
+func DefaultRelayOptions() RelayOptions {
+	return RelayOptions{
+		PollInterval: time.Second,
+		MinBackoff:   500 * time.Millisecond,
+		MaxBackoff:   time.Minute,
+		BatchSize:    100,
+		GapTimeout:   30 * time.Second,
+	}
+}
+
+// Relay delivers outbox messages to one sink in order. The sink's cursor is
+// advanced only after it accepted a message, so a crash between delivery
+// and the cursor update redelivers the message: delivery is at-least-once
+// and sinks must tolerate duplicates (the event sequence identifies them).
+type Relay struct {
+	db   *gorm.DB
+	sink Sink
+	opts RelayOptions
+}
+
+func NewRelay(db *gorm.DB, sink Sink, opts RelayOptions) *Relay {
+	defaults := DefaultRelayOptions()
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaults.MinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaults.MaxBackoff, opts.MinBackoff)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.GapTimeout <= 0 {
+		opts.GapTimeout = defaults.GapTimeout
+	}
+
+	return &Relay{db: db, sink: sink, opts: opts}
+}
+
+// Run delivers messages until ctx is done.
+func (r *Relay) Run(ctx context.Context) error {
+	backoff := r.opts.MinBackoff
+
+	for {
+		delivered, err := r.RunOnce(ctx)
+
+		wait := r.opts.PollInterval
+		switch {
+		case err != nil:
+			log.Printf("outbox relay %s: %v, retrying in %s", r.sink.Name(), err, backoff)
+			wait = backoff
+			backoff = min(backoff*2, r.opts.MaxBackoff)
+		case delivered > 0:
+			backoff = r.opts.MinBackoff
+			wait = 0
+		default:
+			backoff = r.opts.MinBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RunOnce delivers up to BatchSize pending messages and returns how many
+// were delivered. It stops at the first message the sink rejects, and
+// before a gap in the ids that is younger than GapTimeout.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	db := r.db.WithContext(ctx)
+
+	cursor := Cursor{Sink: r.sink.Name()}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cursor).Error; err != nil {
+		return 0, err
+	}
+	if err := db.First(&cursor, "sink = ?", cursor.Sink).Error; err != nil {
+		return 0, err
+	}
+
+	var messages []Message
+	err := db.Where("id > ?", cursor.LastID).Order("id").Limit(r.opts.BatchSize).Find(&messages).Error
+	if err != nil {
+		return 0, err
+	}
+
+	next := cursor.LastID + 1
+	for i, msg := range messages {
+		if msg.ID != next && time.Since(msg.CreatedAt) < r.opts.GapTimeout {
+			// The missing messages may still commit.
+			return i, nil
+		}
+		next = msg.ID + 1
+
+		event, err := msg.Event()
+		if err != nil {
+			return i, err
+		}
+
+		if err := r.sink.Deliver(ctx, event); err != nil {
+			return i, fmt.Errorf("message %d: %w", msg.ID, err)
+		}
+
+		err = db.Model(&Cursor{}).Where("sink = ?", cursor.Sink).Update("last_id", msg.ID).Error
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(messages), nil
+}
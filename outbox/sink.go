@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"bookstoregrpc/pb"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Sink receives outbox events. Deliver returning nil acknowledges the
+// event; an error makes the relay retry it later.
+type Sink interface {
+	// Name identifies the sink's cursor, so it must be stable across restarts.
+	Name() string
+	Deliver(context.Context, *pb.BookEvent) error
+}
+
+// WebhookSink POSTs every event as JSON to URL and expects a 2xx answer.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, Client: http.DefaultClient}
+}
+
+func (ws *WebhookSink) Name() string {
+	return "webhook:" + ws.URL
+}
+
+func (ws *WebhookSink) Deliver(ctx context.Context, event *pb.BookEvent) error {
+	body, err := protojson.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ws.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := ws.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+
+	return nil
+}
+
+// FileSink appends every event as a line of JSON to a file.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (fs *FileSink) Name() string {
+	return "file:" + fs.path
+}
+
+func (fs *FileSink) Deliver(_ context.Context, event *pb.BookEvent) error {
+	line, err := protojson.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	f, err := os.OpenFile(fs.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ChannelSink hands events to an in-process consumer. Deliver blocks until
+// the consumer takes the event or ctx is done.
+type ChannelSink struct {
+	name string
+	ch   chan<- *pb.BookEvent
+}
+
+func NewChannelSink(name string, ch chan<- *pb.BookEvent) *ChannelSink {
+	return &ChannelSink{name: name, ch: ch}
+}
+
+func (cs *ChannelSink) Name() string {
+	return "channel:" + cs.name
+}
+
+func (cs *ChannelSink) Deliver(ctx context.Context, event *pb.BookEvent) error {
+	select {
+	case cs.ch <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
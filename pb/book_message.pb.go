@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.14.0
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: book_message.proto
 
 package pb
@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,23 +22,51 @@ const (
 )
 
 type Book struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Author string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Title  string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	// Whole units of list_price, for clients that predate it. When a book is
+	// written without list_price, price is taken in RUB.
+	//
+	// Deprecated: Marked as deprecated in book_message.proto.
+	Price int32 `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	// ISBN-10 or ISBN-13, stored as the 13 digits of the ISBN-13.
+	Isbn        string `protobuf:"bytes,5,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Description string `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Publisher   string `protobuf:"bytes,7,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	// 0 if unknown.
+	PublicationYear int32 `protobuf:"varint,8,opt,name=publication_year,json=publicationYear,proto3" json:"publication_year,omitempty"`
+	// ISO 639 language code, e.g. "ru".
+	Language  string `protobuf:"bytes,9,opt,name=language,proto3" json:"language,omitempty"`
+	PageCount int32  `protobuf:"varint,10,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`
+	CoverUrl  string `protobuf:"bytes,11,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	ListPrice *Money `protobuf:"bytes,12,opt,name=list_price,json=listPrice,proto3" json:"list_price,omitempty"`
+	// Ids of the Author resources, in credit order. author remains the
+	// display name.
+	AuthorIds []string `protobuf:"bytes,13,rep,name=author_ids,json=authorIds,proto3" json:"author_ids,omitempty"`
+	// Ids of the Category resources the book is filed under.
+	CategoryIds []string `protobuf:"bytes,14,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	// Free-form labels, stored trimmed and lower-cased.
+	Tags []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Average and number of the approved reviews, maintained by
+	// ReviewService and ignored when the book is written.
+	RatingAverage float64 `protobuf:"fixed64,16,opt,name=rating_average,json=ratingAverage,proto3" json:"rating_average,omitempty"`
+	RatingCount   int64   `protobuf:"varint,17,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	// list_price after the best active PriceRule, as of the time the book
+	// was read. Ignored when the book is written.
+	EffectivePrice *Money `protobuf:"bytes,18,opt,name=effective_price,json=effectivePrice,proto3" json:"effective_price,omitempty"`
+	// The PriceRule applied, empty if none is.
+	PriceRuleId   string `protobuf:"bytes,19,opt,name=price_rule_id,json=priceRuleId,proto3" json:"price_rule_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Author string `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
-	Title  string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Price  int32  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Book) Reset() {
 	*x = Book{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_message_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_message_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Book) String() string {
@@ -48,7 +77,7 @@ func (*Book) ProtoMessage() {}
 
 func (x *Book) ProtoReflect() protoreflect.Message {
 	mi := &file_book_message_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -84,6 +113,7 @@ func (x *Book) GetTitle() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in book_message.proto.
 func (x *Book) GetPrice() int32 {
 	if x != nil {
 		return x.Price
@@ -91,41 +121,166 @@ func (x *Book) GetPrice() int32 {
 	return 0
 }
 
-var File_book_message_proto protoreflect.FileDescriptor
+func (x *Book) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Book) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Book) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *Book) GetPublicationYear() int32 {
+	if x != nil {
+		return x.PublicationYear
+	}
+	return 0
+}
+
+func (x *Book) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Book) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *Book) GetCoverUrl() string {
+	if x != nil {
+		return x.CoverUrl
+	}
+	return ""
+}
+
+func (x *Book) GetListPrice() *Money {
+	if x != nil {
+		return x.ListPrice
+	}
+	return nil
+}
+
+func (x *Book) GetAuthorIds() []string {
+	if x != nil {
+		return x.AuthorIds
+	}
+	return nil
+}
+
+func (x *Book) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+func (x *Book) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Book) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *Book) GetRatingCount() int64 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Book) GetEffectivePrice() *Money {
+	if x != nil {
+		return x.EffectivePrice
+	}
+	return nil
+}
 
-var file_book_message_proto_rawDesc = []byte{
-	0x0a, 0x12, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5a, 0x0a, 0x04, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x0e, 0x0a, 0x02,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06,
-	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75,
-	0x74, 0x68, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72,
-	0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
-	0x42, 0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *Book) GetPriceRuleId() string {
+	if x != nil {
+		return x.PriceRuleId
+	}
+	return ""
 }
 
+var File_book_message_proto protoreflect.FileDescriptor
+
+const file_book_message_proto_rawDesc = "" +
+	"\n" +
+	"\x12book_message.proto\x1a\x13money_message.proto\"\xd1\x04\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\x05price\x18\x04 \x01(\x05B\x02\x18\x01R\x05price\x12\x12\n" +
+	"\x04isbn\x18\x05 \x01(\tR\x04isbn\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1c\n" +
+	"\tpublisher\x18\a \x01(\tR\tpublisher\x12)\n" +
+	"\x10publication_year\x18\b \x01(\x05R\x0fpublicationYear\x12\x1a\n" +
+	"\blanguage\x18\t \x01(\tR\blanguage\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\n" +
+	" \x01(\x05R\tpageCount\x12\x1b\n" +
+	"\tcover_url\x18\v \x01(\tR\bcoverUrl\x12%\n" +
+	"\n" +
+	"list_price\x18\f \x01(\v2\x06.MoneyR\tlistPrice\x12\x1d\n" +
+	"\n" +
+	"author_ids\x18\r \x03(\tR\tauthorIds\x12!\n" +
+	"\fcategory_ids\x18\x0e \x03(\tR\vcategoryIds\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x12%\n" +
+	"\x0erating_average\x18\x10 \x01(\x01R\rratingAverage\x12!\n" +
+	"\frating_count\x18\x11 \x01(\x03R\vratingCount\x12/\n" +
+	"\x0feffective_price\x18\x12 \x01(\v2\x06.MoneyR\x0eeffectivePrice\x12\"\n" +
+	"\rprice_rule_id\x18\x13 \x01(\tR\vpriceRuleIdB\x06Z\x04.;pbb\x06proto3"
+
 var (
 	file_book_message_proto_rawDescOnce sync.Once
-	file_book_message_proto_rawDescData = file_book_message_proto_rawDesc
+	file_book_message_proto_rawDescData []byte
 )
 
 func file_book_message_proto_rawDescGZIP() []byte {
 	file_book_message_proto_rawDescOnce.Do(func() {
-		file_book_message_proto_rawDescData = protoimpl.X.CompressGZIP(file_book_message_proto_rawDescData)
+		file_book_message_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_book_message_proto_rawDesc), len(file_book_message_proto_rawDesc)))
 	})
 	return file_book_message_proto_rawDescData
 }
 
 var file_book_message_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
-var file_book_message_proto_goTypes = []interface{}{
-	(*Book)(nil), // 0: Book
+var file_book_message_proto_goTypes = []any{
+	(*Book)(nil),  // 0: Book
+	(*Money)(nil), // 1: Money
 }
 var file_book_message_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: Book.list_price:type_name -> Money
+	1, // 1: Book.effective_price:type_name -> Money
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_book_message_proto_init() }
@@ -133,25 +288,12 @@ func file_book_message_proto_init() {
 	if File_book_message_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_book_message_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Book); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
+	file_money_message_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_book_message_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_book_message_proto_rawDesc), len(file_book_message_proto_rawDesc)),
 			NumEnums:      0,
 			NumMessages:   1,
 			NumExtensions: 0,
@@ -162,7 +304,6 @@ func file_book_message_proto_init() {
 		MessageInfos:      file_book_message_proto_msgTypes,
 	}.Build()
 	File_book_message_proto = out.File
-	file_book_message_proto_rawDesc = nil
 	file_book_message_proto_goTypes = nil
 	file_book_message_proto_depIdxs = nil
 }
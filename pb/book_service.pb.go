@@ -1,17 +1,21 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.14.0
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: book_service.proto
 
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,21 +25,67 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SearchBookRequest_Sort int32
+
+const (
+	SearchBookRequest_SORT_UNSPECIFIED SearchBookRequest_Sort = 0
+	SearchBookRequest_RATING_DESC      SearchBookRequest_Sort = 1
+	SearchBookRequest_RATING_ASC       SearchBookRequest_Sort = 2
+)
+
+// Enum value maps for SearchBookRequest_Sort.
+var (
+	SearchBookRequest_Sort_name = map[int32]string{
+		0: "SORT_UNSPECIFIED",
+		1: "RATING_DESC",
+		2: "RATING_ASC",
+	}
+	SearchBookRequest_Sort_value = map[string]int32{
+		"SORT_UNSPECIFIED": 0,
+		"RATING_DESC":      1,
+		"RATING_ASC":       2,
+	}
+)
+
+func (x SearchBookRequest_Sort) Enum() *SearchBookRequest_Sort {
+	p := new(SearchBookRequest_Sort)
+	*p = x
+	return p
+}
+
+func (x SearchBookRequest_Sort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SearchBookRequest_Sort) Descriptor() protoreflect.EnumDescriptor {
+	return file_book_service_proto_enumTypes[0].Descriptor()
+}
+
+func (SearchBookRequest_Sort) Type() protoreflect.EnumType {
+	return &file_book_service_proto_enumTypes[0]
+}
+
+func (x SearchBookRequest_Sort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SearchBookRequest_Sort.Descriptor instead.
+func (SearchBookRequest_Sort) EnumDescriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{9, 0}
+}
+
 type CreateBookRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateBookRequest) Reset() {
 	*x = CreateBookRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CreateBookRequest) String() string {
@@ -46,7 +96,7 @@ func (*CreateBookRequest) ProtoMessage() {}
 
 func (x *CreateBookRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -69,20 +119,17 @@ func (x *CreateBookRequest) GetBook() *Book {
 }
 
 type CreateBookResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateBookResponse) Reset() {
 	*x = CreateBookResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CreateBookResponse) String() string {
@@ -93,7 +140,7 @@ func (*CreateBookResponse) ProtoMessage() {}
 
 func (x *CreateBookResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -116,20 +163,19 @@ func (x *CreateBookResponse) GetId() string {
 }
 
 type ReadBookRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Reads the book as it was at this time instead of its current state.
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadBookRequest) Reset() {
 	*x = ReadBookRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ReadBookRequest) String() string {
@@ -140,7 +186,7 @@ func (*ReadBookRequest) ProtoMessage() {}
 
 func (x *ReadBookRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -162,21 +208,25 @@ func (x *ReadBookRequest) GetId() string {
 	return ""
 }
 
+func (x *ReadBookRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
 type ReadBookResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadBookResponse) Reset() {
 	*x = ReadBookResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ReadBookResponse) String() string {
@@ -187,7 +237,7 @@ func (*ReadBookResponse) ProtoMessage() {}
 
 func (x *ReadBookResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -210,20 +260,17 @@ func (x *ReadBookResponse) GetBook() *Book {
 }
 
 type ReadBooksResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadBooksResponse) Reset() {
 	*x = ReadBooksResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ReadBooksResponse) String() string {
@@ -234,7 +281,7 @@ func (*ReadBooksResponse) ProtoMessage() {}
 
 func (x *ReadBooksResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -257,21 +304,23 @@ func (x *ReadBooksResponse) GetBook() *Book {
 }
 
 type UpdateBookRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Book  *Book                  `protobuf:"bytes,2,opt,name=book,proto3" json:"book,omitempty"`
+	// Book fields to change, e.g. "title" or "list_price"; the others keep
+	// their stored values. A nested path such as "list_price.units" changes
+	// its whole top-level field. An empty mask or "*" replaces the whole
+	// book. REST PATCH fills the mask from the fields of the body.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Book *Book  `protobuf:"bytes,2,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateBookRequest) Reset() {
 	*x = UpdateBookRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *UpdateBookRequest) String() string {
@@ -282,7 +331,7 @@ func (*UpdateBookRequest) ProtoMessage() {}
 
 func (x *UpdateBookRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -311,21 +360,25 @@ func (x *UpdateBookRequest) GetBook() *Book {
 	return nil
 }
 
+func (x *UpdateBookRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 type UpdateBookResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateBookResponse) Reset() {
 	*x = UpdateBookResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *UpdateBookResponse) String() string {
@@ -336,7 +389,7 @@ func (*UpdateBookResponse) ProtoMessage() {}
 
 func (x *UpdateBookResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -359,20 +412,17 @@ func (x *UpdateBookResponse) GetBook() *Book {
 }
 
 type DeleteBookRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteBookRequest) Reset() {
 	*x = DeleteBookRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DeleteBookRequest) String() string {
@@ -383,7 +433,7 @@ func (*DeleteBookRequest) ProtoMessage() {}
 
 func (x *DeleteBookRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -406,20 +456,17 @@ func (x *DeleteBookRequest) GetId() string {
 }
 
 type DeleteBookResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteBookResponse) Reset() {
 	*x = DeleteBookResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DeleteBookResponse) String() string {
@@ -430,7 +477,7 @@ func (*DeleteBookResponse) ProtoMessage() {}
 
 func (x *DeleteBookResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -453,20 +500,21 @@ func (x *DeleteBookResponse) GetBook() *Book {
 }
 
 type SearchBookRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *Filter                `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Sends the facet counts of the matching books after the last book, in
+	// a response of their own.
+	IncludeFacets bool                   `protobuf:"varint,2,opt,name=include_facets,json=includeFacets,proto3" json:"include_facets,omitempty"`
+	Sort          SearchBookRequest_Sort `protobuf:"varint,3,opt,name=sort,proto3,enum=SearchBookRequest_Sort" json:"sort,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Filter *Filter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchBookRequest) Reset() {
 	*x = SearchBookRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *SearchBookRequest) String() string {
@@ -477,7 +525,7 @@ func (*SearchBookRequest) ProtoMessage() {}
 
 func (x *SearchBookRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -499,21 +547,33 @@ func (x *SearchBookRequest) GetFilter() *Filter {
 	return nil
 }
 
+func (x *SearchBookRequest) GetIncludeFacets() bool {
+	if x != nil {
+		return x.IncludeFacets
+	}
+	return false
+}
+
+func (x *SearchBookRequest) GetSort() SearchBookRequest_Sort {
+	if x != nil {
+		return x.Sort
+	}
+	return SearchBookRequest_SORT_UNSPECIFIED
+}
+
 type SearchBookResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	Facets        *Facets                `protobuf:"bytes,2,opt,name=facets,proto3" json:"facets,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Book *Book `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchBookResponse) Reset() {
 	*x = SearchBookResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_book_service_proto_msgTypes[10]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_book_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *SearchBookResponse) String() string {
@@ -524,7 +584,7 @@ func (*SearchBookResponse) ProtoMessage() {}
 
 func (x *SearchBookResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_book_service_proto_msgTypes[10]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -546,286 +606,1702 @@ func (x *SearchBookResponse) GetBook() *Book {
 	return nil
 }
 
-var File_book_service_proto protoreflect.FileDescriptor
+func (x *SearchBookResponse) GetFacets() *Facets {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
 
-var file_book_service_proto_rawDesc = []byte{
-	0x0a, 0x12, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
-	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2e, 0x0a, 0x11, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05,
-	0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x22, 0x24, 0x0a, 0x12, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x22, 0x21, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x2d, 0x0a, 0x10, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6f, 0x6f, 0x6b,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6b,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62,
-	0x6f, 0x6f, 0x6b, 0x22, 0x2e, 0x0a, 0x11, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6b,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62,
-	0x6f, 0x6f, 0x6b, 0x22, 0x3e, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f,
-	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6b,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62,
-	0x6f, 0x6f, 0x6b, 0x22, 0x2f, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f,
-	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f,
-	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04,
-	0x62, 0x6f, 0x6f, 0x6b, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6f,
-	0x6f, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2f, 0x0a, 0x12, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e,
-	0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x22, 0x34, 0x0a, 0x11, 0x53, 0x65,
-	0x61, 0x72, 0x63, 0x68, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1f, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x07, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x22, 0x2f, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x04, 0x62, 0x6f, 0x6f,
-	0x6b, 0x32, 0xd7, 0x02, 0x0a, 0x0b, 0x42, 0x6f, 0x6f, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x35, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x12,
-	0x12, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x52, 0x65, 0x61, 0x64,
-	0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x10, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6f, 0x6f,
-	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x09, 0x52, 0x65, 0x61,
-	0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x12,
-	0x2e, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x30, 0x01, 0x12, 0x35, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6f,
-	0x6f, 0x6b, 0x12, 0x12, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42,
-	0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x12, 0x2e, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6f, 0x6f, 0x6b,
-	0x12, 0x12, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6f, 0x6f, 0x6b, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6f, 0x6f,
-	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x06, 0x5a, 0x04, 0x2e,
-	0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+type ListTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var (
-	file_book_service_proto_rawDescOnce sync.Once
-	file_book_service_proto_rawDescData = file_book_service_proto_rawDesc
-)
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_book_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func file_book_service_proto_rawDescGZIP() []byte {
-	file_book_service_proto_rawDescOnce.Do(func() {
-		file_book_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_book_service_proto_rawDescData)
-	})
-	return file_book_service_proto_rawDescData
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_book_service_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
-var file_book_service_proto_goTypes = []interface{}{
-	(*CreateBookRequest)(nil),  // 0: CreateBookRequest
-	(*CreateBookResponse)(nil), // 1: CreateBookResponse
-	(*ReadBookRequest)(nil),    // 2: ReadBookRequest
-	(*ReadBookResponse)(nil),   // 3: ReadBookResponse
-	(*ReadBooksResponse)(nil),  // 4: ReadBooksResponse
-	(*UpdateBookRequest)(nil),  // 5: UpdateBookRequest
-	(*UpdateBookResponse)(nil), // 6: UpdateBookResponse
-	(*DeleteBookRequest)(nil),  // 7: DeleteBookRequest
-	(*DeleteBookResponse)(nil), // 8: DeleteBookResponse
-	(*SearchBookRequest)(nil),  // 9: SearchBookRequest
-	(*SearchBookResponse)(nil), // 10: SearchBookResponse
-	(*Book)(nil),               // 11: Book
-	(*Filter)(nil),             // 12: Filter
-	(*emptypb.Empty)(nil),      // 13: google.protobuf.Empty
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_book_service_proto_depIdxs = []int32{
-	11, // 0: CreateBookRequest.book:type_name -> Book
-	11, // 1: ReadBookResponse.book:type_name -> Book
-	11, // 2: ReadBooksResponse.book:type_name -> Book
-	11, // 3: UpdateBookRequest.book:type_name -> Book
-	11, // 4: UpdateBookResponse.book:type_name -> Book
-	11, // 5: DeleteBookResponse.book:type_name -> Book
-	12, // 6: SearchBookRequest.filter:type_name -> Filter
-	11, // 7: SearchBookResponse.book:type_name -> Book
-	0,  // 8: BookService.CreateBook:input_type -> CreateBookRequest
-	2,  // 9: BookService.ReadBook:input_type -> ReadBookRequest
-	13, // 10: BookService.ReadBooks:input_type -> google.protobuf.Empty
-	5,  // 11: BookService.UpdateBook:input_type -> UpdateBookRequest
-	7,  // 12: BookService.DeleteBook:input_type -> DeleteBookRequest
-	9,  // 13: BookService.SearchBook:input_type -> SearchBookRequest
-	1,  // 14: BookService.CreateBook:output_type -> CreateBookResponse
-	3,  // 15: BookService.ReadBook:output_type -> ReadBookResponse
-	4,  // 16: BookService.ReadBooks:output_type -> ReadBooksResponse
-	6,  // 17: BookService.UpdateBook:output_type -> UpdateBookResponse
-	8,  // 18: BookService.DeleteBook:output_type -> DeleteBookResponse
-	10, // 19: BookService.SearchBook:output_type -> SearchBookResponse
-	14, // [14:20] is the sub-list for method output_type
-	8,  // [8:14] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{11}
 }
 
-func init() { file_book_service_proto_init() }
-func file_book_service_proto_init() {
-	if File_book_service_proto != nil {
-		return
+// Tags with the number of books that carry them, most used first.
+type ListTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []*FacetCount          `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsResponse) Reset() {
+	*x = ListTagsResponse{}
+	mi := &file_book_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsResponse) ProtoMessage() {}
+
+func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	file_book_message_proto_init()
-	file_filter_message_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_book_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateBookRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateBookResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadBookRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadBookResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadBooksResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateBookRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateBookResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBookRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBookResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchBookRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_book_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SearchBookResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListTagsResponse) GetTags() []*FacetCount {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// In atomic mode a batch either succeeds as a whole or fails with ABORTED
+// and nothing is written. Otherwise every item is applied independently
+// and its outcome is reported in statuses, in request order.
+type BatchItemStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Index int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// google.rpc.Code of the item, 0 (OK) on success.
+	Code          int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Book          *Book  `protobuf:"bytes,4,opt,name=book,proto3" json:"book,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemStatus) Reset() {
+	*x = BatchItemStatus{}
+	mi := &file_book_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemStatus) ProtoMessage() {}
+
+func (x *BatchItemStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemStatus.ProtoReflect.Descriptor instead.
+func (*BatchItemStatus) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchItemStatus) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchItemStatus) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchItemStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BatchItemStatus) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+type BatchCreateBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	Atomic        bool                   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateBooksRequest) Reset() {
+	*x = BatchCreateBooksRequest{}
+	mi := &file_book_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateBooksRequest) ProtoMessage() {}
+
+func (x *BatchCreateBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateBooksRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateBooksRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchCreateBooksRequest) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *BatchCreateBooksRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+type BatchCreateBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*BatchItemStatus     `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateBooksResponse) Reset() {
+	*x = BatchCreateBooksResponse{}
+	mi := &file_book_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateBooksResponse) ProtoMessage() {}
+
+func (x *BatchCreateBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateBooksResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateBooksResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchCreateBooksResponse) GetStatuses() []*BatchItemStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type BatchUpdateBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*UpdateBookRequest   `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	Atomic        bool                   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchUpdateBooksRequest) Reset() {
+	*x = BatchUpdateBooksRequest{}
+	mi := &file_book_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUpdateBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUpdateBooksRequest) ProtoMessage() {}
+
+func (x *BatchUpdateBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUpdateBooksRequest.ProtoReflect.Descriptor instead.
+func (*BatchUpdateBooksRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchUpdateBooksRequest) GetRequests() []*UpdateBookRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *BatchUpdateBooksRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+type BatchUpdateBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*BatchItemStatus     `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchUpdateBooksResponse) Reset() {
+	*x = BatchUpdateBooksResponse{}
+	mi := &file_book_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUpdateBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUpdateBooksResponse) ProtoMessage() {}
+
+func (x *BatchUpdateBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUpdateBooksResponse.ProtoReflect.Descriptor instead.
+func (*BatchUpdateBooksResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchUpdateBooksResponse) GetStatuses() []*BatchItemStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type BatchDeleteBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Repeats of an id fail with INVALID_ARGUMENT; its first occurrence is
+	// deleted.
+	Ids           []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Atomic        bool     `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteBooksRequest) Reset() {
+	*x = BatchDeleteBooksRequest{}
+	mi := &file_book_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteBooksRequest) ProtoMessage() {}
+
+func (x *BatchDeleteBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteBooksRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteBooksRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BatchDeleteBooksRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BatchDeleteBooksRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+type BatchDeleteBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*BatchItemStatus     `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteBooksResponse) Reset() {
+	*x = BatchDeleteBooksResponse{}
+	mi := &file_book_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteBooksResponse) ProtoMessage() {}
+
+func (x *BatchDeleteBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteBooksResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteBooksResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BatchDeleteBooksResponse) GetStatuses() []*BatchItemStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type ImportBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Book  *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	// chunk_size and upsert are read from the first message of the stream.
+	ChunkSize     int32 `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	Upsert        bool  `protobuf:"varint,3,opt,name=upsert,proto3" json:"upsert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportBooksRequest) Reset() {
+	*x = ImportBooksRequest{}
+	mi := &file_book_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBooksRequest) ProtoMessage() {}
+
+func (x *ImportBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBooksRequest.ProtoReflect.Descriptor instead.
+func (*ImportBooksRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ImportBooksRequest) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+func (x *ImportBooksRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *ImportBooksRequest) GetUpsert() bool {
+	if x != nil {
+		return x.Upsert
+	}
+	return false
+}
+
+type ImportError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the book in the request stream, starting from 0.
+	Index         int64  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	BookId        string `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	mi := &file_book_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportError) ProtoMessage() {}
+
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImportError) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportError) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *ImportError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ImportBooksResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Created int64                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Updated int64                  `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"`
+	Failed  int64                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	// The first failed rows, not all of them.
+	Errors        []*ImportError `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportBooksResponse) Reset() {
+	*x = ImportBooksResponse{}
+	mi := &file_book_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBooksResponse) ProtoMessage() {}
+
+func (x *ImportBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBooksResponse.ProtoReflect.Descriptor instead.
+func (*ImportBooksResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ImportBooksResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportBooksResponse) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportBooksResponse) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportBooksResponse) GetErrors() []*ImportError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// BookSession runs commands in the order they are received and answers each
+// with a response carrying the same tag. A failed command is reported in its
+// response and does not end the session.
+type BookSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tag   string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Types that are valid to be assigned to Command:
+	//
+	//	*BookSessionRequest_Create
+	//	*BookSessionRequest_Read
+	//	*BookSessionRequest_Update
+	//	*BookSessionRequest_Delete
+	Command       isBookSessionRequest_Command `protobuf_oneof:"command"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookSessionRequest) Reset() {
+	*x = BookSessionRequest{}
+	mi := &file_book_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookSessionRequest) ProtoMessage() {}
+
+func (x *BookSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookSessionRequest.ProtoReflect.Descriptor instead.
+func (*BookSessionRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BookSessionRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *BookSessionRequest) GetCommand() isBookSessionRequest_Command {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *BookSessionRequest) GetCreate() *CreateBookRequest {
+	if x != nil {
+		if x, ok := x.Command.(*BookSessionRequest_Create); ok {
+			return x.Create
+		}
+	}
+	return nil
+}
+
+func (x *BookSessionRequest) GetRead() *ReadBookRequest {
+	if x != nil {
+		if x, ok := x.Command.(*BookSessionRequest_Read); ok {
+			return x.Read
+		}
+	}
+	return nil
+}
+
+func (x *BookSessionRequest) GetUpdate() *UpdateBookRequest {
+	if x != nil {
+		if x, ok := x.Command.(*BookSessionRequest_Update); ok {
+			return x.Update
+		}
+	}
+	return nil
+}
+
+func (x *BookSessionRequest) GetDelete() *DeleteBookRequest {
+	if x != nil {
+		if x, ok := x.Command.(*BookSessionRequest_Delete); ok {
+			return x.Delete
+		}
+	}
+	return nil
+}
+
+type isBookSessionRequest_Command interface {
+	isBookSessionRequest_Command()
+}
+
+type BookSessionRequest_Create struct {
+	Create *CreateBookRequest `protobuf:"bytes,2,opt,name=create,proto3,oneof"`
+}
+
+type BookSessionRequest_Read struct {
+	Read *ReadBookRequest `protobuf:"bytes,3,opt,name=read,proto3,oneof"`
+}
+
+type BookSessionRequest_Update struct {
+	Update *UpdateBookRequest `protobuf:"bytes,4,opt,name=update,proto3,oneof"`
+}
+
+type BookSessionRequest_Delete struct {
+	Delete *DeleteBookRequest `protobuf:"bytes,5,opt,name=delete,proto3,oneof"`
+}
+
+func (*BookSessionRequest_Create) isBookSessionRequest_Command() {}
+
+func (*BookSessionRequest_Read) isBookSessionRequest_Command() {}
+
+func (*BookSessionRequest_Update) isBookSessionRequest_Command() {}
+
+func (*BookSessionRequest_Delete) isBookSessionRequest_Command() {}
+
+type BookSessionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tag   string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// google.rpc.Code of the command, 0 (OK) on success.
+	Code    int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BookSessionResponse_Create
+	//	*BookSessionResponse_Read
+	//	*BookSessionResponse_Update
+	//	*BookSessionResponse_Delete
+	Result        isBookSessionResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookSessionResponse) Reset() {
+	*x = BookSessionResponse{}
+	mi := &file_book_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookSessionResponse) ProtoMessage() {}
+
+func (x *BookSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookSessionResponse.ProtoReflect.Descriptor instead.
+func (*BookSessionResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BookSessionResponse) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *BookSessionResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BookSessionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BookSessionResponse) GetResult() isBookSessionResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BookSessionResponse) GetCreate() *CreateBookResponse {
+	if x != nil {
+		if x, ok := x.Result.(*BookSessionResponse_Create); ok {
+			return x.Create
+		}
+	}
+	return nil
+}
+
+func (x *BookSessionResponse) GetRead() *ReadBookResponse {
+	if x != nil {
+		if x, ok := x.Result.(*BookSessionResponse_Read); ok {
+			return x.Read
+		}
+	}
+	return nil
+}
+
+func (x *BookSessionResponse) GetUpdate() *UpdateBookResponse {
+	if x != nil {
+		if x, ok := x.Result.(*BookSessionResponse_Update); ok {
+			return x.Update
+		}
+	}
+	return nil
+}
+
+func (x *BookSessionResponse) GetDelete() *DeleteBookResponse {
+	if x != nil {
+		if x, ok := x.Result.(*BookSessionResponse_Delete); ok {
+			return x.Delete
+		}
+	}
+	return nil
+}
+
+type isBookSessionResponse_Result interface {
+	isBookSessionResponse_Result()
+}
+
+type BookSessionResponse_Create struct {
+	Create *CreateBookResponse `protobuf:"bytes,4,opt,name=create,proto3,oneof"`
+}
+
+type BookSessionResponse_Read struct {
+	Read *ReadBookResponse `protobuf:"bytes,5,opt,name=read,proto3,oneof"`
+}
+
+type BookSessionResponse_Update struct {
+	Update *UpdateBookResponse `protobuf:"bytes,6,opt,name=update,proto3,oneof"`
+}
+
+type BookSessionResponse_Delete struct {
+	Delete *DeleteBookResponse `protobuf:"bytes,7,opt,name=delete,proto3,oneof"`
+}
+
+func (*BookSessionResponse_Create) isBookSessionResponse_Result() {}
+
+func (*BookSessionResponse_Read) isBookSessionResponse_Result() {}
+
+func (*BookSessionResponse_Update) isBookSessionResponse_Result() {}
+
+func (*BookSessionResponse_Delete) isBookSessionResponse_Result() {}
+
+type WatchBooksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only events whose book matches the filter before or after the change
+	// are sent.
+	Filter *Filter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Replays the events after this sequence before streaming new ones;
+	// 0 starts with the next event.
+	SinceSequence uint64 `protobuf:"varint,2,opt,name=since_sequence,json=sinceSequence,proto3" json:"since_sequence,omitempty"`
+	// The epoch of the event since_sequence was taken from. A sequence from
+	// another epoch fails with OUT_OF_RANGE, and the client has to resync.
+	SinceEpoch    string `protobuf:"bytes,3,opt,name=since_epoch,json=sinceEpoch,proto3" json:"since_epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchBooksRequest) Reset() {
+	*x = WatchBooksRequest{}
+	mi := &file_book_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBooksRequest) ProtoMessage() {}
+
+func (x *WatchBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBooksRequest.ProtoReflect.Descriptor instead.
+func (*WatchBooksRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *WatchBooksRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *WatchBooksRequest) GetSinceSequence() uint64 {
+	if x != nil {
+		return x.SinceSequence
+	}
+	return 0
+}
+
+func (x *WatchBooksRequest) GetSinceEpoch() string {
+	if x != nil {
+		return x.SinceEpoch
+	}
+	return ""
+}
+
+type WatchBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *BookEvent             `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchBooksResponse) Reset() {
+	*x = WatchBooksResponse{}
+	mi := &file_book_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBooksResponse) ProtoMessage() {}
+
+func (x *WatchBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBooksResponse.ProtoReflect.Descriptor instead.
+func (*WatchBooksResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchBooksResponse) GetEvent() *BookEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// BookRevision is the state of a book after one of its changes. Revisions
+// are numbered from 1 per book id and never change.
+type BookRevision struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	BookId   string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Revision int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Type     BookEvent_Type         `protobuf:"varint,3,opt,name=type,proto3,enum=BookEvent_Type" json:"type,omitempty"`
+	// Empty for DELETED.
+	Book          *Book                  `protobuf:"bytes,4,opt,name=book,proto3" json:"book,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookRevision) Reset() {
+	*x = BookRevision{}
+	mi := &file_book_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookRevision) ProtoMessage() {}
+
+func (x *BookRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookRevision.ProtoReflect.Descriptor instead.
+func (*BookRevision) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BookRevision) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *BookRevision) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *BookRevision) GetType() BookEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return BookEvent_TYPE_UNSPECIFIED
+}
+
+func (x *BookRevision) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+func (x *BookRevision) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type ListBookRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookRevisionsRequest) Reset() {
+	*x = ListBookRevisionsRequest{}
+	mi := &file_book_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookRevisionsRequest) ProtoMessage() {}
+
+func (x *ListBookRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListBookRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListBookRevisionsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Oldest first.
+type ListBookRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*BookRevision        `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookRevisionsResponse) Reset() {
+	*x = ListBookRevisionsResponse{}
+	mi := &file_book_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookRevisionsResponse) ProtoMessage() {}
+
+func (x *ListBookRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListBookRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListBookRevisionsResponse) GetRevisions() []*BookRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type GetBookRevisionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRevisionRequest) Reset() {
+	*x = GetBookRevisionRequest{}
+	mi := &file_book_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRevisionRequest) ProtoMessage() {}
+
+func (x *GetBookRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRevisionRequest.ProtoReflect.Descriptor instead.
+func (*GetBookRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetBookRevisionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetBookRevisionRequest) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type GetBookRevisionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      *BookRevision          `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookRevisionResponse) Reset() {
+	*x = GetBookRevisionResponse{}
+	mi := &file_book_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookRevisionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookRevisionResponse) ProtoMessage() {}
+
+func (x *GetBookRevisionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookRevisionResponse.ProtoReflect.Descriptor instead.
+func (*GetBookRevisionResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetBookRevisionResponse) GetRevision() *BookRevision {
+	if x != nil {
+		return x.Revision
+	}
+	return nil
+}
+
+// RollbackBook restores the book as it was in revision; the restored state
+// is stored as a new revision.
+type RollbackBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackBookRequest) Reset() {
+	*x = RollbackBookRequest{}
+	mi := &file_book_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackBookRequest) ProtoMessage() {}
+
+func (x *RollbackBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackBookRequest.ProtoReflect.Descriptor instead.
+func (*RollbackBookRequest) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RollbackBookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RollbackBookRequest) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type RollbackBookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackBookResponse) Reset() {
+	*x = RollbackBookResponse{}
+	mi := &file_book_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackBookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackBookResponse) ProtoMessage() {}
+
+func (x *RollbackBookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_book_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackBookResponse.ProtoReflect.Descriptor instead.
+func (*RollbackBookResponse) Descriptor() ([]byte, []int) {
+	return file_book_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RollbackBookResponse) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+var File_book_service_proto protoreflect.FileDescriptor
+
+const file_book_service_proto_rawDesc = "" +
+	"\n" +
+	"\x12book_service.proto\x1a\x12book_message.proto\x1a\x13event_message.proto\x1a\x13facet_message.proto\x1a\x14filter_message.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\".\n" +
+	"\x11CreateBookRequest\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\"$\n" +
+	"\x12CreateBookResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"R\n" +
+	"\x0fReadBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\x05as_of\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"-\n" +
+	"\x10ReadBookResponse\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\".\n" +
+	"\x11ReadBooksResponse\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\"{\n" +
+	"\x11UpdateBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\x04book\x18\x02 \x01(\v2\x05.BookR\x04book\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"/\n" +
+	"\x12UpdateBookResponse\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\"#\n" +
+	"\x11DeleteBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
+	"\x12DeleteBookResponse\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\"\xc7\x01\n" +
+	"\x11SearchBookRequest\x12\x1f\n" +
+	"\x06filter\x18\x01 \x01(\v2\a.FilterR\x06filter\x12%\n" +
+	"\x0einclude_facets\x18\x02 \x01(\bR\rincludeFacets\x12+\n" +
+	"\x04sort\x18\x03 \x01(\x0e2\x17.SearchBookRequest.SortR\x04sort\"=\n" +
+	"\x04Sort\x12\x14\n" +
+	"\x10SORT_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vRATING_DESC\x10\x01\x12\x0e\n" +
+	"\n" +
+	"RATING_ASC\x10\x02\"P\n" +
+	"\x12SearchBookResponse\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\x12\x1f\n" +
+	"\x06facets\x18\x02 \x01(\v2\a.FacetsR\x06facets\"\x11\n" +
+	"\x0fListTagsRequest\"3\n" +
+	"\x10ListTagsResponse\x12\x1f\n" +
+	"\x04tags\x18\x01 \x03(\v2\v.FacetCountR\x04tags\"p\n" +
+	"\x0fBatchItemStatus\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x19\n" +
+	"\x04book\x18\x04 \x01(\v2\x05.BookR\x04book\"N\n" +
+	"\x17BatchCreateBooksRequest\x12\x1b\n" +
+	"\x05books\x18\x01 \x03(\v2\x05.BookR\x05books\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\"H\n" +
+	"\x18BatchCreateBooksResponse\x12,\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x10.BatchItemStatusR\bstatuses\"a\n" +
+	"\x17BatchUpdateBooksRequest\x12.\n" +
+	"\brequests\x18\x01 \x03(\v2\x12.UpdateBookRequestR\brequests\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\"H\n" +
+	"\x18BatchUpdateBooksResponse\x12,\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x10.BatchItemStatusR\bstatuses\"C\n" +
+	"\x17BatchDeleteBooksRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\"H\n" +
+	"\x18BatchDeleteBooksResponse\x12,\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x10.BatchItemStatusR\bstatuses\"f\n" +
+	"\x12ImportBooksRequest\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x02 \x01(\x05R\tchunkSize\x12\x16\n" +
+	"\x06upsert\x18\x03 \x01(\bR\x06upsert\"V\n" +
+	"\vImportError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x87\x01\n" +
+	"\x13ImportBooksResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x03R\acreated\x12\x18\n" +
+	"\aupdated\x18\x02 \x01(\x03R\aupdated\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x03R\x06failed\x12$\n" +
+	"\x06errors\x18\x04 \x03(\v2\f.ImportErrorR\x06errors\"\xe3\x01\n" +
+	"\x12BookSessionRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12,\n" +
+	"\x06create\x18\x02 \x01(\v2\x12.CreateBookRequestH\x00R\x06create\x12&\n" +
+	"\x04read\x18\x03 \x01(\v2\x10.ReadBookRequestH\x00R\x04read\x12,\n" +
+	"\x06update\x18\x04 \x01(\v2\x12.UpdateBookRequestH\x00R\x06update\x12,\n" +
+	"\x06delete\x18\x05 \x01(\v2\x12.DeleteBookRequestH\x00R\x06deleteB\t\n" +
+	"\acommand\"\x95\x02\n" +
+	"\x13BookSessionResponse\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12-\n" +
+	"\x06create\x18\x04 \x01(\v2\x13.CreateBookResponseH\x00R\x06create\x12'\n" +
+	"\x04read\x18\x05 \x01(\v2\x11.ReadBookResponseH\x00R\x04read\x12-\n" +
+	"\x06update\x18\x06 \x01(\v2\x13.UpdateBookResponseH\x00R\x06update\x12-\n" +
+	"\x06delete\x18\a \x01(\v2\x13.DeleteBookResponseH\x00R\x06deleteB\b\n" +
+	"\x06result\"|\n" +
+	"\x11WatchBooksRequest\x12\x1f\n" +
+	"\x06filter\x18\x01 \x01(\v2\a.FilterR\x06filter\x12%\n" +
+	"\x0esince_sequence\x18\x02 \x01(\x04R\rsinceSequence\x12\x1f\n" +
+	"\vsince_epoch\x18\x03 \x01(\tR\n" +
+	"sinceEpoch\"6\n" +
+	"\x12WatchBooksResponse\x12 \n" +
+	"\x05event\x18\x01 \x01(\v2\n" +
+	".BookEventR\x05event\"\xb3\x01\n" +
+	"\fBookRevision\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\x12#\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x0f.BookEvent.TypeR\x04type\x12\x19\n" +
+	"\x04book\x18\x04 \x01(\v2\x05.BookR\x04book\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"*\n" +
+	"\x18ListBookRevisionsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"H\n" +
+	"\x19ListBookRevisionsResponse\x12+\n" +
+	"\trevisions\x18\x01 \x03(\v2\r.BookRevisionR\trevisions\"D\n" +
+	"\x16GetBookRevisionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\"D\n" +
+	"\x17GetBookRevisionResponse\x12)\n" +
+	"\brevision\x18\x01 \x01(\v2\r.BookRevisionR\brevision\"A\n" +
+	"\x13RollbackBookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\"1\n" +
+	"\x14RollbackBookResponse\x12\x19\n" +
+	"\x04book\x18\x01 \x01(\v2\x05.BookR\x04book2\xfd\n" +
+	"\n" +
+	"\vBookService\x12N\n" +
+	"\n" +
+	"CreateBook\x12\x12.CreateBookRequest\x1a\x13.CreateBookResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x04book\"\t/v1/books\x12G\n" +
+	"\bReadBook\x12\x10.ReadBookRequest\x1a\x11.ReadBookResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/books/{id}\x12L\n" +
+	"\tReadBooks\x12\x16.google.protobuf.Empty\x1a\x12.ReadBooksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/books0\x01\x12S\n" +
+	"\n" +
+	"UpdateBook\x12\x12.UpdateBookRequest\x1a\x13.UpdateBookResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x04book2\x0e/v1/books/{id}\x12M\n" +
+	"\n" +
+	"DeleteBook\x12\x12.DeleteBookRequest\x1a\x13.DeleteBookResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/books/{id}\x12Q\n" +
+	"\n" +
+	"SearchBook\x12\x12.SearchBookRequest\x1a\x13.SearchBookResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/books:search0\x01\x12i\n" +
+	"\x10BatchCreateBooks\x12\x18.BatchCreateBooksRequest\x1a\x19.BatchCreateBooksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/books:batchCreate\x12i\n" +
+	"\x10BatchUpdateBooks\x12\x18.BatchUpdateBooksRequest\x1a\x19.BatchUpdateBooksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/books:batchUpdate\x12i\n" +
+	"\x10BatchDeleteBooks\x12\x18.BatchDeleteBooksRequest\x1a\x19.BatchDeleteBooksResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/books:batchDelete\x12:\n" +
+	"\vImportBooks\x12\x13.ImportBooksRequest\x1a\x14.ImportBooksResponse(\x01\x12<\n" +
+	"\vBookSession\x12\x13.BookSessionRequest\x1a\x14.BookSessionResponse(\x010\x01\x12P\n" +
+	"\n" +
+	"WatchBooks\x12\x12.WatchBooksRequest\x1a\x13.WatchBooksResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/books:watch0\x01\x12l\n" +
+	"\x11ListBookRevisions\x12\x19.ListBookRevisionsRequest\x1a\x1a.ListBookRevisionsResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/v1/books/{id}/revisions\x12q\n" +
+	"\x0fGetBookRevision\x12\x17.GetBookRevisionRequest\x1a\x18.GetBookRevisionResponse\"+\x82\xd3\xe4\x93\x02%\x12#/v1/books/{id}/revisions/{revision}\x12_\n" +
+	"\fRollbackBook\x12\x14.RollbackBookRequest\x1a\x15.RollbackBookResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/books/{id}:rollback\x12A\n" +
+	"\bListTags\x12\x10.ListTagsRequest\x1a\x11.ListTagsResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/v1/tagsB\x06Z\x04.;pbb\x06proto3"
+
+var (
+	file_book_service_proto_rawDescOnce sync.Once
+	file_book_service_proto_rawDescData []byte
+)
+
+func file_book_service_proto_rawDescGZIP() []byte {
+	file_book_service_proto_rawDescOnce.Do(func() {
+		file_book_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_book_service_proto_rawDesc), len(file_book_service_proto_rawDesc)))
+	})
+	return file_book_service_proto_rawDescData
+}
+
+var file_book_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_book_service_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_book_service_proto_goTypes = []any{
+	(SearchBookRequest_Sort)(0),       // 0: SearchBookRequest.Sort
+	(*CreateBookRequest)(nil),         // 1: CreateBookRequest
+	(*CreateBookResponse)(nil),        // 2: CreateBookResponse
+	(*ReadBookRequest)(nil),           // 3: ReadBookRequest
+	(*ReadBookResponse)(nil),          // 4: ReadBookResponse
+	(*ReadBooksResponse)(nil),         // 5: ReadBooksResponse
+	(*UpdateBookRequest)(nil),         // 6: UpdateBookRequest
+	(*UpdateBookResponse)(nil),        // 7: UpdateBookResponse
+	(*DeleteBookRequest)(nil),         // 8: DeleteBookRequest
+	(*DeleteBookResponse)(nil),        // 9: DeleteBookResponse
+	(*SearchBookRequest)(nil),         // 10: SearchBookRequest
+	(*SearchBookResponse)(nil),        // 11: SearchBookResponse
+	(*ListTagsRequest)(nil),           // 12: ListTagsRequest
+	(*ListTagsResponse)(nil),          // 13: ListTagsResponse
+	(*BatchItemStatus)(nil),           // 14: BatchItemStatus
+	(*BatchCreateBooksRequest)(nil),   // 15: BatchCreateBooksRequest
+	(*BatchCreateBooksResponse)(nil),  // 16: BatchCreateBooksResponse
+	(*BatchUpdateBooksRequest)(nil),   // 17: BatchUpdateBooksRequest
+	(*BatchUpdateBooksResponse)(nil),  // 18: BatchUpdateBooksResponse
+	(*BatchDeleteBooksRequest)(nil),   // 19: BatchDeleteBooksRequest
+	(*BatchDeleteBooksResponse)(nil),  // 20: BatchDeleteBooksResponse
+	(*ImportBooksRequest)(nil),        // 21: ImportBooksRequest
+	(*ImportError)(nil),               // 22: ImportError
+	(*ImportBooksResponse)(nil),       // 23: ImportBooksResponse
+	(*BookSessionRequest)(nil),        // 24: BookSessionRequest
+	(*BookSessionResponse)(nil),       // 25: BookSessionResponse
+	(*WatchBooksRequest)(nil),         // 26: WatchBooksRequest
+	(*WatchBooksResponse)(nil),        // 27: WatchBooksResponse
+	(*BookRevision)(nil),              // 28: BookRevision
+	(*ListBookRevisionsRequest)(nil),  // 29: ListBookRevisionsRequest
+	(*ListBookRevisionsResponse)(nil), // 30: ListBookRevisionsResponse
+	(*GetBookRevisionRequest)(nil),    // 31: GetBookRevisionRequest
+	(*GetBookRevisionResponse)(nil),   // 32: GetBookRevisionResponse
+	(*RollbackBookRequest)(nil),       // 33: RollbackBookRequest
+	(*RollbackBookResponse)(nil),      // 34: RollbackBookResponse
+	(*Book)(nil),                      // 35: Book
+	(*timestamppb.Timestamp)(nil),     // 36: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),     // 37: google.protobuf.FieldMask
+	(*Filter)(nil),                    // 38: Filter
+	(*Facets)(nil),                    // 39: Facets
+	(*FacetCount)(nil),                // 40: FacetCount
+	(*BookEvent)(nil),                 // 41: BookEvent
+	(BookEvent_Type)(0),               // 42: BookEvent.Type
+	(*emptypb.Empty)(nil),             // 43: google.protobuf.Empty
+}
+var file_book_service_proto_depIdxs = []int32{
+	35, // 0: CreateBookRequest.book:type_name -> Book
+	36, // 1: ReadBookRequest.as_of:type_name -> google.protobuf.Timestamp
+	35, // 2: ReadBookResponse.book:type_name -> Book
+	35, // 3: ReadBooksResponse.book:type_name -> Book
+	35, // 4: UpdateBookRequest.book:type_name -> Book
+	37, // 5: UpdateBookRequest.update_mask:type_name -> google.protobuf.FieldMask
+	35, // 6: UpdateBookResponse.book:type_name -> Book
+	35, // 7: DeleteBookResponse.book:type_name -> Book
+	38, // 8: SearchBookRequest.filter:type_name -> Filter
+	0,  // 9: SearchBookRequest.sort:type_name -> SearchBookRequest.Sort
+	35, // 10: SearchBookResponse.book:type_name -> Book
+	39, // 11: SearchBookResponse.facets:type_name -> Facets
+	40, // 12: ListTagsResponse.tags:type_name -> FacetCount
+	35, // 13: BatchItemStatus.book:type_name -> Book
+	35, // 14: BatchCreateBooksRequest.books:type_name -> Book
+	14, // 15: BatchCreateBooksResponse.statuses:type_name -> BatchItemStatus
+	6,  // 16: BatchUpdateBooksRequest.requests:type_name -> UpdateBookRequest
+	14, // 17: BatchUpdateBooksResponse.statuses:type_name -> BatchItemStatus
+	14, // 18: BatchDeleteBooksResponse.statuses:type_name -> BatchItemStatus
+	35, // 19: ImportBooksRequest.book:type_name -> Book
+	22, // 20: ImportBooksResponse.errors:type_name -> ImportError
+	1,  // 21: BookSessionRequest.create:type_name -> CreateBookRequest
+	3,  // 22: BookSessionRequest.read:type_name -> ReadBookRequest
+	6,  // 23: BookSessionRequest.update:type_name -> UpdateBookRequest
+	8,  // 24: BookSessionRequest.delete:type_name -> DeleteBookRequest
+	2,  // 25: BookSessionResponse.create:type_name -> CreateBookResponse
+	4,  // 26: BookSessionResponse.read:type_name -> ReadBookResponse
+	7,  // 27: BookSessionResponse.update:type_name -> UpdateBookResponse
+	9,  // 28: BookSessionResponse.delete:type_name -> DeleteBookResponse
+	38, // 29: WatchBooksRequest.filter:type_name -> Filter
+	41, // 30: WatchBooksResponse.event:type_name -> BookEvent
+	42, // 31: BookRevision.type:type_name -> BookEvent.Type
+	35, // 32: BookRevision.book:type_name -> Book
+	36, // 33: BookRevision.time:type_name -> google.protobuf.Timestamp
+	28, // 34: ListBookRevisionsResponse.revisions:type_name -> BookRevision
+	28, // 35: GetBookRevisionResponse.revision:type_name -> BookRevision
+	35, // 36: RollbackBookResponse.book:type_name -> Book
+	1,  // 37: BookService.CreateBook:input_type -> CreateBookRequest
+	3,  // 38: BookService.ReadBook:input_type -> ReadBookRequest
+	43, // 39: BookService.ReadBooks:input_type -> google.protobuf.Empty
+	6,  // 40: BookService.UpdateBook:input_type -> UpdateBookRequest
+	8,  // 41: BookService.DeleteBook:input_type -> DeleteBookRequest
+	10, // 42: BookService.SearchBook:input_type -> SearchBookRequest
+	15, // 43: BookService.BatchCreateBooks:input_type -> BatchCreateBooksRequest
+	17, // 44: BookService.BatchUpdateBooks:input_type -> BatchUpdateBooksRequest
+	19, // 45: BookService.BatchDeleteBooks:input_type -> BatchDeleteBooksRequest
+	21, // 46: BookService.ImportBooks:input_type -> ImportBooksRequest
+	24, // 47: BookService.BookSession:input_type -> BookSessionRequest
+	26, // 48: BookService.WatchBooks:input_type -> WatchBooksRequest
+	29, // 49: BookService.ListBookRevisions:input_type -> ListBookRevisionsRequest
+	31, // 50: BookService.GetBookRevision:input_type -> GetBookRevisionRequest
+	33, // 51: BookService.RollbackBook:input_type -> RollbackBookRequest
+	12, // 52: BookService.ListTags:input_type -> ListTagsRequest
+	2,  // 53: BookService.CreateBook:output_type -> CreateBookResponse
+	4,  // 54: BookService.ReadBook:output_type -> ReadBookResponse
+	5,  // 55: BookService.ReadBooks:output_type -> ReadBooksResponse
+	7,  // 56: BookService.UpdateBook:output_type -> UpdateBookResponse
+	9,  // 57: BookService.DeleteBook:output_type -> DeleteBookResponse
+	11, // 58: BookService.SearchBook:output_type -> SearchBookResponse
+	16, // 59: BookService.BatchCreateBooks:output_type -> BatchCreateBooksResponse
+	18, // 60: BookService.BatchUpdateBooks:output_type -> BatchUpdateBooksResponse
+	20, // 61: BookService.BatchDeleteBooks:output_type -> BatchDeleteBooksResponse
+	23, // 62: BookService.ImportBooks:output_type -> ImportBooksResponse
+	25, // 63: BookService.BookSession:output_type -> BookSessionResponse
+	27, // 64: BookService.WatchBooks:output_type -> WatchBooksResponse
+	30, // 65: BookService.ListBookRevisions:output_type -> ListBookRevisionsResponse
+	32, // 66: BookService.GetBookRevision:output_type -> GetBookRevisionResponse
+	34, // 67: BookService.RollbackBook:output_type -> RollbackBookResponse
+	13, // 68: BookService.ListTags:output_type -> ListTagsResponse
+	53, // [53:69] is the sub-list for method output_type
+	37, // [37:53] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_book_service_proto_init() }
+func file_book_service_proto_init() {
+	if File_book_service_proto != nil {
+		return
+	}
+	file_book_message_proto_init()
+	file_event_message_proto_init()
+	file_facet_message_proto_init()
+	file_filter_message_proto_init()
+	file_book_service_proto_msgTypes[23].OneofWrappers = []any{
+		(*BookSessionRequest_Create)(nil),
+		(*BookSessionRequest_Read)(nil),
+		(*BookSessionRequest_Update)(nil),
+		(*BookSessionRequest_Delete)(nil),
+	}
+	file_book_service_proto_msgTypes[24].OneofWrappers = []any{
+		(*BookSessionResponse_Create)(nil),
+		(*BookSessionResponse_Read)(nil),
+		(*BookSessionResponse_Update)(nil),
+		(*BookSessionResponse_Delete)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_book_service_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   11,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_book_service_proto_rawDesc), len(file_book_service_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_book_service_proto_goTypes,
 		DependencyIndexes: file_book_service_proto_depIdxs,
+		EnumInfos:         file_book_service_proto_enumTypes,
 		MessageInfos:      file_book_service_proto_msgTypes,
 	}.Build()
 	File_book_service_proto = out.File
-	file_book_service_proto_rawDesc = nil
 	file_book_service_proto_goTypes = nil
 	file_book_service_proto_depIdxs = nil
 }
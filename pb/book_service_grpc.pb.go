@@ -25,6 +25,16 @@ type BookServiceClient interface {
 	UpdateBook(ctx context.Context, in *UpdateBookRequest, opts ...grpc.CallOption) (*UpdateBookResponse, error)
 	DeleteBook(ctx context.Context, in *DeleteBookRequest, opts ...grpc.CallOption) (*DeleteBookResponse, error)
 	SearchBook(ctx context.Context, in *SearchBookRequest, opts ...grpc.CallOption) (BookService_SearchBookClient, error)
+	BatchCreateBooks(ctx context.Context, in *BatchCreateBooksRequest, opts ...grpc.CallOption) (*BatchCreateBooksResponse, error)
+	BatchUpdateBooks(ctx context.Context, in *BatchUpdateBooksRequest, opts ...grpc.CallOption) (*BatchUpdateBooksResponse, error)
+	BatchDeleteBooks(ctx context.Context, in *BatchDeleteBooksRequest, opts ...grpc.CallOption) (*BatchDeleteBooksResponse, error)
+	ImportBooks(ctx context.Context, opts ...grpc.CallOption) (BookService_ImportBooksClient, error)
+	BookSession(ctx context.Context, opts ...grpc.CallOption) (BookService_BookSessionClient, error)
+	WatchBooks(ctx context.Context, in *WatchBooksRequest, opts ...grpc.CallOption) (BookService_WatchBooksClient, error)
+	ListBookRevisions(ctx context.Context, in *ListBookRevisionsRequest, opts ...grpc.CallOption) (*ListBookRevisionsResponse, error)
+	GetBookRevision(ctx context.Context, in *GetBookRevisionRequest, opts ...grpc.CallOption) (*GetBookRevisionResponse, error)
+	RollbackBook(ctx context.Context, in *RollbackBookRequest, opts ...grpc.CallOption) (*RollbackBookResponse, error)
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
 }
 
 type bookServiceClient struct {
@@ -135,6 +145,166 @@ func (x *bookServiceSearchBookClient) Recv() (*SearchBookResponse, error) {
 	return m, nil
 }
 
+func (c *bookServiceClient) BatchCreateBooks(ctx context.Context, in *BatchCreateBooksRequest, opts ...grpc.CallOption) (*BatchCreateBooksResponse, error) {
+	out := new(BatchCreateBooksResponse)
+	err := c.cc.Invoke(ctx, "/BookService/BatchCreateBooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) BatchUpdateBooks(ctx context.Context, in *BatchUpdateBooksRequest, opts ...grpc.CallOption) (*BatchUpdateBooksResponse, error) {
+	out := new(BatchUpdateBooksResponse)
+	err := c.cc.Invoke(ctx, "/BookService/BatchUpdateBooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) BatchDeleteBooks(ctx context.Context, in *BatchDeleteBooksRequest, opts ...grpc.CallOption) (*BatchDeleteBooksResponse, error) {
+	out := new(BatchDeleteBooksResponse)
+	err := c.cc.Invoke(ctx, "/BookService/BatchDeleteBooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ImportBooks(ctx context.Context, opts ...grpc.CallOption) (BookService_ImportBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[2], "/BookService/ImportBooks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceImportBooksClient{stream}
+	return x, nil
+}
+
+type BookService_ImportBooksClient interface {
+	Send(*ImportBooksRequest) error
+	CloseAndRecv() (*ImportBooksResponse, error)
+	grpc.ClientStream
+}
+
+type bookServiceImportBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceImportBooksClient) Send(m *ImportBooksRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *bookServiceImportBooksClient) CloseAndRecv() (*ImportBooksResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportBooksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) BookSession(ctx context.Context, opts ...grpc.CallOption) (BookService_BookSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[3], "/BookService/BookSession", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceBookSessionClient{stream}
+	return x, nil
+}
+
+type BookService_BookSessionClient interface {
+	Send(*BookSessionRequest) error
+	Recv() (*BookSessionResponse, error)
+	grpc.ClientStream
+}
+
+type bookServiceBookSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceBookSessionClient) Send(m *BookSessionRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *bookServiceBookSessionClient) Recv() (*BookSessionResponse, error) {
+	m := new(BookSessionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) WatchBooks(ctx context.Context, in *WatchBooksRequest, opts ...grpc.CallOption) (BookService_WatchBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[4], "/BookService/WatchBooks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceWatchBooksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookService_WatchBooksClient interface {
+	Recv() (*WatchBooksResponse, error)
+	grpc.ClientStream
+}
+
+type bookServiceWatchBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceWatchBooksClient) Recv() (*WatchBooksResponse, error) {
+	m := new(WatchBooksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) ListBookRevisions(ctx context.Context, in *ListBookRevisionsRequest, opts ...grpc.CallOption) (*ListBookRevisionsResponse, error) {
+	out := new(ListBookRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/BookService/ListBookRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetBookRevision(ctx context.Context, in *GetBookRevisionRequest, opts ...grpc.CallOption) (*GetBookRevisionResponse, error) {
+	out := new(GetBookRevisionResponse)
+	err := c.cc.Invoke(ctx, "/BookService/GetBookRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) RollbackBook(ctx context.Context, in *RollbackBookRequest, opts ...grpc.CallOption) (*RollbackBookResponse, error) {
+	out := new(RollbackBookResponse)
+	err := c.cc.Invoke(ctx, "/BookService/RollbackBook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error) {
+	out := new(ListTagsResponse)
+	err := c.cc.Invoke(ctx, "/BookService/ListTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BookServiceServer is the server API for BookService service.
 // All implementations must embed UnimplementedBookServiceServer
 // for forward compatibility
@@ -145,6 +315,16 @@ type BookServiceServer interface {
 	UpdateBook(context.Context, *UpdateBookRequest) (*UpdateBookResponse, error)
 	DeleteBook(context.Context, *DeleteBookRequest) (*DeleteBookResponse, error)
 	SearchBook(*SearchBookRequest, BookService_SearchBookServer) error
+	BatchCreateBooks(context.Context, *BatchCreateBooksRequest) (*BatchCreateBooksResponse, error)
+	BatchUpdateBooks(context.Context, *BatchUpdateBooksRequest) (*BatchUpdateBooksResponse, error)
+	BatchDeleteBooks(context.Context, *BatchDeleteBooksRequest) (*BatchDeleteBooksResponse, error)
+	ImportBooks(BookService_ImportBooksServer) error
+	BookSession(BookService_BookSessionServer) error
+	WatchBooks(*WatchBooksRequest, BookService_WatchBooksServer) error
+	ListBookRevisions(context.Context, *ListBookRevisionsRequest) (*ListBookRevisionsResponse, error)
+	GetBookRevision(context.Context, *GetBookRevisionRequest) (*GetBookRevisionResponse, error)
+	RollbackBook(context.Context, *RollbackBookRequest) (*RollbackBookResponse, error)
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
 	mustEmbedUnimplementedBookServiceServer()
 }
 
@@ -170,6 +350,36 @@ func (UnimplementedBookServiceServer) DeleteBook(context.Context, *DeleteBookReq
 func (UnimplementedBookServiceServer) SearchBook(*SearchBookRequest, BookService_SearchBookServer) error {
 	return status.Errorf(codes.Unimplemented, "method SearchBook not implemented")
 }
+func (UnimplementedBookServiceServer) BatchCreateBooks(context.Context, *BatchCreateBooksRequest) (*BatchCreateBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateBooks not implemented")
+}
+func (UnimplementedBookServiceServer) BatchUpdateBooks(context.Context, *BatchUpdateBooksRequest) (*BatchUpdateBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateBooks not implemented")
+}
+func (UnimplementedBookServiceServer) BatchDeleteBooks(context.Context, *BatchDeleteBooksRequest) (*BatchDeleteBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteBooks not implemented")
+}
+func (UnimplementedBookServiceServer) ImportBooks(BookService_ImportBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBooks not implemented")
+}
+func (UnimplementedBookServiceServer) BookSession(BookService_BookSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method BookSession not implemented")
+}
+func (UnimplementedBookServiceServer) WatchBooks(*WatchBooksRequest, BookService_WatchBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBooks not implemented")
+}
+func (UnimplementedBookServiceServer) ListBookRevisions(context.Context, *ListBookRevisionsRequest) (*ListBookRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBookRevisions not implemented")
+}
+func (UnimplementedBookServiceServer) GetBookRevision(context.Context, *GetBookRevisionRequest) (*GetBookRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBookRevision not implemented")
+}
+func (UnimplementedBookServiceServer) RollbackBook(context.Context, *RollbackBookRequest) (*RollbackBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackBook not implemented")
+}
+func (UnimplementedBookServiceServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
 func (UnimplementedBookServiceServer) mustEmbedUnimplementedBookServiceServer() {}
 
 // UnsafeBookServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -297,6 +507,205 @@ func (x *bookServiceSearchBookServer) Send(m *SearchBookResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _BookService_BatchCreateBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).BatchCreateBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/BatchCreateBooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).BatchCreateBooks(ctx, req.(*BatchCreateBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_BatchUpdateBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).BatchUpdateBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/BatchUpdateBooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).BatchUpdateBooks(ctx, req.(*BatchUpdateBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_BatchDeleteBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).BatchDeleteBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/BatchDeleteBooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).BatchDeleteBooks(ctx, req.(*BatchDeleteBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ImportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookServiceServer).ImportBooks(&bookServiceImportBooksServer{stream})
+}
+
+type BookService_ImportBooksServer interface {
+	SendAndClose(*ImportBooksResponse) error
+	Recv() (*ImportBooksRequest, error)
+	grpc.ServerStream
+}
+
+type bookServiceImportBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceImportBooksServer) SendAndClose(m *ImportBooksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *bookServiceImportBooksServer) Recv() (*ImportBooksRequest, error) {
+	m := new(ImportBooksRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BookService_BookSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookServiceServer).BookSession(&bookServiceBookSessionServer{stream})
+}
+
+type BookService_BookSessionServer interface {
+	Send(*BookSessionResponse) error
+	Recv() (*BookSessionRequest, error)
+	grpc.ServerStream
+}
+
+type bookServiceBookSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceBookSessionServer) Send(m *BookSessionResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *bookServiceBookSessionServer) Recv() (*BookSessionRequest, error) {
+	m := new(BookSessionRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BookService_WatchBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookServiceServer).WatchBooks(m, &bookServiceWatchBooksServer{stream})
+}
+
+type BookService_WatchBooksServer interface {
+	Send(*WatchBooksResponse) error
+	grpc.ServerStream
+}
+
+type bookServiceWatchBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceWatchBooksServer) Send(m *WatchBooksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookService_ListBookRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBookRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListBookRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/ListBookRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListBookRevisions(ctx, req.(*ListBookRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetBookRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBookRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetBookRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/GetBookRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetBookRevision(ctx, req.(*GetBookRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_RollbackBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).RollbackBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/RollbackBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).RollbackBook(ctx, req.(*RollbackBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BookService/ListTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BookService_ServiceDesc is the grpc.ServiceDesc for BookService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -320,6 +729,34 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBook",
 			Handler:    _BookService_DeleteBook_Handler,
 		},
+		{
+			MethodName: "BatchCreateBooks",
+			Handler:    _BookService_BatchCreateBooks_Handler,
+		},
+		{
+			MethodName: "BatchUpdateBooks",
+			Handler:    _BookService_BatchUpdateBooks_Handler,
+		},
+		{
+			MethodName: "BatchDeleteBooks",
+			Handler:    _BookService_BatchDeleteBooks_Handler,
+		},
+		{
+			MethodName: "ListBookRevisions",
+			Handler:    _BookService_ListBookRevisions_Handler,
+		},
+		{
+			MethodName: "GetBookRevision",
+			Handler:    _BookService_GetBookRevision_Handler,
+		},
+		{
+			MethodName: "RollbackBook",
+			Handler:    _BookService_RollbackBook_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _BookService_ListTags_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -332,6 +769,22 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _BookService_SearchBook_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ImportBooks",
+			Handler:       _BookService_ImportBooks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BookSession",
+			Handler:       _BookService_BookSession_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchBooks",
+			Handler:       _BookService_WatchBooks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "book_service.proto",
 }
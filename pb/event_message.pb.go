@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: event_message.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BookEvent_Type int32
+
+const (
+	BookEvent_TYPE_UNSPECIFIED BookEvent_Type = 0
+	BookEvent_CREATED          BookEvent_Type = 1
+	BookEvent_UPDATED          BookEvent_Type = 2
+	BookEvent_DELETED          BookEvent_Type = 3
+	// The available stock of a book in a warehouse fell to its low-stock
+	// threshold. Only stock is set.
+	BookEvent_LOW_STOCK BookEvent_Type = 4
+)
+
+// Enum value maps for BookEvent_Type.
+var (
+	BookEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "CREATED",
+		2: "UPDATED",
+		3: "DELETED",
+		4: "LOW_STOCK",
+	}
+	BookEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"CREATED":          1,
+		"UPDATED":          2,
+		"DELETED":          3,
+		"LOW_STOCK":        4,
+	}
+)
+
+func (x BookEvent_Type) Enum() *BookEvent_Type {
+	p := new(BookEvent_Type)
+	*p = x
+	return p
+}
+
+func (x BookEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BookEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_event_message_proto_enumTypes[0].Descriptor()
+}
+
+func (BookEvent_Type) Type() protoreflect.EnumType {
+	return &file_event_message_proto_enumTypes[0]
+}
+
+func (x BookEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BookEvent_Type.Descriptor instead.
+func (BookEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_event_message_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type BookEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Increases by one with every event; pass the last one seen as
+	// since_sequence, with its epoch, to resume a watch.
+	Sequence uint64         `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Type     BookEvent_Type `protobuf:"varint,2,opt,name=type,proto3,enum=BookEvent_Type" json:"type,omitempty"`
+	// Empty for CREATED.
+	Before *Book `protobuf:"bytes,3,opt,name=before,proto3" json:"before,omitempty"`
+	// Empty for DELETED.
+	After *Book                  `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
+	Time  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	// The stock level after the change, for LOW_STOCK.
+	Stock *StockLevel `protobuf:"bytes,6,opt,name=stock,proto3" json:"stock,omitempty"`
+	// Identifies the server process that numbered the event. Sequences
+	// start again from 1 in every epoch, e.g. after a restart.
+	Epoch         string `protobuf:"bytes,7,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookEvent) Reset() {
+	*x = BookEvent{}
+	mi := &file_event_message_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookEvent) ProtoMessage() {}
+
+func (x *BookEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_event_message_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookEvent.ProtoReflect.Descriptor instead.
+func (*BookEvent) Descriptor() ([]byte, []int) {
+	return file_event_message_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BookEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *BookEvent) GetType() BookEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return BookEvent_TYPE_UNSPECIFIED
+}
+
+func (x *BookEvent) GetBefore() *Book {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *BookEvent) GetAfter() *Book {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *BookEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *BookEvent) GetStock() *StockLevel {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+func (x *BookEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+var File_event_message_proto protoreflect.FileDescriptor
+
+const file_event_message_proto_rawDesc = "" +
+	"\n" +
+	"\x13event_message.proto\x1a\x12book_message.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x13stock_message.proto\"\xc5\x02\n" +
+	"\tBookEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12#\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x0f.BookEvent.TypeR\x04type\x12\x1d\n" +
+	"\x06before\x18\x03 \x01(\v2\x05.BookR\x06before\x12\x1b\n" +
+	"\x05after\x18\x04 \x01(\v2\x05.BookR\x05after\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12!\n" +
+	"\x05stock\x18\x06 \x01(\v2\v.StockLevelR\x05stock\x12\x14\n" +
+	"\x05epoch\x18\a \x01(\tR\x05epoch\"R\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCREATED\x10\x01\x12\v\n" +
+	"\aUPDATED\x10\x02\x12\v\n" +
+	"\aDELETED\x10\x03\x12\r\n" +
+	"\tLOW_STOCK\x10\x04B\x06Z\x04.;pbb\x06proto3"
+
+var (
+	file_event_message_proto_rawDescOnce sync.Once
+	file_event_message_proto_rawDescData []byte
+)
+
+func file_event_message_proto_rawDescGZIP() []byte {
+	file_event_message_proto_rawDescOnce.Do(func() {
+		file_event_message_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_event_message_proto_rawDesc), len(file_event_message_proto_rawDesc)))
+	})
+	return file_event_message_proto_rawDescData
+}
+
+var file_event_message_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_event_message_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_event_message_proto_goTypes = []any{
+	(BookEvent_Type)(0),           // 0: BookEvent.Type
+	(*BookEvent)(nil),             // 1: BookEvent
+	(*Book)(nil),                  // 2: Book
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*StockLevel)(nil),            // 4: StockLevel
+}
+var file_event_message_proto_depIdxs = []int32{
+	0, // 0: BookEvent.type:type_name -> BookEvent.Type
+	2, // 1: BookEvent.before:type_name -> Book
+	2, // 2: BookEvent.after:type_name -> Book
+	3, // 3: BookEvent.time:type_name -> google.protobuf.Timestamp
+	4, // 4: BookEvent.stock:type_name -> StockLevel
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_event_message_proto_init() }
+func file_event_message_proto_init() {
+	if File_event_message_proto != nil {
+		return
+	}
+	file_book_message_proto_init()
+	file_stock_message_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_event_message_proto_rawDesc), len(file_event_message_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_event_message_proto_goTypes,
+		DependencyIndexes: file_event_message_proto_depIdxs,
+		EnumInfos:         file_event_message_proto_enumTypes,
+		MessageInfos:      file_event_message_proto_msgTypes,
+	}.Build()
+	File_event_message_proto = out.File
+	file_event_message_proto_goTypes = nil
+	file_event_message_proto_depIdxs = nil
+}
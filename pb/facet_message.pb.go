@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: facet_message.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FacetCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The id of the category or author, or the tag.
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	// The name of the category or author.
+	Label         string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Count         int64  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FacetCount) Reset() {
+	*x = FacetCount{}
+	mi := &file_facet_message_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FacetCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FacetCount) ProtoMessage() {}
+
+func (x *FacetCount) ProtoReflect() protoreflect.Message {
+	mi := &file_facet_message_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FacetCount.ProtoReflect.Descriptor instead.
+func (*FacetCount) Descriptor() ([]byte, []int) {
+	return file_facet_message_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FacetCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FacetCount) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *FacetCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PriceBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Inclusive lower bound.
+	Min *Money `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	// Exclusive upper bound, unset for the last bucket.
+	Max           *Money `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	Count         int64  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceBucket) Reset() {
+	*x = PriceBucket{}
+	mi := &file_facet_message_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceBucket) ProtoMessage() {}
+
+func (x *PriceBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_facet_message_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceBucket.ProtoReflect.Descriptor instead.
+func (*PriceBucket) Descriptor() ([]byte, []int) {
+	return file_facet_message_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PriceBucket) GetMin() *Money {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *PriceBucket) GetMax() *Money {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+func (x *PriceBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Facets count the books matching a search.
+type Facets struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A book counts towards its categories and all their ancestors.
+	Categories []*FacetCount `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	Authors    []*FacetCount `protobuf:"bytes,2,rep,name=authors,proto3" json:"authors,omitempty"`
+	// Buckets are per currency.
+	PriceBuckets  []*PriceBucket `protobuf:"bytes,3,rep,name=price_buckets,json=priceBuckets,proto3" json:"price_buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Facets) Reset() {
+	*x = Facets{}
+	mi := &file_facet_message_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Facets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Facets) ProtoMessage() {}
+
+func (x *Facets) ProtoReflect() protoreflect.Message {
+	mi := &file_facet_message_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Facets.ProtoReflect.Descriptor instead.
+func (*Facets) Descriptor() ([]byte, []int) {
+	return file_facet_message_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Facets) GetCategories() []*FacetCount {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Facets) GetAuthors() []*FacetCount {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+func (x *Facets) GetPriceBuckets() []*PriceBucket {
+	if x != nil {
+		return x.PriceBuckets
+	}
+	return nil
+}
+
+var File_facet_message_proto protoreflect.FileDescriptor
+
+const file_facet_message_proto_rawDesc = "" +
+	"\n" +
+	"\x13facet_message.proto\x1a\x13money_message.proto\"N\n" +
+	"\n" +
+	"FacetCount\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"W\n" +
+	"\vPriceBucket\x12\x18\n" +
+	"\x03min\x18\x01 \x01(\v2\x06.MoneyR\x03min\x12\x18\n" +
+	"\x03max\x18\x02 \x01(\v2\x06.MoneyR\x03max\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"\x8f\x01\n" +
+	"\x06Facets\x12+\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\v.FacetCountR\n" +
+	"categories\x12%\n" +
+	"\aauthors\x18\x02 \x03(\v2\v.FacetCountR\aauthors\x121\n" +
+	"\rprice_buckets\x18\x03 \x03(\v2\f.PriceBucketR\fpriceBucketsB\x06Z\x04.;pbb\x06proto3"
+
+var (
+	file_facet_message_proto_rawDescOnce sync.Once
+	file_facet_message_proto_rawDescData []byte
+)
+
+func file_facet_message_proto_rawDescGZIP() []byte {
+	file_facet_message_proto_rawDescOnce.Do(func() {
+		file_facet_message_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_facet_message_proto_rawDesc), len(file_facet_message_proto_rawDesc)))
+	})
+	return file_facet_message_proto_rawDescData
+}
+
+var file_facet_message_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_facet_message_proto_goTypes = []any{
+	(*FacetCount)(nil),  // 0: FacetCount
+	(*PriceBucket)(nil), // 1: PriceBucket
+	(*Facets)(nil),      // 2: Facets
+	(*Money)(nil),       // 3: Money
+}
+var file_facet_message_proto_depIdxs = []int32{
+	3, // 0: PriceBucket.min:type_name -> Money
+	3, // 1: PriceBucket.max:type_name -> Money
+	0, // 2: Facets.categories:type_name -> FacetCount
+	0, // 3: Facets.authors:type_name -> FacetCount
+	1, // 4: Facets.price_buckets:type_name -> PriceBucket
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_facet_message_proto_init() }
+func file_facet_message_proto_init() {
+	if File_facet_message_proto != nil {
+		return
+	}
+	file_money_message_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_facet_message_proto_rawDesc), len(file_facet_message_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_facet_message_proto_goTypes,
+		DependencyIndexes: file_facet_message_proto_depIdxs,
+		MessageInfos:      file_facet_message_proto_msgTypes,
+	}.Build()
+	File_facet_message_proto = out.File
+	file_facet_message_proto_goTypes = nil
+	file_facet_message_proto_depIdxs = nil
+}
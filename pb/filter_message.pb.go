@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.14.0
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: filter_message.proto
 
 package pb
@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -20,22 +21,44 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Empty fields match every book.
 type Filter struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matches the author text of the book and the names and aliases of its
+	// authors.
 	Author string `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
-	Price  int32  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	// Matches books priced higher than this many units of the default
+	// currency (RUB), the currency legacy prices are written in.
+	//
+	// Deprecated: Marked as deprecated in filter_message.proto.
+	Price           int32  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	Isbn            string `protobuf:"bytes,3,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Publisher       string `protobuf:"bytes,4,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	PublicationYear int32  `protobuf:"varint,5,opt,name=publication_year,json=publicationYear,proto3" json:"publication_year,omitempty"`
+	Language        string `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	// Inclusive price bounds. They only match books priced in the same
+	// currency and must use the same currency if both are set.
+	MinPrice *Money `protobuf:"bytes,7,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice *Money `protobuf:"bytes,8,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	AuthorId string `protobuf:"bytes,9,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Matches books in this category or any of its subcategories.
+	CategoryId string `protobuf:"bytes,10,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Tag        string `protobuf:"bytes,11,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Matches books whose average rating is at least this.
+	MinRating float64 `protobuf:"fixed64,12,opt,name=min_rating,json=minRating,proto3" json:"min_rating,omitempty"`
+	// Inclusive bounds on effective_price, with the same currency rules as
+	// min_price and max_price. WatchBooks ignores them.
+	MinEffectivePrice *Money `protobuf:"bytes,13,opt,name=min_effective_price,json=minEffectivePrice,proto3" json:"min_effective_price,omitempty"`
+	MaxEffectivePrice *Money `protobuf:"bytes,14,opt,name=max_effective_price,json=maxEffectivePrice,proto3" json:"max_effective_price,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Filter) Reset() {
 	*x = Filter{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filter_message_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_filter_message_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Filter) String() string {
@@ -46,7 +69,7 @@ func (*Filter) ProtoMessage() {}
 
 func (x *Filter) ProtoReflect() protoreflect.Message {
 	mi := &file_filter_message_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -68,6 +91,7 @@ func (x *Filter) GetAuthor() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in filter_message.proto.
 func (x *Filter) GetPrice() int32 {
 	if x != nil {
 		return x.Price
@@ -75,39 +99,141 @@ func (x *Filter) GetPrice() int32 {
 	return 0
 }
 
-var File_filter_message_proto protoreflect.FileDescriptor
+func (x *Filter) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Filter) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *Filter) GetPublicationYear() int32 {
+	if x != nil {
+		return x.PublicationYear
+	}
+	return 0
+}
+
+func (x *Filter) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Filter) GetMinPrice() *Money {
+	if x != nil {
+		return x.MinPrice
+	}
+	return nil
+}
+
+func (x *Filter) GetMaxPrice() *Money {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return nil
+}
+
+func (x *Filter) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Filter) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *Filter) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
 
-var file_filter_message_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x36, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x12, 0x16, 0x0a, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x42, 0x06,
-	0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *Filter) GetMinRating() float64 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *Filter) GetMinEffectivePrice() *Money {
+	if x != nil {
+		return x.MinEffectivePrice
+	}
+	return nil
+}
+
+func (x *Filter) GetMaxEffectivePrice() *Money {
+	if x != nil {
+		return x.MaxEffectivePrice
+	}
+	return nil
 }
 
+var File_filter_message_proto protoreflect.FileDescriptor
+
+const file_filter_message_proto_rawDesc = "" +
+	"\n" +
+	"\x14filter_message.proto\x1a\x13money_message.proto\"\xdc\x03\n" +
+	"\x06Filter\x12\x16\n" +
+	"\x06author\x18\x01 \x01(\tR\x06author\x12\x18\n" +
+	"\x05price\x18\x02 \x01(\x05B\x02\x18\x01R\x05price\x12\x12\n" +
+	"\x04isbn\x18\x03 \x01(\tR\x04isbn\x12\x1c\n" +
+	"\tpublisher\x18\x04 \x01(\tR\tpublisher\x12)\n" +
+	"\x10publication_year\x18\x05 \x01(\x05R\x0fpublicationYear\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage\x12#\n" +
+	"\tmin_price\x18\a \x01(\v2\x06.MoneyR\bminPrice\x12#\n" +
+	"\tmax_price\x18\b \x01(\v2\x06.MoneyR\bmaxPrice\x12\x1b\n" +
+	"\tauthor_id\x18\t \x01(\tR\bauthorId\x12\x1f\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\tR\n" +
+	"categoryId\x12\x10\n" +
+	"\x03tag\x18\v \x01(\tR\x03tag\x12\x1d\n" +
+	"\n" +
+	"min_rating\x18\f \x01(\x01R\tminRating\x126\n" +
+	"\x13min_effective_price\x18\r \x01(\v2\x06.MoneyR\x11minEffectivePrice\x126\n" +
+	"\x13max_effective_price\x18\x0e \x01(\v2\x06.MoneyR\x11maxEffectivePriceB\x06Z\x04.;pbb\x06proto3"
+
 var (
 	file_filter_message_proto_rawDescOnce sync.Once
-	file_filter_message_proto_rawDescData = file_filter_message_proto_rawDesc
+	file_filter_message_proto_rawDescData []byte
 )
 
 func file_filter_message_proto_rawDescGZIP() []byte {
 	file_filter_message_proto_rawDescOnce.Do(func() {
-		file_filter_message_proto_rawDescData = protoimpl.X.CompressGZIP(file_filter_message_proto_rawDescData)
+		file_filter_message_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_filter_message_proto_rawDesc), len(file_filter_message_proto_rawDesc)))
 	})
 	return file_filter_message_proto_rawDescData
 }
 
 var file_filter_message_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
-var file_filter_message_proto_goTypes = []interface{}{
+var file_filter_message_proto_goTypes = []any{
 	(*Filter)(nil), // 0: Filter
+	(*Money)(nil),  // 1: Money
 }
 var file_filter_message_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: Filter.min_price:type_name -> Money
+	1, // 1: Filter.max_price:type_name -> Money
+	1, // 2: Filter.min_effective_price:type_name -> Money
+	1, // 3: Filter.max_effective_price:type_name -> Money
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_filter_message_proto_init() }
@@ -115,25 +241,12 @@ func file_filter_message_proto_init() {
 	if File_filter_message_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_filter_message_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Filter); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
+	file_money_message_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_filter_message_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_filter_message_proto_rawDesc), len(file_filter_message_proto_rawDesc)),
 			NumEnums:      0,
 			NumMessages:   1,
 			NumExtensions: 0,
@@ -144,7 +257,6 @@ func file_filter_message_proto_init() {
 		MessageInfos:      file_filter_message_proto_msgTypes,
 	}.Build()
 	File_filter_message_proto = out.File
-	file_filter_message_proto_rawDesc = nil
 	file_filter_message_proto_goTypes = nil
 	file_filter_message_proto_depIdxs = nil
 }
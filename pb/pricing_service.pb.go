@@ -0,0 +1,612 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: pricing_service.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PriceRule_Scope int32
+
+const (
+	PriceRule_SCOPE_UNSPECIFIED PriceRule_Scope = 0
+	PriceRule_BOOK              PriceRule_Scope = 1
+	// Books with the author among their author_ids.
+	PriceRule_AUTHOR PriceRule_Scope = 2
+	// Books in the category or any of its subcategories.
+	PriceRule_CATEGORY PriceRule_Scope = 3
+)
+
+// Enum value maps for PriceRule_Scope.
+var (
+	PriceRule_Scope_name = map[int32]string{
+		0: "SCOPE_UNSPECIFIED",
+		1: "BOOK",
+		2: "AUTHOR",
+		3: "CATEGORY",
+	}
+	PriceRule_Scope_value = map[string]int32{
+		"SCOPE_UNSPECIFIED": 0,
+		"BOOK":              1,
+		"AUTHOR":            2,
+		"CATEGORY":          3,
+	}
+)
+
+func (x PriceRule_Scope) Enum() *PriceRule_Scope {
+	p := new(PriceRule_Scope)
+	*p = x
+	return p
+}
+
+func (x PriceRule_Scope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PriceRule_Scope) Descriptor() protoreflect.EnumDescriptor {
+	return file_pricing_service_proto_enumTypes[0].Descriptor()
+}
+
+func (PriceRule_Scope) Type() protoreflect.EnumType {
+	return &file_pricing_service_proto_enumTypes[0]
+}
+
+func (x PriceRule_Scope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PriceRule_Scope.Descriptor instead.
+func (PriceRule_Scope) EnumDescriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// PriceRule discounts the books in its scope while it is active. When
+// several rules apply to a book, the one giving the lowest price wins;
+// discounts do not stack.
+type PriceRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Generated when empty.
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Types that are valid to be assigned to Discount:
+	//
+	//	*PriceRule_PercentOff
+	//	*PriceRule_AmountOff
+	Discount isPriceRule_Discount `protobuf_oneof:"discount"`
+	Scope    PriceRule_Scope      `protobuf:"varint,5,opt,name=scope,proto3,enum=PriceRule_Scope" json:"scope,omitempty"`
+	ScopeId  string               `protobuf:"bytes,6,opt,name=scope_id,json=scopeId,proto3" json:"scope_id,omitempty"`
+	// Active from start_time, inclusive, until end_time, exclusive. Unset
+	// bounds are open.
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceRule) Reset() {
+	*x = PriceRule{}
+	mi := &file_pricing_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceRule) ProtoMessage() {}
+
+func (x *PriceRule) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceRule.ProtoReflect.Descriptor instead.
+func (*PriceRule) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PriceRule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PriceRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PriceRule) GetDiscount() isPriceRule_Discount {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *PriceRule) GetPercentOff() int32 {
+	if x != nil {
+		if x, ok := x.Discount.(*PriceRule_PercentOff); ok {
+			return x.PercentOff
+		}
+	}
+	return 0
+}
+
+func (x *PriceRule) GetAmountOff() *Money {
+	if x != nil {
+		if x, ok := x.Discount.(*PriceRule_AmountOff); ok {
+			return x.AmountOff
+		}
+	}
+	return nil
+}
+
+func (x *PriceRule) GetScope() PriceRule_Scope {
+	if x != nil {
+		return x.Scope
+	}
+	return PriceRule_SCOPE_UNSPECIFIED
+}
+
+func (x *PriceRule) GetScopeId() string {
+	if x != nil {
+		return x.ScopeId
+	}
+	return ""
+}
+
+func (x *PriceRule) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *PriceRule) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type isPriceRule_Discount interface {
+	isPriceRule_Discount()
+}
+
+type PriceRule_PercentOff struct {
+	// 1 to 100.
+	PercentOff int32 `protobuf:"varint,3,opt,name=percent_off,json=percentOff,proto3,oneof"`
+}
+
+type PriceRule_AmountOff struct {
+	// Only applies to books priced in the same currency. The price never
+	// drops below zero.
+	AmountOff *Money `protobuf:"bytes,4,opt,name=amount_off,json=amountOff,proto3,oneof"`
+}
+
+func (*PriceRule_PercentOff) isPriceRule_Discount() {}
+
+func (*PriceRule_AmountOff) isPriceRule_Discount() {}
+
+type CreatePriceRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceRule     *PriceRule             `protobuf:"bytes,1,opt,name=price_rule,json=priceRule,proto3" json:"price_rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePriceRuleRequest) Reset() {
+	*x = CreatePriceRuleRequest{}
+	mi := &file_pricing_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePriceRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePriceRuleRequest) ProtoMessage() {}
+
+func (x *CreatePriceRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePriceRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreatePriceRuleRequest) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePriceRuleRequest) GetPriceRule() *PriceRule {
+	if x != nil {
+		return x.PriceRule
+	}
+	return nil
+}
+
+type CreatePriceRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceRule     *PriceRule             `protobuf:"bytes,1,opt,name=price_rule,json=priceRule,proto3" json:"price_rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePriceRuleResponse) Reset() {
+	*x = CreatePriceRuleResponse{}
+	mi := &file_pricing_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePriceRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePriceRuleResponse) ProtoMessage() {}
+
+func (x *CreatePriceRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePriceRuleResponse.ProtoReflect.Descriptor instead.
+func (*CreatePriceRuleResponse) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreatePriceRuleResponse) GetPriceRule() *PriceRule {
+	if x != nil {
+		return x.PriceRule
+	}
+	return nil
+}
+
+type ListPriceRulesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only lists the rules active now.
+	ActiveOnly    bool `protobuf:"varint,1,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPriceRulesRequest) Reset() {
+	*x = ListPriceRulesRequest{}
+	mi := &file_pricing_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPriceRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPriceRulesRequest) ProtoMessage() {}
+
+func (x *ListPriceRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPriceRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListPriceRulesRequest) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPriceRulesRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+type ListPriceRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceRules    []*PriceRule           `protobuf:"bytes,1,rep,name=price_rules,json=priceRules,proto3" json:"price_rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPriceRulesResponse) Reset() {
+	*x = ListPriceRulesResponse{}
+	mi := &file_pricing_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPriceRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPriceRulesResponse) ProtoMessage() {}
+
+func (x *ListPriceRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPriceRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListPriceRulesResponse) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPriceRulesResponse) GetPriceRules() []*PriceRule {
+	if x != nil {
+		return x.PriceRules
+	}
+	return nil
+}
+
+type DeletePriceRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePriceRuleRequest) Reset() {
+	*x = DeletePriceRuleRequest{}
+	mi := &file_pricing_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePriceRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePriceRuleRequest) ProtoMessage() {}
+
+func (x *DeletePriceRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePriceRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeletePriceRuleRequest) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeletePriceRuleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeletePriceRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceRule     *PriceRule             `protobuf:"bytes,1,opt,name=price_rule,json=priceRule,proto3" json:"price_rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePriceRuleResponse) Reset() {
+	*x = DeletePriceRuleResponse{}
+	mi := &file_pricing_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePriceRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePriceRuleResponse) ProtoMessage() {}
+
+func (x *DeletePriceRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pricing_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePriceRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeletePriceRuleResponse) Descriptor() ([]byte, []int) {
+	return file_pricing_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeletePriceRuleResponse) GetPriceRule() *PriceRule {
+	if x != nil {
+		return x.PriceRule
+	}
+	return nil
+}
+
+var File_pricing_service_proto protoreflect.FileDescriptor
+
+const file_pricing_service_proto_rawDesc = "" +
+	"\n" +
+	"\x15pricing_service.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x13money_message.proto\"\x80\x03\n" +
+	"\tPriceRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\vpercent_off\x18\x03 \x01(\x05H\x00R\n" +
+	"percentOff\x12'\n" +
+	"\n" +
+	"amount_off\x18\x04 \x01(\v2\x06.MoneyH\x00R\tamountOff\x12&\n" +
+	"\x05scope\x18\x05 \x01(\x0e2\x10.PriceRule.ScopeR\x05scope\x12\x19\n" +
+	"\bscope_id\x18\x06 \x01(\tR\ascopeId\x129\n" +
+	"\n" +
+	"start_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"B\n" +
+	"\x05Scope\x12\x15\n" +
+	"\x11SCOPE_UNSPECIFIED\x10\x00\x12\b\n" +
+	"\x04BOOK\x10\x01\x12\n" +
+	"\n" +
+	"\x06AUTHOR\x10\x02\x12\f\n" +
+	"\bCATEGORY\x10\x03B\n" +
+	"\n" +
+	"\bdiscount\"C\n" +
+	"\x16CreatePriceRuleRequest\x12)\n" +
+	"\n" +
+	"price_rule\x18\x01 \x01(\v2\n" +
+	".PriceRuleR\tpriceRule\"D\n" +
+	"\x17CreatePriceRuleResponse\x12)\n" +
+	"\n" +
+	"price_rule\x18\x01 \x01(\v2\n" +
+	".PriceRuleR\tpriceRule\"8\n" +
+	"\x15ListPriceRulesRequest\x12\x1f\n" +
+	"\vactive_only\x18\x01 \x01(\bR\n" +
+	"activeOnly\"E\n" +
+	"\x16ListPriceRulesResponse\x12+\n" +
+	"\vprice_rules\x18\x01 \x03(\v2\n" +
+	".PriceRuleR\n" +
+	"priceRules\"(\n" +
+	"\x16DeletePriceRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\x17DeletePriceRuleResponse\x12)\n" +
+	"\n" +
+	"price_rule\x18\x01 \x01(\v2\n" +
+	".PriceRuleR\tpriceRule2\xb8\x02\n" +
+	"\x0ePricingService\x12h\n" +
+	"\x0fCreatePriceRule\x12\x17.CreatePriceRuleRequest\x1a\x18.CreatePriceRuleResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\n" +
+	"price_rule\"\x0e/v1/priceRules\x12Y\n" +
+	"\x0eListPriceRules\x12\x16.ListPriceRulesRequest\x1a\x17.ListPriceRulesResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/priceRules\x12a\n" +
+	"\x0fDeletePriceRule\x12\x17.DeletePriceRuleRequest\x1a\x18.DeletePriceRuleResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/priceRules/{id}B\x06Z\x04.;pbb\x06proto3"
+
+var (
+	file_pricing_service_proto_rawDescOnce sync.Once
+	file_pricing_service_proto_rawDescData []byte
+)
+
+func file_pricing_service_proto_rawDescGZIP() []byte {
+	file_pricing_service_proto_rawDescOnce.Do(func() {
+		file_pricing_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pricing_service_proto_rawDesc), len(file_pricing_service_proto_rawDesc)))
+	})
+	return file_pricing_service_proto_rawDescData
+}
+
+var file_pricing_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pricing_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_pricing_service_proto_goTypes = []any{
+	(PriceRule_Scope)(0),            // 0: PriceRule.Scope
+	(*PriceRule)(nil),               // 1: PriceRule
+	(*CreatePriceRuleRequest)(nil),  // 2: CreatePriceRuleRequest
+	(*CreatePriceRuleResponse)(nil), // 3: CreatePriceRuleResponse
+	(*ListPriceRulesRequest)(nil),   // 4: ListPriceRulesRequest
+	(*ListPriceRulesResponse)(nil),  // 5: ListPriceRulesResponse
+	(*DeletePriceRuleRequest)(nil),  // 6: DeletePriceRuleRequest
+	(*DeletePriceRuleResponse)(nil), // 7: DeletePriceRuleResponse
+	(*Money)(nil),                   // 8: Money
+	(*timestamppb.Timestamp)(nil),   // 9: google.protobuf.Timestamp
+}
+var file_pricing_service_proto_depIdxs = []int32{
+	8,  // 0: PriceRule.amount_off:type_name -> Money
+	0,  // 1: PriceRule.scope:type_name -> PriceRule.Scope
+	9,  // 2: PriceRule.start_time:type_name -> google.protobuf.Timestamp
+	9,  // 3: PriceRule.end_time:type_name -> google.protobuf.Timestamp
+	1,  // 4: CreatePriceRuleRequest.price_rule:type_name -> PriceRule
+	1,  // 5: CreatePriceRuleResponse.price_rule:type_name -> PriceRule
+	1,  // 6: ListPriceRulesResponse.price_rules:type_name -> PriceRule
+	1,  // 7: DeletePriceRuleResponse.price_rule:type_name -> PriceRule
+	2,  // 8: PricingService.CreatePriceRule:input_type -> CreatePriceRuleRequest
+	4,  // 9: PricingService.ListPriceRules:input_type -> ListPriceRulesRequest
+	6,  // 10: PricingService.DeletePriceRule:input_type -> DeletePriceRuleRequest
+	3,  // 11: PricingService.CreatePriceRule:output_type -> CreatePriceRuleResponse
+	5,  // 12: PricingService.ListPriceRules:output_type -> ListPriceRulesResponse
+	7,  // 13: PricingService.DeletePriceRule:output_type -> DeletePriceRuleResponse
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_pricing_service_proto_init() }
+func file_pricing_service_proto_init() {
+	if File_pricing_service_proto != nil {
+		return
+	}
+	file_money_message_proto_init()
+	file_pricing_service_proto_msgTypes[0].OneofWrappers = []any{
+		(*PriceRule_PercentOff)(nil),
+		(*PriceRule_AmountOff)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pricing_service_proto_rawDesc), len(file_pricing_service_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pricing_service_proto_goTypes,
+		DependencyIndexes: file_pricing_service_proto_depIdxs,
+		EnumInfos:         file_pricing_service_proto_enumTypes,
+		MessageInfos:      file_pricing_service_proto_msgTypes,
+	}.Build()
+	File_pricing_service_proto = out.File
+	file_pricing_service_proto_goTypes = nil
+	file_pricing_service_proto_depIdxs = nil
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PricingServiceClient is the client API for PricingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PricingServiceClient interface {
+	CreatePriceRule(ctx context.Context, in *CreatePriceRuleRequest, opts ...grpc.CallOption) (*CreatePriceRuleResponse, error)
+	ListPriceRules(ctx context.Context, in *ListPriceRulesRequest, opts ...grpc.CallOption) (*ListPriceRulesResponse, error)
+	DeletePriceRule(ctx context.Context, in *DeletePriceRuleRequest, opts ...grpc.CallOption) (*DeletePriceRuleResponse, error)
+}
+
+type pricingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPricingServiceClient(cc grpc.ClientConnInterface) PricingServiceClient {
+	return &pricingServiceClient{cc}
+}
+
+func (c *pricingServiceClient) CreatePriceRule(ctx context.Context, in *CreatePriceRuleRequest, opts ...grpc.CallOption) (*CreatePriceRuleResponse, error) {
+	out := new(CreatePriceRuleResponse)
+	err := c.cc.Invoke(ctx, "/PricingService/CreatePriceRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pricingServiceClient) ListPriceRules(ctx context.Context, in *ListPriceRulesRequest, opts ...grpc.CallOption) (*ListPriceRulesResponse, error) {
+	out := new(ListPriceRulesResponse)
+	err := c.cc.Invoke(ctx, "/PricingService/ListPriceRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pricingServiceClient) DeletePriceRule(ctx context.Context, in *DeletePriceRuleRequest, opts ...grpc.CallOption) (*DeletePriceRuleResponse, error) {
+	out := new(DeletePriceRuleResponse)
+	err := c.cc.Invoke(ctx, "/PricingService/DeletePriceRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PricingServiceServer is the server API for PricingService service.
+// All implementations must embed UnimplementedPricingServiceServer
+// for forward compatibility
+type PricingServiceServer interface {
+	CreatePriceRule(context.Context, *CreatePriceRuleRequest) (*CreatePriceRuleResponse, error)
+	ListPriceRules(context.Context, *ListPriceRulesRequest) (*ListPriceRulesResponse, error)
+	DeletePriceRule(context.Context, *DeletePriceRuleRequest) (*DeletePriceRuleResponse, error)
+	mustEmbedUnimplementedPricingServiceServer()
+}
+
+// UnimplementedPricingServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPricingServiceServer struct {
+}
+
+func (UnimplementedPricingServiceServer) CreatePriceRule(context.Context, *CreatePriceRuleRequest) (*CreatePriceRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePriceRule not implemented")
+}
+func (UnimplementedPricingServiceServer) ListPriceRules(context.Context, *ListPriceRulesRequest) (*ListPriceRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPriceRules not implemented")
+}
+func (UnimplementedPricingServiceServer) DeletePriceRule(context.Context, *DeletePriceRuleRequest) (*DeletePriceRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePriceRule not implemented")
+}
+func (UnimplementedPricingServiceServer) mustEmbedUnimplementedPricingServiceServer() {}
+
+// UnsafePricingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PricingServiceServer will
+// result in compilation errors.
+type UnsafePricingServiceServer interface {
+	mustEmbedUnimplementedPricingServiceServer()
+}
+
+func RegisterPricingServiceServer(s grpc.ServiceRegistrar, srv PricingServiceServer) {
+	s.RegisterService(&PricingService_ServiceDesc, srv)
+}
+
+func _PricingService_CreatePriceRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePriceRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PricingServiceServer).CreatePriceRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PricingService/CreatePriceRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PricingServiceServer).CreatePriceRule(ctx, req.(*CreatePriceRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PricingService_ListPriceRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPriceRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PricingServiceServer).ListPriceRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PricingService/ListPriceRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PricingServiceServer).ListPriceRules(ctx, req.(*ListPriceRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PricingService_DeletePriceRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePriceRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PricingServiceServer).DeletePriceRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PricingService/DeletePriceRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PricingServiceServer).DeletePriceRule(ctx, req.(*DeletePriceRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PricingService_ServiceDesc is the grpc.ServiceDesc for PricingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PricingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "PricingService",
+	HandlerType: (*PricingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePriceRule",
+			Handler:    _PricingService_CreatePriceRule_Handler,
+		},
+		{
+			MethodName: "ListPriceRules",
+			Handler:    _PricingService_ListPriceRules_Handler,
+		},
+		{
+			MethodName: "DeletePriceRule",
+			Handler:    _PricingService_DeletePriceRule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pricing_service.proto",
+}
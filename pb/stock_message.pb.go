@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: stock_message.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StockLevel is the stock of a book in one warehouse.
+type StockLevel struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookId    string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Warehouse string                 `protobuf:"bytes,2,opt,name=warehouse,proto3" json:"warehouse,omitempty"`
+	// Copies physically in the warehouse.
+	OnHand int64 `protobuf:"varint,3,opt,name=on_hand,json=onHand,proto3" json:"on_hand,omitempty"`
+	// Copies held by reservations; never more than on_hand.
+	Reserved int64 `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	// on_hand - reserved.
+	Available int64 `protobuf:"varint,5,opt,name=available,proto3" json:"available,omitempty"`
+	// A LOW_STOCK event is emitted when available drops to this level or
+	// below. 0 disables it.
+	LowStockThreshold int64 `protobuf:"varint,6,opt,name=low_stock_threshold,json=lowStockThreshold,proto3" json:"low_stock_threshold,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *StockLevel) Reset() {
+	*x = StockLevel{}
+	mi := &file_stock_message_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockLevel) ProtoMessage() {}
+
+func (x *StockLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_stock_message_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockLevel.ProtoReflect.Descriptor instead.
+func (*StockLevel) Descriptor() ([]byte, []int) {
+	return file_stock_message_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StockLevel) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *StockLevel) GetWarehouse() string {
+	if x != nil {
+		return x.Warehouse
+	}
+	return ""
+}
+
+func (x *StockLevel) GetOnHand() int64 {
+	if x != nil {
+		return x.OnHand
+	}
+	return 0
+}
+
+func (x *StockLevel) GetReserved() int64 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *StockLevel) GetAvailable() int64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *StockLevel) GetLowStockThreshold() int64 {
+	if x != nil {
+		return x.LowStockThreshold
+	}
+	return 0
+}
+
+var File_stock_message_proto protoreflect.FileDescriptor
+
+const file_stock_message_proto_rawDesc = "" +
+	"\n" +
+	"\x13stock_message.proto\"\xc6\x01\n" +
+	"\n" +
+	"StockLevel\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x1c\n" +
+	"\twarehouse\x18\x02 \x01(\tR\twarehouse\x12\x17\n" +
+	"\aon_hand\x18\x03 \x01(\x03R\x06onHand\x12\x1a\n" +
+	"\breserved\x18\x04 \x01(\x03R\breserved\x12\x1c\n" +
+	"\tavailable\x18\x05 \x01(\x03R\tavailable\x12.\n" +
+	"\x13low_stock_threshold\x18\x06 \x01(\x03R\x11lowStockThresholdB\x06Z\x04.;pbb\x06proto3"
+
+var (
+	file_stock_message_proto_rawDescOnce sync.Once
+	file_stock_message_proto_rawDescData []byte
+)
+
+func file_stock_message_proto_rawDescGZIP() []byte {
+	file_stock_message_proto_rawDescOnce.Do(func() {
+		file_stock_message_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stock_message_proto_rawDesc), len(file_stock_message_proto_rawDesc)))
+	})
+	return file_stock_message_proto_rawDescData
+}
+
+var file_stock_message_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_stock_message_proto_goTypes = []any{
+	(*StockLevel)(nil), // 0: StockLevel
+}
+var file_stock_message_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_stock_message_proto_init() }
+func file_stock_message_proto_init() {
+	if File_stock_message_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stock_message_proto_rawDesc), len(file_stock_message_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_stock_message_proto_goTypes,
+		DependencyIndexes: file_stock_message_proto_depIdxs,
+		MessageInfos:      file_stock_message_proto_msgTypes,
+	}.Build()
+	File_stock_message_proto = out.File
+	file_stock_message_proto_goTypes = nil
+	file_stock_message_proto_depIdxs = nil
+}
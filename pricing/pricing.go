@@ -0,0 +1,302 @@
+// Package pricing keeps discount rules and computes the effective price of
+// books from them.
+package pricing
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidRule = errors.New("invalid price rule")
+
+// Rule is a discount. Either PercentOff or AmountOff is set.
+type Rule struct {
+	ID             string `gorm:"primaryKey"`
+	Name           string
+	PercentOff     int32
+	AmountCurrency string       `gorm:"size:3"`
+	AmountOff      money.Amount `gorm:"type:numeric(20,9)"`
+	Scope          string       `gorm:"index:idx_price_rules_scope"`
+	ScopeID        string       `gorm:"index:idx_price_rules_scope"`
+	StartTime      *time.Time
+	EndTime        *time.Time
+	CreatedAt      time.Time
+}
+
+func (Rule) TableName() string {
+	return "price_rules"
+}
+
+func (r *Rule) Proto() *pb.PriceRule {
+	rule := &pb.PriceRule{
+		Id:      r.ID,
+		Name:    r.Name,
+		Scope:   pb.PriceRule_Scope(pb.PriceRule_Scope_value[r.Scope]),
+		ScopeId: r.ScopeID,
+	}
+	if r.PercentOff > 0 {
+		rule.Discount = &pb.PriceRule_PercentOff{PercentOff: r.PercentOff}
+	} else {
+		rule.Discount = &pb.PriceRule_AmountOff{AmountOff: r.AmountOff.Money(r.AmountCurrency)}
+	}
+	if r.StartTime != nil {
+		rule.StartTime = timestamppb.New(*r.StartTime)
+	}
+	if r.EndTime != nil {
+		rule.EndTime = timestamppb.New(*r.EndTime)
+	}
+
+	return rule
+}
+
+// Price returns price after the discount of the rule, and false if the
+// rule cannot apply to it.
+func (r *Rule) Price(price *pb.Money) (*pb.Money, bool) {
+	amount := money.AmountOf(price)
+	if r.PercentOff > 0 {
+		return amount.Sub(amount.Percent(int64(r.PercentOff))).Money(price.GetCurrencyCode()), true
+	}
+	if r.AmountCurrency != price.GetCurrencyCode() {
+		return nil, false
+	}
+
+	discounted := amount.Sub(r.AmountOff)
+	if discounted.Cmp(money.Amount{}) < 0 {
+		discounted = money.Amount{}
+	}
+
+	return discounted.Money(price.GetCurrencyCode()), true
+}
+
+// Applies reports whether a book is in the scope of the rule. categories
+// are the categories of the book and all their ancestors.
+func (r *Rule) Applies(book *pb.Book, categories []string) bool {
+	switch r.Scope {
+	case pb.PriceRule_BOOK.String():
+		return book.GetId() == r.ScopeID
+	case pb.PriceRule_AUTHOR.String():
+		return slices.Contains(book.GetAuthorIds(), r.ScopeID)
+	case pb.PriceRule_CATEGORY.String():
+		return slices.Contains(categories, r.ScopeID)
+	}
+
+	return false
+}
+
+func Models() []any {
+	return []any{&Rule{}}
+}
+
+// Active returns the rules active at t.
+func Active(db *gorm.DB, t time.Time) ([]*Rule, error) {
+	var rules []*Rule
+	err := db.Where("(start_time IS NULL OR start_time <= ?) AND (end_time IS NULL OR end_time > ?)", t.UTC(), t.UTC()).
+		Order("id").
+		Find(&rules).Error
+
+	return rules, err
+}
+
+// Apply sets the effective price of a book to the lowest price given by
+// the rules that apply to it, or to its list price.
+func Apply(rules []*Rule, book *pb.Book, categories []string) {
+	book.EffectivePrice, book.PriceRuleId = book.GetListPrice(), ""
+	if book.GetListPrice() == nil {
+		return
+	}
+
+	for _, rule := range rules {
+		if !rule.Applies(book, categories) {
+			continue
+		}
+		price, ok := rule.Price(book.GetListPrice())
+		if !ok || money.AmountOf(price).Cmp(money.AmountOf(book.EffectivePrice)) >= 0 {
+			continue
+		}
+		book.EffectivePrice, book.PriceRuleId = price, rule.ID
+	}
+}
+
+// ApplyStored is Apply for a stored book: it reads the authors and
+// categories of the book, with the categories' ancestors, from db, e.g.
+// in the transaction that sells the book.
+func ApplyStored(db *gorm.DB, rules []*Rule, book *pb.Book) error {
+	var authors []*model.BookAuthor
+	if err := db.Where("book_id = ?", book.GetId()).Order("position").Find(&authors).Error; err != nil {
+		return err
+	}
+	book.AuthorIds = nil
+	for _, link := range authors {
+		book.AuthorIds = append(book.AuthorIds, link.AuthorID)
+	}
+
+	var links []*model.BookCategory
+	if err := db.Where("book_id = ?", book.GetId()).Order("category_id").Find(&links).Error; err != nil {
+		return err
+	}
+	var categories []string
+	for _, link := range links {
+		for id := link.CategoryID; id != "" && !slices.Contains(categories, id); {
+			var category model.Category
+			if err := db.Where("id = ?", id).First(&category).Error; err != nil {
+				return err
+			}
+			categories = append(categories, id)
+			id = category.ParentID
+		}
+	}
+
+	Apply(rules, book, categories)
+
+	return nil
+}
+
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Create(ctx context.Context, rule *pb.PriceRule) (*pb.PriceRule, error) {
+	row, err := newRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := scopeExists(tx, rule.Scope, rule.ScopeId); err != nil {
+			return err
+		}
+
+		return tx.Create(row).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return row.Proto(), nil
+}
+
+// List returns every rule, or only the rules active now.
+func (s *Store) List(ctx context.Context, activeOnly bool) ([]*pb.PriceRule, error) {
+	db := s.db.WithContext(ctx)
+
+	var rows []*Rule
+	var err error
+	if activeOnly {
+		rows, err = Active(db, time.Now())
+	} else {
+		err = db.Order("id").Find(&rows).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*pb.PriceRule, len(rows))
+	for i, row := range rows {
+		rules[i] = row.Proto()
+	}
+
+	return rules, nil
+}
+
+func (s *Store) Delete(ctx context.Context, id string) (*pb.PriceRule, error) {
+	var row Rule
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&row).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&row).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return row.Proto(), nil
+}
+
+func newRule(rule *pb.PriceRule) (*Rule, error) {
+	row := &Rule{
+		ID:      rule.GetId(),
+		Name:    strings.TrimSpace(rule.GetName()),
+		Scope:   rule.GetScope().String(),
+		ScopeID: rule.GetScopeId(),
+	}
+	if row.ID == "" {
+		row.ID = uuid.New().String()
+	}
+
+	switch discount := rule.GetDiscount().(type) {
+	case *pb.PriceRule_PercentOff:
+		if discount.PercentOff < 1 || discount.PercentOff > 100 {
+			return nil, fmt.Errorf("%w: percent off must be between 1 and 100", ErrInvalidRule)
+		}
+		row.PercentOff = discount.PercentOff
+	case *pb.PriceRule_AmountOff:
+		if discount.AmountOff == nil {
+			return nil, fmt.Errorf("%w: amount off is required", ErrInvalidRule)
+		}
+		if err := money.Validate(discount.AmountOff); err != nil {
+			return nil, fmt.Errorf("%w: amount off: %v", ErrInvalidRule, err)
+		}
+		row.AmountOff = money.AmountOf(discount.AmountOff)
+		if row.AmountOff.Cmp(money.Amount{}) <= 0 {
+			return nil, fmt.Errorf("%w: amount off must be positive", ErrInvalidRule)
+		}
+		row.AmountCurrency = discount.AmountOff.CurrencyCode
+	default:
+		return nil, fmt.Errorf("%w: a percent or amount off is required", ErrInvalidRule)
+	}
+
+	if rule.GetStartTime() != nil {
+		start := rule.GetStartTime().AsTime()
+		row.StartTime = &start
+	}
+	if rule.GetEndTime() != nil {
+		end := rule.GetEndTime().AsTime()
+		row.EndTime = &end
+	}
+	if row.StartTime != nil && row.EndTime != nil && !row.EndTime.After(*row.StartTime) {
+		return nil, fmt.Errorf("%w: end time must be after start time", ErrInvalidRule)
+	}
+
+	return row, nil
+}
+
+func scopeExists(tx *gorm.DB, scope pb.PriceRule_Scope, id string) error {
+	var table any
+	switch scope {
+	case pb.PriceRule_BOOK:
+		table = &model.Book{}
+	case pb.PriceRule_AUTHOR:
+		table = &model.Author{}
+	case pb.PriceRule_CATEGORY:
+		table = &model.Category{}
+	default:
+		return fmt.Errorf("%w: scope is required", ErrInvalidRule)
+	}
+
+	var count int64
+	if err := tx.Model(table).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s %q does not exist", ErrInvalidRule, strings.ToLower(scope.String()), id)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package pricing_test
+
+import (
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	return dbtest.New(t).DB
+}
+
+func rub(units int64, nanos int32) *pb.Money {
+	return &pb.Money{CurrencyCode: "RUB", Units: units, Nanos: nanos}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	book := &pb.Book{Id: "karamazov", AuthorIds: []string{"dostoevsky"}, ListPrice: rub(1000, 0)}
+	rules := []*pricing.Rule{
+		{ID: "book", Scope: "BOOK", ScopeID: "karamazov", PercentOff: 10},
+		{ID: "author", Scope: "AUTHOR", ScopeID: "dostoevsky", AmountCurrency: "RUB", AmountOff: money.AmountOf(rub(150, 0))},
+		{ID: "usd", Scope: "BOOK", ScopeID: "karamazov", AmountCurrency: "USD", AmountOff: money.AmountOf(&pb.Money{CurrencyCode: "USD", Units: 900})},
+		{ID: "classics", Scope: "CATEGORY", ScopeID: "classics", PercentOff: 20},
+	}
+
+	pricing.Apply(rules[:3], book, nil)
+	assert.Equal(t, "author", book.PriceRuleId)
+	assert.Equal(t, int64(850), book.EffectivePrice.Units)
+
+	pricing.Apply(rules, book, []string{"russian", "classics"})
+	assert.Equal(t, "classics", book.PriceRuleId)
+	assert.Equal(t, int64(800), book.EffectivePrice.Units)
+
+	pricing.Apply(nil, book, nil)
+	assert.Empty(t, book.PriceRuleId)
+	assert.Equal(t, book.ListPrice, book.EffectivePrice)
+
+	cheap := &pb.Book{Id: "karamazov", ListPrice: rub(99, 990000000)}
+	pricing.Apply([]*pricing.Rule{{ID: "big", Scope: "BOOK", ScopeID: "karamazov", AmountCurrency: "RUB", AmountOff: money.AmountOf(rub(500, 0))}}, cheap, nil)
+	assert.Equal(t, "big", cheap.PriceRuleId)
+	assert.Equal(t, int64(0), cheap.EffectivePrice.Units)
+	assert.Equal(t, int32(0), cheap.EffectivePrice.Nanos)
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := pricing.NewStore(db)
+
+	assert.NoError(t, db.Create(&model.Book{ID: "karamazov", Title: "Братья Карамазовы"}).Error)
+
+	now := time.Now()
+	current, err := store.Create(ctx, &pb.PriceRule{
+		Name:     "Осенняя распродажа",
+		Discount: &pb.PriceRule_PercentOff{PercentOff: 15},
+		Scope:    pb.PriceRule_BOOK,
+		ScopeId:  "karamazov",
+		EndTime:  timestamppb.New(now.Add(time.Hour)),
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, current.Id)
+	assert.Equal(t, int32(15), current.GetPercentOff())
+
+	_, err = store.Create(ctx, &pb.PriceRule{
+		Discount:  &pb.PriceRule_AmountOff{AmountOff: rub(100, 0)},
+		Scope:     pb.PriceRule_BOOK,
+		ScopeId:   "karamazov",
+		StartTime: timestamppb.New(now.Add(24 * time.Hour)),
+	})
+	assert.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		for _, rule := range []*pb.PriceRule{
+			{Scope: pb.PriceRule_BOOK, ScopeId: "karamazov"},
+			{Discount: &pb.PriceRule_PercentOff{PercentOff: 101}, Scope: pb.PriceRule_BOOK, ScopeId: "karamazov"},
+			{Discount: &pb.PriceRule_AmountOff{AmountOff: rub(-1, 0)}, Scope: pb.PriceRule_BOOK, ScopeId: "karamazov"},
+			{Discount: &pb.PriceRule_PercentOff{PercentOff: 5}},
+			{Discount: &pb.PriceRule_PercentOff{PercentOff: 5}, Scope: pb.PriceRule_AUTHOR, ScopeId: "missing"},
+			{
+				Discount:  &pb.PriceRule_PercentOff{PercentOff: 5},
+				Scope:     pb.PriceRule_BOOK,
+				ScopeId:   "karamazov",
+				StartTime: timestamppb.New(now),
+				EndTime:   timestamppb.New(now),
+			},
+		} {
+			_, err := store.Create(ctx, rule)
+			assert.ErrorIs(t, err, pricing.ErrInvalidRule)
+		}
+	})
+
+	t.Run("Active", func(t *testing.T) {
+		all, err := store.List(ctx, false)
+		assert.NoError(t, err)
+		assert.Len(t, all, 2)
+
+		active, err := store.List(ctx, true)
+		assert.NoError(t, err)
+		assert.Len(t, active, 1)
+		assert.Equal(t, current.Id, active[0].Id)
+
+		later, err := pricing.Active(db, now.Add(48*time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, later, 1)
+		assert.Equal(t, "RUB", later[0].AmountCurrency)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		deleted, err := store.Delete(ctx, current.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, current.Id, deleted.Id)
+
+		_, err = store.Delete(ctx, current.Id)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,262 @@
+// Package review keeps the reviews of books and their moderation.
+package review
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// MaxTextLength is the longest review text accepted, in bytes.
+const MaxTextLength = 10_000
+
+var (
+	ErrInvalidReview = errors.New("invalid review")
+	ErrAnonymous     = errors.New("reviews need a principal")
+	ErrNotAuthor     = errors.New("only the author can change a review")
+	ErrNotModerator  = errors.New("only moderators can moderate reviews")
+	ErrConflict      = errors.New("review was changed concurrently")
+)
+
+// Review is a review of a book. Only approved reviews count towards the
+// rating aggregates of the book, which are kept in step in the same
+// transactions that change the reviews.
+type Review struct {
+	ID        string `gorm:"primaryKey"`
+	BookID    string `gorm:"uniqueIndex:idx_reviews_book_principal"`
+	Principal string `gorm:"uniqueIndex:idx_reviews_book_principal"`
+	Rating    int32  `gorm:"check:chk_reviews_rating,rating BETWEEN 1 AND 5"`
+	Text      string
+	Status    string `gorm:"index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
+
+func (r *Review) Proto() *pb.Review {
+	return &pb.Review{
+		Id:         r.ID,
+		BookId:     r.BookID,
+		Principal:  r.Principal,
+		Rating:     r.Rating,
+		Text:       r.Text,
+		Status:     pb.Review_Status(pb.Review_Status_value[r.Status]),
+		CreateTime: timestamppb.New(r.CreatedAt),
+		UpdateTime: timestamppb.New(r.UpdatedAt),
+	}
+}
+
+func (r *Review) approved() bool {
+	return r.Status == pb.Review_APPROVED.String()
+}
+
+func Models() []any {
+	return []any{&Review{}}
+}
+
+type Store struct {
+	db         *gorm.DB
+	moderators audit.Principals
+	onRating   func(ctx context.Context, bookIDs ...string)
+}
+
+// NewStore returns a store whose reviews can be moderated by moderators.
+func NewStore(db *gorm.DB, moderators audit.Principals) *Store {
+	return &Store{db: db, moderators: moderators}
+}
+
+// OnRatingChange registers f to be called after a change to the rating
+// aggregates of a book is committed, e.g. to drop the book from a cache.
+func (s *Store) OnRatingChange(f func(ctx context.Context, bookIDs ...string)) {
+	s.onRating = f
+}
+
+// Create adds a pending review of a book by the principal of ctx.
+func (s *Store) Create(ctx context.Context, bookID string, review *pb.Review) (*pb.Review, error) {
+	principal, err := principalOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(review); err != nil {
+		return nil, err
+	}
+
+	row := &Review{
+		ID:        uuid.New().String(),
+		BookID:    bookID,
+		Principal: principal,
+		Rating:    review.Rating,
+		Text:      review.Text,
+		Status:    pb.Review_PENDING.String(),
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", bookID).First(&model.Book{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(row).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return row.Proto(), nil
+}
+
+// List returns the reviews with status, of one book or of all books if
+// bookID is empty, newest first.
+func (s *Store) List(ctx context.Context, bookID string, status pb.Review_Status) ([]*pb.Review, error) {
+	if status == pb.Review_STATUS_UNSPECIFIED {
+		status = pb.Review_APPROVED
+	}
+
+	query := s.db.WithContext(ctx).Where("status = ?", status.String()).Order("created_at DESC, id")
+	if bookID != "" {
+		query = query.Where("book_id = ?", bookID)
+	}
+
+	var rows []*Review
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	reviews := make([]*pb.Review, len(rows))
+	for i, row := range rows {
+		reviews[i] = row.Proto()
+	}
+
+	return reviews, nil
+}
+
+// Update changes the rating and text of a review of the principal of ctx
+// and sends it back to moderation.
+func (s *Store) Update(ctx context.Context, id string, review *pb.Review) (*pb.Review, error) {
+	if err := validate(review); err != nil {
+		return nil, err
+	}
+
+	return s.change(ctx, id, true, func(row *Review) *Review {
+		row.Rating, row.Text, row.Status = review.Rating, review.Text, pb.Review_PENDING.String()
+		return row
+	})
+}
+
+// Delete removes a review of the principal of ctx.
+func (s *Store) Delete(ctx context.Context, id string) (*pb.Review, error) {
+	return s.change(ctx, id, true, func(*Review) *Review {
+		return nil
+	})
+}
+
+// Moderate approves or rejects a review. Only moderators can.
+func (s *Store) Moderate(ctx context.Context, id string, status pb.Review_Status) (*pb.Review, error) {
+	if !s.moderators.Allows(ctx) {
+		return nil, ErrNotModerator
+	}
+	if status != pb.Review_APPROVED && status != pb.Review_REJECTED {
+		return nil, fmt.Errorf("%w: status must be APPROVED or REJECTED", ErrInvalidReview)
+	}
+
+	return s.change(ctx, id, false, func(row *Review) *Review {
+		row.Status = status.String()
+		return row
+	})
+}
+
+// change writes the review returned by edit, or deletes the review if edit
+// returns nil, and moves its rating in or out of the aggregates of the
+// book when it starts or stops being approved. The write only applies if
+// the status is still the one read, so concurrent changes cannot count a
+// rating twice. If own is set, only the author may change the review.
+func (s *Store) change(ctx context.Context, id string, own bool, edit func(*Review) *Review) (review *pb.Review, err error) {
+	rated := ""
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row Review
+		if err := tx.Where("id = ?", id).First(&row).Error; err != nil {
+			return err
+		}
+		if own && row.Principal != audit.FromContext(ctx).Principal {
+			return ErrNotAuthor
+		}
+
+		before := row
+		after := edit(&row)
+
+		guarded := tx.Where("id = ? AND status = ?", id, before.Status)
+		var res *gorm.DB
+		if after == nil {
+			res = guarded.Delete(&Review{})
+			review = before.Proto()
+		} else {
+			res = guarded.Model(&Review{}).Updates(map[string]any{
+				"rating": after.Rating,
+				"text":   after.Text,
+				"status": after.Status,
+			})
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrConflict
+		}
+
+		if after != nil {
+			if err := tx.Where("id = ?", id).First(&row).Error; err != nil {
+				return err
+			}
+			review = row.Proto()
+		}
+
+		switch wasApproved, isApproved := before.approved(), after != nil && after.approved(); {
+		case wasApproved && !isApproved:
+			rated = before.BookID
+			return model.AddRating(tx, before.BookID, -1, -int64(before.Rating))
+		case !wasApproved && isApproved:
+			rated = after.BookID
+			return model.AddRating(tx, after.BookID, 1, int64(after.Rating))
+		}
+
+		return nil
+	})
+	if err == nil && rated != "" && s.onRating != nil {
+		s.onRating(ctx, rated)
+	}
+
+	return review, err
+}
+
+func principalOf(ctx context.Context) (string, error) {
+	principal := audit.FromContext(ctx).Principal
+	if principal == audit.Anonymous {
+		return "", ErrAnonymous
+	}
+
+	return principal, nil
+}
+
+// validate checks the rating and trims the text in place.
+func validate(review *pb.Review) error {
+	if review.Rating < 1 || review.Rating > 5 {
+		return fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidReview)
+	}
+
+	review.Text = strings.TrimSpace(review.Text)
+	if len(review.Text) > MaxTextLength {
+		return fmt.Errorf("%w: text must not be longer than %d bytes", ErrInvalidReview, MaxTextLength)
+	}
+
+	return nil
+}
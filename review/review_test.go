@@ -0,0 +1,126 @@
+package review_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/review"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	return dbtest.New(t).DB
+}
+
+func TestReviews(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+	store := review.NewStore(db, audit.ParsePrincipals("moderator"))
+
+	alice := audit.NewContext(context.Background(), audit.Info{Principal: "alice"})
+	bob := audit.NewContext(context.Background(), audit.Info{Principal: "bob"})
+	moderator := audit.NewContext(context.Background(), audit.Info{Principal: "moderator"})
+
+	assert.NoError(t, db.Create(&model.Book{ID: "karamazov", Title: "Братья Карамазовы"}).Error)
+
+	rating := func() (float64, int64) {
+		var book model.Book
+		assert.NoError(t, db.Where("id = ?", "karamazov").First(&book).Error)
+		return book.RatingAverage, book.RatingCount
+	}
+
+	first, err := store.Create(alice, "karamazov", &pb.Review{Rating: 5, Text: " Великий роман "})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.Review_PENDING, first.Status)
+	assert.Equal(t, "alice", first.Principal)
+	assert.Equal(t, "Великий роман", first.Text)
+
+	second, err := store.Create(bob, "karamazov", &pb.Review{Rating: 2})
+	assert.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := store.Create(alice, "karamazov", &pb.Review{Rating: 4})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+		_, err = store.Create(context.Background(), "karamazov", &pb.Review{Rating: 4})
+		assert.ErrorIs(t, err, review.ErrAnonymous)
+		_, err = store.Create(bob, "karamazov", &pb.Review{Rating: 6})
+		assert.ErrorIs(t, err, review.ErrInvalidReview)
+		_, err = store.Create(moderator, "missing", &pb.Review{Rating: 3})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = store.Update(bob, first.Id, &pb.Review{Rating: 1})
+		assert.ErrorIs(t, err, review.ErrNotAuthor)
+		_, err = store.Moderate(moderator, first.Id, pb.Review_PENDING)
+		assert.ErrorIs(t, err, review.ErrInvalidReview)
+	})
+
+	t.Run("Not Moderator", func(t *testing.T) {
+		// An author cannot approve their own review.
+		_, err := store.Moderate(alice, first.Id, pb.Review_APPROVED)
+		assert.ErrorIs(t, err, review.ErrNotModerator)
+		_, err = store.Moderate(context.Background(), first.Id, pb.Review_APPROVED)
+		assert.ErrorIs(t, err, review.ErrNotModerator)
+
+		_, count := rating()
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Moderation", func(t *testing.T) {
+		listed, err := store.List(alice, "karamazov", pb.Review_STATUS_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Empty(t, listed)
+
+		for _, id := range []string{first.Id, second.Id} {
+			approved, err := store.Moderate(moderator, id, pb.Review_APPROVED)
+			assert.NoError(t, err)
+			assert.Equal(t, pb.Review_APPROVED, approved.Status)
+		}
+		// Approving twice does not count twice.
+		_, err = store.Moderate(moderator, first.Id, pb.Review_APPROVED)
+		assert.NoError(t, err)
+
+		average, count := rating()
+		assert.Equal(t, 3.5, average)
+		assert.Equal(t, int64(2), count)
+
+		listed, err = store.List(alice, "karamazov", pb.Review_APPROVED)
+		assert.NoError(t, err)
+		assert.Len(t, listed, 2)
+
+		_, err = store.Moderate(moderator, second.Id, pb.Review_REJECTED)
+		assert.NoError(t, err)
+		average, count = rating()
+		assert.Equal(t, 5.0, average)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		updated, err := store.Update(alice, first.Id, &pb.Review{Rating: 4, Text: "Перечитал"})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.Review_PENDING, updated.Status)
+		assert.Equal(t, int32(4), updated.Rating)
+
+		_, count := rating()
+		assert.Equal(t, int64(0), count)
+
+		_, err = store.Moderate(moderator, first.Id, pb.Review_APPROVED)
+		assert.NoError(t, err)
+		average, count := rating()
+		assert.Equal(t, 4.0, average)
+		assert.Equal(t, int64(1), count)
+
+		deleted, err := store.Delete(alice, first.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, pb.Review_APPROVED, deleted.Status)
+		average, count = rating()
+		assert.Equal(t, 0.0, average)
+		assert.Equal(t, int64(0), count)
+
+		_, err = store.Delete(alice, first.Id)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
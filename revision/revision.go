@@ -0,0 +1,121 @@
+package revision
+
+import (
+	"bookstoregrpc/pb"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// Revision is a snapshot of a book after a change. Book is the book as
+// JSON, empty when the change deleted it.
+type Revision struct {
+	BookID   string `gorm:"primaryKey"`
+	Revision int64  `gorm:"primaryKey;autoIncrement:false"`
+	Type     string
+	Book     []byte
+	Time     time.Time `gorm:"index"`
+}
+
+func (Revision) TableName() string {
+	return "book_revisions"
+}
+
+func Models() []any {
+	return []any{&Revision{}}
+}
+
+// Write stores the book after the change as the next revision of the book.
+// tx must be the transaction that applies the change.
+func Write(tx *gorm.DB, event *pb.BookEvent) error {
+	bookID := event.GetAfter().GetId()
+	if bookID == "" {
+		bookID = event.GetBefore().GetId()
+	}
+
+	var last int64
+	err := tx.Model(&Revision{}).Where("book_id = ?", bookID).Select("COALESCE(MAX(revision), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	row := &Revision{
+		BookID:   bookID,
+		Revision: last + 1,
+		Type:     event.GetType().String(),
+		Time:     event.GetTime().AsTime(),
+	}
+	if after := event.GetAfter(); after != nil {
+		if row.Book, err = protojson.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Create(row).Error; err != nil {
+		return fmt.Errorf("failed to write book revision: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the revisions of a book, oldest first.
+func List(db *gorm.DB, bookID string) ([]*pb.BookRevision, error) {
+	var rows []*Revision
+	if err := db.Where("book_id = ?", bookID).Order("revision").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*pb.BookRevision, len(rows))
+	for i, row := range rows {
+		rev, err := row.proto()
+		if err != nil {
+			return nil, err
+		}
+		revisions[i] = rev
+	}
+
+	return revisions, nil
+}
+
+// Get returns one revision of a book or gorm.ErrRecordNotFound.
+func Get(db *gorm.DB, bookID string, revision int64) (*pb.BookRevision, error) {
+	var row Revision
+	if err := db.Where("book_id = ? AND revision = ?", bookID, revision).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	return row.proto()
+}
+
+// AsOf returns the latest revision of a book made at or before t, or
+// gorm.ErrRecordNotFound if there is none.
+func AsOf(db *gorm.DB, bookID string, t time.Time) (*pb.BookRevision, error) {
+	var row Revision
+	err := db.Where("book_id = ? AND time <= ?", bookID, t.UTC()).Order("revision DESC").First(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return row.proto()
+}
+
+func (r *Revision) proto() (*pb.BookRevision, error) {
+	rev := &pb.BookRevision{
+		BookId:   r.BookID,
+		Revision: r.Revision,
+		Type:     pb.BookEvent_Type(pb.BookEvent_Type_value[r.Type]),
+		Time:     timestamppb.New(r.Time),
+	}
+
+	if len(r.Book) > 0 {
+		rev.Book = &pb.Book{}
+		if err := protojson.Unmarshal(r.Book, rev.Book); err != nil {
+			return nil, fmt.Errorf("failed to decode revision %d of book %s: %w", r.Revision, r.BookID, err)
+		}
+	}
+
+	return rev, nil
+}
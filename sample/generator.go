@@ -1,7 +1,9 @@
 package sample
 
 import (
+	"bookstoregrpc/money"
 	"bookstoregrpc/pb"
+	"fmt"
 	"math/rand/v2"
 
 	"github.com/google/uuid"
@@ -9,11 +11,22 @@ import (
 
 func NewBook() *pb.Book {
 	author := RandomAuthor()
+	title := RandomTitle(author)
+	id := RandomID()
+	price := rand.Int32N(1000) + 200
 	return &pb.Book{
-		Id:     RandomID(),
-		Author: author,
-		Title:  RandomTitle(author),
-		Price:  rand.Int32N(1000) + 200,
+		Id:              id,
+		Author:          author,
+		Title:           title,
+		Price:           price,
+		ListPrice:       money.New(money.DefaultCurrency, int64(price), 0),
+		Isbn:            RandomISBN(),
+		Description:     fmt.Sprintf("%s «%s»", author, title),
+		Publisher:       RandomPublisher(),
+		PublicationYear: rand.Int32N(30) + 1995,
+		Language:        "ru",
+		PageCount:       rand.Int32N(900) + 100,
+		CoverUrl:        "https://covers.example.com/" + id + ".jpg",
 	}
 }
 
@@ -21,6 +34,30 @@ func RandomID() string {
 	return uuid.New().String()
 }
 
+// RandomISBN returns a random ISBN-13 with a valid check digit.
+func RandomISBN() string {
+	digits := []byte("978")
+	for range 9 {
+		digits = append(digits, byte('0'+rand.IntN(10)))
+	}
+
+	sum := 0
+	for i, d := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(d-'0') * weight
+	}
+
+	return string(append(digits, byte('0'+(10-sum%10)%10)))
+}
+
+func RandomPublisher() string {
+	publishers := []string{"Эксмо", "АСТ", "Азбука", "Питер", "Речь"}
+	return publishers[rand.IntN(len(publishers))]
+}
+
 func RandomAuthor() string {
 	authors := []string{"А. С. Пушкин", "Ф. М. Достоевский", "Л. Н. Толстой", "Н. В. Гоголь", "А. П. Чехов"}
 	return authors[rand.IntN(len(authors))]
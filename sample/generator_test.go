@@ -0,0 +1,18 @@
+package sample_test
+
+import (
+	"bookstoregrpc/isbn"
+	"bookstoregrpc/sample"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomISBN(t *testing.T) {
+	for range 100 {
+		s := sample.RandomISBN()
+		normalized, err := isbn.Normalize(s)
+		assert.NoError(t, err)
+		assert.Equal(t, s, normalized)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuditServer struct {
+	Store *audit.Store
+	pb.UnimplementedAuditServiceServer
+}
+
+var auditErrors = errorCodes{
+	{audit.ErrInvalidPageToken, codes.InvalidArgument},
+}
+
+func NewAuditServer(store *audit.Store) *AuditServer {
+	return &AuditServer{Store: store}
+}
+
+func (as *AuditServer) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	events, next, err := as.Store.List(ctx, req)
+	if err != nil {
+		return nil, status.Error(auditErrors.code(err), err.Error())
+	}
+
+	return &pb.ListAuditEventsResponse{Events: events, NextPageToken: next}, nil
+}
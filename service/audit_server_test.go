@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// principalKey carries the principal of a test client. The tests trust it
+// in place of a client certificate.
+const principalKey = "x-test-principal"
+
+func principalFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(principalKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func TestAuditEvents_server(t *testing.T) {
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+		pb.RegisterAuditServiceServer(s, service.NewAuditServer(audit.NewStore(db)))
+	}, grpc.ChainUnaryInterceptor(audit.UnaryServerInterceptor(principalFromMetadata)), grpc.ChainStreamInterceptor(audit.StreamServerInterceptor(principalFromMetadata)))
+
+	books := pb.NewBookServiceClient(conn)
+	auditClient := pb.NewAuditServiceClient(conn)
+
+	alice := metadata.AppendToOutgoingContext(context.Background(), principalKey, "alice", audit.RequestIDKey, "req-1")
+	bob := metadata.AppendToOutgoingContext(context.Background(), principalKey, "bob")
+
+	book := &pb.Book{Id: "1", Author: "case 1", Title: "test", Price: 100}
+	_, err := books.CreateBook(alice, &pb.CreateBookRequest{Book: book})
+	assert.NoError(t, err)
+
+	var header metadata.MD
+	book.Price = 90
+	_, err = books.UpdateBook(bob, &pb.UpdateBookRequest{Id: "1", Book: book}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Len(t, header.Get(audit.RequestIDKey), 1)
+
+	_, err = books.DeleteBook(context.Background(), &pb.DeleteBookRequest{Id: "1"})
+	assert.NoError(t, err)
+
+	t.Run("By Book", func(t *testing.T) {
+		res, err := auditClient.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{BookId: "1"})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Events, 3) {
+			assert.Equal(t, "alice", res.Events[0].Principal)
+			assert.Equal(t, "req-1", res.Events[0].RequestId)
+			assert.Equal(t, "/BookService/CreateBook", res.Events[0].Method)
+
+			assert.Equal(t, "bob", res.Events[1].Principal)
+			assert.Equal(t, header.Get(audit.RequestIDKey)[0], res.Events[1].RequestId)
+			assert.Equal(t, int32(100), res.Events[1].Before.Price)
+			assert.Equal(t, int32(90), res.Events[1].After.Price)
+
+			assert.Equal(t, audit.Anonymous, res.Events[2].Principal)
+			assert.Equal(t, pb.BookEvent_DELETED, res.Events[2].Type)
+		}
+	})
+
+	t.Run("By Actor", func(t *testing.T) {
+		res, err := auditClient.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{Principal: "bob"})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Events, 1) {
+			assert.Equal(t, "/BookService/UpdateBook", res.Events[0].Method)
+		}
+	})
+}
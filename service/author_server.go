@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuthorServer struct {
+	Store AuthorStore
+	pb.UnimplementedAuthorServiceServer
+}
+
+var authorErrors = errorCodes{
+	{ErrInvalidAuthor, codes.InvalidArgument},
+	{ErrAuthorInUse, codes.FailedPrecondition},
+}
+
+func NewAuthorServer(store AuthorStore) *AuthorServer {
+	return &AuthorServer{Store: store}
+}
+
+func (as *AuthorServer) CreateAuthor(ctx context.Context, req *pb.CreateAuthorRequest) (*pb.CreateAuthorResponse, error) {
+	if req.GetAuthor() == nil {
+		return nil, status.Error(codes.InvalidArgument, "author is required")
+	}
+
+	author, err := as.Store.CreateAuthor(ctx, req.GetAuthor())
+	if err != nil {
+		return nil, status.Error(authorErrors.code(err), err.Error())
+	}
+
+	return &pb.CreateAuthorResponse{Author: author}, nil
+}
+
+func (as *AuthorServer) GetAuthor(ctx context.Context, req *pb.GetAuthorRequest) (*pb.GetAuthorResponse, error) {
+	author, err := as.Store.GetAuthor(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(authorErrors.code(err), err.Error())
+	}
+
+	return &pb.GetAuthorResponse{Author: author}, nil
+}
+
+func (as *AuthorServer) ListAuthors(ctx context.Context, req *pb.ListAuthorsRequest) (*pb.ListAuthorsResponse, error) {
+	authors, err := as.Store.ListAuthors(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListAuthorsResponse{Authors: authors}, nil
+}
+
+func (as *AuthorServer) UpdateAuthor(ctx context.Context, req *pb.UpdateAuthorRequest) (*pb.UpdateAuthorResponse, error) {
+	if req.GetAuthor() == nil {
+		return nil, status.Error(codes.InvalidArgument, "author is required")
+	}
+
+	author, err := as.Store.UpdateAuthor(ctx, req.GetId(), req.GetAuthor())
+	if err != nil {
+		return nil, status.Error(authorErrors.code(err), err.Error())
+	}
+
+	return &pb.UpdateAuthorResponse{Author: author}, nil
+}
+
+func (as *AuthorServer) DeleteAuthor(ctx context.Context, req *pb.DeleteAuthorRequest) (*pb.DeleteAuthorResponse, error) {
+	author, err := as.Store.DeleteAuthor(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(authorErrors.code(err), err.Error())
+	}
+
+	return &pb.DeleteAuthorResponse{Author: author}, nil
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthors_server(t *testing.T) {
+	ctx := context.Background()
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterAuthorServiceServer(s, service.NewAuthorServer(service.NewPostgresAuthorStore(db)))
+		pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+	})
+
+	authors := pb.NewAuthorServiceClient(conn)
+	books := pb.NewBookServiceClient(conn)
+
+	created, err := authors.CreateAuthor(ctx, &pb.CreateAuthorRequest{Author: &pb.Author{Name: "Фёдор Достоевский", Aliases: []string{"Dostoevsky"}}})
+	assert.NoError(t, err)
+	id := created.GetAuthor().GetId()
+
+	_, err = authors.CreateAuthor(ctx, &pb.CreateAuthorRequest{Author: &pb.Author{}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: "idiot", Title: "Идиот", AuthorIds: []string{id}}})
+	assert.NoError(t, err)
+	_, err = books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: "demons", AuthorIds: []string{"nobody"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	search, err := books.SearchBook(ctx, &pb.SearchBookRequest{Filter: &pb.Filter{Author: "Dostoevsky"}})
+	assert.NoError(t, err)
+	res, err := search.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{id}, res.GetBook().GetAuthorIds())
+	_, err = search.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = authors.DeleteAuthor(ctx, &pb.DeleteAuthorRequest{Id: id})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = authors.GetAuthor(ctx, &pb.GetAuthorRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
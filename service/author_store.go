@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAuthor = errors.New("invalid author")
+	ErrAuthorInUse   = errors.New("author has books")
+)
+
+type AuthorStore interface {
+	CreateAuthor(context.Context, *pb.Author) (*pb.Author, error)
+	GetAuthor(context.Context, string) (*pb.Author, error)
+	ListAuthors(context.Context, string) ([]*pb.Author, error)
+	UpdateAuthor(context.Context, string, *pb.Author) (*pb.Author, error)
+	DeleteAuthor(context.Context, string) (*pb.Author, error)
+}
+
+type PostgresAuthorStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresAuthorStore(db *gorm.DB) AuthorStore {
+	return &PostgresAuthorStore{db: db}
+}
+
+func (as *PostgresAuthorStore) CreateAuthor(ctx context.Context, author *pb.Author) (_ *pb.Author, err error) {
+	log.Println("CREATEAUTHOR receive request")
+	ctx, span := startSpan(ctx, "CreateAuthor")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateAuthor(author); err != nil {
+		return nil, err
+	}
+	if author.Id == "" {
+		author.Id = uuid.New().String()
+	}
+
+	err = as.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.Author{ID: author.Id, Name: author.Name}).Error; err != nil {
+			return err
+		}
+
+		return setAliases(tx, author.Id, author.Aliases)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func (as *PostgresAuthorStore) GetAuthor(ctx context.Context, id string) (_ *pb.Author, err error) {
+	log.Println("GETAUTHOR receive request")
+	ctx, span := startSpan(ctx, "GetAuthor")
+	defer func() { endSpan(span, err) }()
+
+	db := as.db.WithContext(ctx)
+
+	var row model.Author
+	if err := db.Where("id = ?", id).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	authors, err := withAliases(db, []*model.Author{&row})
+	if err != nil {
+		return nil, err
+	}
+
+	return authors[0], nil
+}
+
+// ListAuthors returns every author, or only those whose name or one of
+// whose aliases equals name.
+func (as *PostgresAuthorStore) ListAuthors(ctx context.Context, name string) (_ []*pb.Author, err error) {
+	log.Println("LISTAUTHORS receive request")
+	ctx, span := startSpan(ctx, "ListAuthors")
+	defer func() { endSpan(span, err) }()
+
+	db := as.db.WithContext(ctx)
+
+	query := db.Order("name, id")
+	if name != "" {
+		query = query.Where("id IN (?)", authorsNamed(db, name))
+	}
+
+	var rows []*model.Author
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return withAliases(db, rows)
+}
+
+func (as *PostgresAuthorStore) UpdateAuthor(ctx context.Context, id string, author *pb.Author) (_ *pb.Author, err error) {
+	log.Println("UPDATEAUTHOR receive request")
+	ctx, span := startSpan(ctx, "UpdateAuthor")
+	defer func() { endSpan(span, err) }()
+
+	if author.Id != "" && author.Id != id {
+		return nil, fmt.Errorf("%w: id must match the request id", ErrInvalidAuthor)
+	}
+	author.Id = id
+	if err := validateAuthor(author); err != nil {
+		return nil, err
+	}
+
+	err = as.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Author{}).Where("id = ?", id).Update("name", author.Name)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return setAliases(tx, id, author.Aliases)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func (as *PostgresAuthorStore) DeleteAuthor(ctx context.Context, id string) (author *pb.Author, err error) {
+	log.Println("DELETEAUTHOR receive request")
+	ctx, span := startSpan(ctx, "DeleteAuthor")
+	defer func() { endSpan(span, err) }()
+
+	err = as.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row model.Author
+		if err := tx.Where("id = ?", id).First(&row).Error; err != nil {
+			return err
+		}
+
+		authors, err := withAliases(tx, []*model.Author{&row})
+		if err != nil {
+			return err
+		}
+		author = authors[0]
+
+		var books int64
+		if err := tx.Model(&model.BookAuthor{}).Where("author_id = ?", id).Count(&books).Error; err != nil {
+			return err
+		}
+		if books > 0 {
+			return ErrAuthorInUse
+		}
+
+		if err := tx.Where("author_id = ?", id).Delete(&model.AuthorAlias{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&model.Author{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+// validateAuthor trims the name and the aliases and drops empty and
+// repeated aliases.
+func validateAuthor(author *pb.Author) error {
+	author.Name = strings.TrimSpace(author.Name)
+	if author.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAuthor)
+	}
+
+	aliases := make([]string, 0, len(author.Aliases))
+	for _, alias := range author.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias != "" && alias != author.Name && !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	author.Aliases = aliases
+
+	return nil
+}
+
+func setAliases(tx *gorm.DB, authorID string, aliases []string) error {
+	if err := tx.Where("author_id = ?", authorID).Delete(&model.AuthorAlias{}).Error; err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	rows := make([]*model.AuthorAlias, len(aliases))
+	for i, alias := range aliases {
+		rows[i] = &model.AuthorAlias{AuthorID: authorID, Alias: alias}
+	}
+
+	return tx.Create(rows).Error
+}
+
+func withAliases(db *gorm.DB, rows []*model.Author) ([]*pb.Author, error) {
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var aliases []*model.AuthorAlias
+	if err := db.Where("author_id IN ?", ids).Order("alias").Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+
+	byAuthor := make(map[string][]string)
+	for _, alias := range aliases {
+		byAuthor[alias.AuthorID] = append(byAuthor[alias.AuthorID], alias.Alias)
+	}
+
+	authors := make([]*pb.Author, len(rows))
+	for i, row := range rows {
+		authors[i] = row.Proto(byAuthor[row.ID])
+	}
+
+	return authors, nil
+}
+
+// authorsNamed is a subquery of the ids of the authors called name, by
+// their name or an alias.
+func authorsNamed(db *gorm.DB, name string) *gorm.DB {
+	aliased := db.Session(&gorm.Session{NewDB: true}).Model(&model.AuthorAlias{}).Select("author_id").Where("alias = ?", name)
+
+	return db.Session(&gorm.Session{NewDB: true}).Model(&model.Author{}).Select("id").Where("name = ? OR id IN (?)", name, aliased)
+}
@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAuthors_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	authors := service.NewPostgresAuthorStore(db)
+	store := service.NewPostgresStore(db)
+
+	tolstoy, err := authors.CreateAuthor(ctx, &pb.Author{Name: " Лев Толстой ", Aliases: []string{"Leo Tolstoy", "", "Leo Tolstoy", "Лев Толстой"}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tolstoy.Id)
+	assert.Equal(t, "Лев Толстой", tolstoy.Name)
+	assert.Equal(t, []string{"Leo Tolstoy"}, tolstoy.Aliases)
+
+	ilf, err := authors.CreateAuthor(ctx, &pb.Author{Id: "ilf", Name: "Илья Ильф"})
+	assert.NoError(t, err)
+	petrov, err := authors.CreateAuthor(ctx, &pb.Author{Id: "petrov", Name: "Евгений Петров"})
+	assert.NoError(t, err)
+
+	_, err = authors.CreateAuthor(ctx, &pb.Author{Name: " "})
+	assert.ErrorIs(t, err, service.ErrInvalidAuthor)
+
+	t.Run("Books", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "war", Author: "Толстой", Title: "Война и мир", AuthorIds: []string{tolstoy.Id}})
+		assert.NoError(t, err)
+		_, err = store.CreateBook(ctx, &pb.Book{Id: "chairs", Title: "12 стульев", AuthorIds: []string{petrov.Id, ilf.Id, petrov.Id}})
+		assert.NoError(t, err)
+
+		book, err := store.GetBook(ctx, "chairs")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"petrov", "ilf"}, book.AuthorIds)
+
+		_, err = store.CreateBook(ctx, &pb.Book{Id: "unknown", AuthorIds: []string{"nobody"}})
+		assert.ErrorIs(t, err, service.ErrInvalidBook)
+		_, err = store.GetBook(ctx, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		book.AuthorIds = []string{ilf.Id, petrov.Id}
+		updated, err := store.UpdateBook(ctx, "chairs", book, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ilf", "petrov"}, updated.AuthorIds)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		books, err := store.SearchBook(ctx, &pb.Filter{AuthorId: ilf.Id}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		if assert.Len(t, books, 1) {
+			assert.Equal(t, "chairs", books[0].Id)
+		}
+
+		for _, name := range []string{"Leo Tolstoy", "Лев Толстой", "Толстой"} {
+			books, err = store.SearchBook(ctx, &pb.Filter{Author: name}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+			assert.NoError(t, err)
+			if assert.Len(t, books, 1, name) {
+				assert.Equal(t, "war", books[0].Id)
+			}
+		}
+
+		found, err := authors.ListAuthors(ctx, "Leo Tolstoy")
+		assert.NoError(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, tolstoy.Id, found[0].Id)
+		}
+
+		all, err := authors.ListAuthors(ctx, "")
+		assert.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("Update And Delete", func(t *testing.T) {
+		updated, err := authors.UpdateAuthor(ctx, tolstoy.Id, &pb.Author{Name: "Лев Толстой", Aliases: []string{"Count Tolstoy"}})
+		assert.NoError(t, err)
+		assert.Equal(t, tolstoy.Id, updated.Id)
+
+		got, err := authors.GetAuthor(ctx, tolstoy.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Count Tolstoy"}, got.Aliases)
+
+		_, err = authors.UpdateAuthor(ctx, "missing", &pb.Author{Name: "x"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = authors.DeleteAuthor(ctx, tolstoy.Id)
+		assert.ErrorIs(t, err, service.ErrAuthorInUse)
+
+		_, err = store.DeleteBook(ctx, "war")
+		assert.NoError(t, err)
+
+		deleted, err := authors.DeleteAuthor(ctx, tolstoy.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Count Tolstoy"}, deleted.Aliases)
+
+		_, err = authors.GetAuthor(ctx, tolstoy.Id)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bookstoregrpc/cache"
+	"bookstoregrpc/database"
+	"bookstoregrpc/pb"
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const DefaultBookCacheTTL = time.Minute
+
+// CacheStats counts the lookups served by a CachedStore.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// CachedStore is a read-through cache of GetBook in front of another
+// BookStote. Changes made through the store invalidate the cached books,
+// and so do changes made elsewhere that call Invalidate, such as rating
+// changes from the review store. Author and category changes do not touch
+// the cached fields: books only hold their ids, and authors and categories
+// with books cannot be deleted. The effective price is the one field that
+// can lag, by up to the TTL, behind changes to price rules and to the
+// parents of categories. Concurrent misses for a book share one lookup.
+type CachedStore struct {
+	BookStote
+	cache cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+
+	// epoch is bumped by every invalidation, so that a lookup started
+	// before a change does not cache the book it read.
+	epoch  atomic.Uint64
+	hits   atomic.Uint64
+	misses atomic.Uint64
+
+	hitCounter  metric.Int64Counter
+	missCounter metric.Int64Counter
+}
+
+func NewCachedStore(store BookStote, c cache.Cache, ttl time.Duration) *CachedStore {
+	cs := &CachedStore{BookStote: store, cache: c, ttl: ttl}
+
+	meter := otel.Meter(tracerName)
+	cs.hitCounter, _ = meter.Int64Counter("bookstore.book_cache.hits", metric.WithDescription("GetBook calls served from the cache"))
+	cs.missCounter, _ = meter.Int64Counter("bookstore.book_cache.misses", metric.WithDescription("GetBook calls read from the store"))
+
+	return cs
+}
+
+func (cs *CachedStore) Stats() CacheStats {
+	return CacheStats{Hits: cs.hits.Load(), Misses: cs.misses.Load()}
+}
+
+func (cs *CachedStore) GetBook(ctx context.Context, id string) (*pb.Book, error) {
+	key := bookCacheKey(id)
+
+	value, ok, err := cs.cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("book cache get %s: %v", id, err)
+	}
+	if ok {
+		var book pb.Book
+		if err := proto.Unmarshal(value, &book); err == nil {
+			cs.hits.Add(1)
+			cs.hitCounter.Add(ctx, 1)
+			return &book, nil
+		}
+	}
+
+	cs.misses.Add(1)
+	cs.missCounter.Add(ctx, 1)
+
+	book, err, _ := cs.group.Do(id, func() (any, error) {
+		// The lookup is shared, so it must not fail when the caller that
+		// started it goes away. It reads from the primary so that a lagging
+		// replica cannot put a book back in the cache right after a change.
+		ctx := database.UsePrimary(context.WithoutCancel(ctx))
+
+		epoch := cs.epoch.Load()
+		book, err := cs.BookStote.GetBook(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, err := proto.Marshal(book); err == nil && cs.epoch.Load() == epoch {
+			if err := cs.cache.Set(ctx, key, value, cs.ttl); err != nil {
+				log.Printf("book cache set %s: %v", id, err)
+			}
+		}
+
+		return book, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers may modify the book, and it is shared with the other callers
+	// of the same lookup.
+	return proto.Clone(book.(*pb.Book)).(*pb.Book), nil
+}
+
+func (cs *CachedStore) UpdateBook(ctx context.Context, id string, book *pb.Book, mask *fieldmaskpb.FieldMask) (*pb.Book, error) {
+	defer cs.Invalidate(ctx, id)
+	return cs.BookStote.UpdateBook(ctx, id, book, mask)
+}
+
+func (cs *CachedStore) DeleteBook(ctx context.Context, id string) (*pb.Book, error) {
+	defer cs.Invalidate(ctx, id)
+	return cs.BookStote.DeleteBook(ctx, id)
+}
+
+func (cs *CachedStore) BatchUpdateBooks(ctx context.Context, ids []string, books []*pb.Book, masks []*fieldmaskpb.FieldMask, atomic bool) ([]*pb.Book, []error, error) {
+	defer cs.Invalidate(ctx, ids...)
+	return cs.BookStote.BatchUpdateBooks(ctx, ids, books, masks, atomic)
+}
+
+func (cs *CachedStore) BatchDeleteBooks(ctx context.Context, ids []string, atomic bool) ([]*pb.Book, []error, error) {
+	defer cs.Invalidate(ctx, ids...)
+	return cs.BookStote.BatchDeleteBooks(ctx, ids, atomic)
+}
+
+func (cs *CachedStore) RollbackBook(ctx context.Context, id string, n int64) (*pb.Book, error) {
+	defer cs.Invalidate(ctx, id)
+	return cs.BookStote.RollbackBook(ctx, id, n)
+}
+
+// ImportBooks invalidates the imported books when the import is closed,
+// since the import may overwrite existing books.
+func (cs *CachedStore) ImportBooks(ctx context.Context, opts ImportOptions) BookImporter {
+	return &cachedImporter{BookImporter: cs.BookStote.ImportBooks(ctx, opts), ctx: ctx, cs: cs}
+}
+
+type cachedImporter struct {
+	BookImporter
+	ctx context.Context
+	cs  *CachedStore
+	ids []string
+}
+
+func (ci *cachedImporter) Add(book *pb.Book) error {
+	ci.ids = append(ci.ids, book.GetId())
+	return ci.BookImporter.Add(book)
+}
+
+func (ci *cachedImporter) Close() (*ImportResult, error) {
+	defer ci.cs.Invalidate(ci.ctx, ci.ids...)
+	return ci.BookImporter.Close()
+}
+
+// Invalidate drops books from the cache. It is for changes to books made
+// outside of the store, such as new ratings.
+func (cs *CachedStore) Invalidate(ctx context.Context, ids ...string) {
+	cs.epoch.Add(1)
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = bookCacheKey(id)
+	}
+	if err := cs.cache.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+		log.Printf("book cache delete %v: %v", ids, err)
+	}
+}
+
+func bookCacheKey(id string) string {
+	return "book:" + id
+}
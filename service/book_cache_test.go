@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/cache"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/review"
+	"bookstoregrpc/service"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// slowStore counts GetBook calls and makes them slow enough to overlap.
+type slowStore struct {
+	service.BookStote
+	calls atomic.Int64
+}
+
+func (s *slowStore) GetBook(ctx context.Context, id string) (*pb.Book, error) {
+	s.calls.Add(1)
+	time.Sleep(20 * time.Millisecond)
+	return s.BookStote.GetBook(ctx, id)
+}
+
+func TestCachedStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	backend := &slowStore{BookStote: service.NewPostgresStore(initTestDB(t))}
+	store := service.NewCachedStore(backend, cache.NewLRU(100, 0), time.Minute)
+
+	_, err := store.CreateBook(ctx, &pb.Book{Id: "karamazov", Title: "Братья Карамазовы", Price: 500})
+	assert.NoError(t, err)
+
+	t.Run("Singleflight", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				book, err := store.GetBook(ctx, "karamazov")
+				assert.NoError(t, err)
+				assert.Equal(t, "Братья Карамазовы", book.Title)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(1), backend.calls.Load())
+		assert.Equal(t, uint64(10), store.Stats().Hits+store.Stats().Misses)
+	})
+
+	t.Run("Hit", func(t *testing.T) {
+		before := store.Stats()
+		book, err := store.GetBook(ctx, "karamazov")
+		assert.NoError(t, err)
+		book.Title = "changed by the caller"
+
+		book, err = store.GetBook(ctx, "karamazov")
+		assert.NoError(t, err)
+		assert.Equal(t, "Братья Карамазовы", book.Title)
+		assert.Equal(t, before.Hits+2, store.Stats().Hits)
+		assert.Equal(t, int64(1), backend.calls.Load())
+	})
+
+	t.Run("Invalidation", func(t *testing.T) {
+		_, err := store.UpdateBook(ctx, "karamazov", &pb.Book{Id: "karamazov", Title: "Карамазовы", Price: 500}, nil)
+		assert.NoError(t, err)
+
+		book, err := store.GetBook(ctx, "karamazov")
+		assert.NoError(t, err)
+		assert.Equal(t, "Карамазовы", book.Title)
+		assert.Equal(t, int64(2), backend.calls.Load())
+
+		_, err = store.DeleteBook(ctx, "karamazov")
+		assert.NoError(t, err)
+		_, err = store.GetBook(ctx, "karamazov")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "idiot", Title: "Идиот", Price: 300})
+		assert.NoError(t, err)
+		_, err = store.GetBook(ctx, "idiot")
+		assert.NoError(t, err)
+
+		importer := store.ImportBooks(ctx, service.ImportOptions{ChunkSize: 10, Upsert: true})
+		assert.NoError(t, importer.Add(&pb.Book{Id: "idiot", Title: "Идиот (новый перевод)", Price: 300}))
+		_, err = importer.Close()
+		assert.NoError(t, err)
+
+		book, err := store.GetBook(ctx, "idiot")
+		assert.NoError(t, err)
+		assert.Equal(t, "Идиот (новый перевод)", book.Title)
+	})
+}
+
+func TestCachedStore_ratings(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+	store := service.NewCachedStore(service.NewPostgresStore(db), cache.NewLRU(100, 0), time.Minute)
+	reviews := review.NewStore(db, audit.ParsePrincipals("moderator"))
+	reviews.OnRatingChange(store.Invalidate)
+
+	ctx := audit.NewContext(context.Background(), audit.Info{Principal: "alice"})
+	_, err := store.CreateBook(ctx, &pb.Book{Id: "karamazov", Title: "Братья Карамазовы", Price: 500})
+	assert.NoError(t, err)
+	_, err = store.GetBook(ctx, "karamazov")
+	assert.NoError(t, err)
+
+	created, err := reviews.Create(ctx, "karamazov", &pb.Review{Rating: 4})
+	assert.NoError(t, err)
+	moderator := audit.NewContext(context.Background(), audit.Info{Principal: "moderator"})
+	_, err = reviews.Moderate(moderator, created.Id, pb.Review_APPROVED)
+	assert.NoError(t, err)
+
+	book, err := store.GetBook(ctx, "karamazov")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), book.RatingCount)
+	assert.Equal(t, 4.0, book.RatingAverage)
+}
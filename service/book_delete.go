@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/order"
+	"bookstoregrpc/review"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var ErrBookInStock = errors.New("book has copies in stock; adjust its stock to zero before deleting it")
+
+// stockedBooks returns those of bookIDs that have copies on hand. Reserved
+// copies are on hand too, so a book with reservations is among them.
+func stockedBooks(tx *gorm.DB, bookIDs []string) (map[string]bool, error) {
+	var ids []string
+	err := tx.Model(&inventory.Stock{}).Where("book_id IN ? AND on_hand > 0", bookIDs).Distinct().Pluck("book_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stocked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		stocked[id] = true
+	}
+
+	return stocked, nil
+}
+
+// deleteBookDependents deletes the rows of other stores that refer to
+// books about to be deleted: their empty stock levels, their reviews and
+// the items of carts not yet placed. Orders keep their items, which are
+// snapshots of the book.
+func deleteBookDependents(tx *gorm.DB, bookIDs ...string) error {
+	for _, table := range []any{&inventory.Stock{}, &review.Review{}} {
+		if err := tx.Where("book_id IN ?", bookIDs).Delete(table).Error; err != nil {
+			return err
+		}
+	}
+
+	open := tx.Model(&order.Cart{}).Select("id").Where("order_id = ?", "")
+	return tx.Where("book_id IN ? AND cart_id IN (?)", bookIDs, open).Delete(&order.CartItem{}).Error
+}
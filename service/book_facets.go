@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"cmp"
+	"context"
+	"log"
+	"maps"
+	"slices"
+)
+
+// priceBucketBounds are the lower bounds of the price buckets, in whole
+// units of the book's currency.
+var priceBucketBounds = []int64{0, 500, 1000, 2000, 5000}
+
+// BookFacets counts books, usually the results of a search, per category,
+// author and price bucket. Empty categories, authors and buckets are left
+// out.
+func (ps *PostgresStore) BookFacets(ctx context.Context, books []*pb.Book) (facets *pb.Facets, err error) {
+	log.Println("BOOKFACETS receive request")
+	ctx, span := startSpan(ctx, "BookFacets")
+	defer func() { endSpan(span, err) }()
+
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	db := ps.db.WithContext(ctx)
+
+	tree, err := loadCategoryTree(db)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]int64)
+	authors := make(map[string]int64)
+	buckets := make(map[string][]int64)
+	for _, book := range books {
+		// A book filed under two subcategories of the same category counts
+		// once for it.
+		seen := make(map[string]bool)
+		for _, id := range book.GetCategoryIds() {
+			for _, ancestor := range tree.ancestors(id) {
+				if !seen[ancestor] {
+					seen[ancestor] = true
+					categories[ancestor]++
+				}
+			}
+		}
+
+		for _, id := range book.GetAuthorIds() {
+			authors[id]++
+		}
+
+		if price := book.GetListPrice(); price != nil {
+			currency := price.GetCurrencyCode()
+			if buckets[currency] == nil {
+				buckets[currency] = make([]int64, len(priceBucketBounds))
+			}
+			buckets[currency][priceBucket(money.AmountOf(price))]++
+		}
+	}
+
+	facets = &pb.Facets{}
+	for id, count := range categories {
+		facets.Categories = append(facets.Categories, &pb.FacetCount{Value: id, Label: tree.get(id).Name, Count: count})
+	}
+	sortFacetCounts(facets.Categories)
+
+	if len(authors) > 0 {
+		var rows []*model.Author
+		if err := db.Where("id IN ?", slices.Collect(maps.Keys(authors))).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			facets.Authors = append(facets.Authors, &pb.FacetCount{Value: row.ID, Label: row.Name, Count: authors[row.ID]})
+		}
+		sortFacetCounts(facets.Authors)
+	}
+
+	for _, currency := range slices.Sorted(maps.Keys(buckets)) {
+		for i, count := range buckets[currency] {
+			if count == 0 {
+				continue
+			}
+			bucket := &pb.PriceBucket{Min: money.New(currency, priceBucketBounds[i], 0), Count: count}
+			if i+1 < len(priceBucketBounds) {
+				bucket.Max = money.New(currency, priceBucketBounds[i+1], 0)
+			}
+			facets.PriceBuckets = append(facets.PriceBuckets, bucket)
+		}
+	}
+
+	return facets, nil
+}
+
+// ListTags returns every tag in use with the number of books that carry it.
+func (ps *PostgresStore) ListTags(ctx context.Context) (tags []*pb.FacetCount, err error) {
+	log.Println("LISTTAGS receive request")
+	ctx, span := startSpan(ctx, "ListTags")
+	defer func() { endSpan(span, err) }()
+
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	var rows []struct {
+		Tag   string
+		Count int64
+	}
+	err = ps.db.WithContext(ctx).Model(&model.BookTag{}).
+		Select("tag, COUNT(*) AS count").
+		Group("tag").
+		Order("count DESC, tag").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tags = make([]*pb.FacetCount, len(rows))
+	for i, row := range rows {
+		tags[i] = &pb.FacetCount{Value: row.Tag, Count: row.Count}
+	}
+
+	return tags, nil
+}
+
+func priceBucket(price money.Amount) int {
+	i := len(priceBucketBounds) - 1
+	for i > 0 && price.Cmp(money.Amount{Units: priceBucketBounds[i]}) < 0 {
+		i--
+	}
+
+	return i
+}
+
+// sortFacetCounts orders counts from the largest, then by label.
+func sortFacetCounts(counts []*pb.FacetCount) {
+	slices.SortFunc(counts, func(a, b *pb.FacetCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Label, b.Label), cmp.Compare(a.Value, b.Value))
+	})
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultImportChunkSize = 500
+	MaxImportChunkSize     = 5000
+
+	// maxImportErrorSamples caps how many row errors an import reports.
+	maxImportErrorSamples = 10
+)
+
+var ErrImportClosed = errors.New("import is already closed")
+
+type ImportOptions struct {
+	// ChunkSize is the number of books buffered before they are written.
+	ChunkSize int
+	// Upsert overwrites books whose id already exists instead of failing.
+	Upsert bool
+}
+
+type ImportError struct {
+	Index  int64
+	BookID string
+	Err    error
+}
+
+type ImportResult struct {
+	Created int64
+	Updated int64
+	Failed  int64
+	Errors  []ImportError
+}
+
+type BookImporter interface {
+	// Add buffers a book and writes the buffer once it holds ChunkSize books.
+	// Rows rejected by the database are reported in the result, not here.
+	Add(*pb.Book) error
+	// Close writes the remaining books and returns the totals.
+	Close() (*ImportResult, error)
+}
+
+type postgresImporter struct {
+	ctx    context.Context
+	ps     *PostgresStore
+	opts   ImportOptions
+	buffer []*pb.Book
+	// indexes holds the stream position of every buffered book.
+	indexes []int64
+	next    int64
+	result  ImportResult
+	closed  bool
+}
+
+func (ps *PostgresStore) ImportBooks(ctx context.Context, opts ImportOptions) BookImporter {
+	log.Println("IMPORTBOOKS receive request")
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultImportChunkSize
+	}
+	if opts.ChunkSize > MaxImportChunkSize {
+		opts.ChunkSize = MaxImportChunkSize
+	}
+
+	return &postgresImporter{
+		ctx:     ctx,
+		ps:      ps,
+		opts:    opts,
+		buffer:  make([]*pb.Book, 0, opts.ChunkSize),
+		indexes: make([]int64, 0, opts.ChunkSize),
+	}
+}
+
+func (pi *postgresImporter) Add(book *pb.Book) error {
+	if pi.closed {
+		return ErrImportClosed
+	}
+
+	index := pi.next
+	pi.next++
+
+	if book.GetId() == "" {
+		pi.fail(index, "", errors.New("book id is required"))
+		return nil
+	}
+	if err := validateBook(book); err != nil {
+		pi.fail(index, book.Id, err)
+		return nil
+	}
+
+	pi.buffer = append(pi.buffer, book)
+	pi.indexes = append(pi.indexes, index)
+	if len(pi.buffer) < pi.opts.ChunkSize {
+		return nil
+	}
+
+	return pi.flush()
+}
+
+func (pi *postgresImporter) Close() (*ImportResult, error) {
+	if pi.closed {
+		return nil, ErrImportClosed
+	}
+	pi.closed = true
+
+	if err := pi.flush(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pi.result.Errors, func(i, j int) bool {
+		return pi.result.Errors[i].Index < pi.result.Errors[j].Index
+	})
+
+	return &pi.result, nil
+}
+
+func (pi *postgresImporter) fail(index int64, id string, err error) {
+	pi.result.Failed++
+	if len(pi.result.Errors) < maxImportErrorSamples {
+		pi.result.Errors = append(pi.result.Errors, ImportError{Index: index, BookID: id, Err: err})
+	}
+}
+
+// flush writes the buffered books in one transaction. The chunk is sent as
+// a single INSERT first; if that fails, the rows are retried one by one in
+// savepoints so that only the offending rows are rejected.
+func (pi *postgresImporter) flush() (err error) {
+	if len(pi.buffer) == 0 {
+		return nil
+	}
+
+	chunk, indexes := pi.buffer, pi.indexes
+	defer func() {
+		pi.buffer = pi.buffer[:0]
+		pi.indexes = pi.indexes[:0]
+	}()
+
+	ctx, span := startSpan(pi.ctx, "ImportBooks.flush")
+	defer func() { endSpan(span, err) }()
+
+	pi.ps.lock(span)
+	defer pi.ps.mu.Unlock()
+
+	// The totals only take the chunk in once it is committed.
+	var (
+		changes changeSet
+		failed  []ImportError
+	)
+	err = pi.ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := existingBooks(tx, chunk)
+		if err != nil {
+			return err
+		}
+
+		written := chunk
+		bulkErr := tx.Transaction(func(tx *gorm.DB) error {
+			return pi.insert(tx, chunk...)
+		})
+		if bulkErr != nil {
+			written = nil
+			for i, book := range chunk {
+				rowErr := tx.Transaction(func(tx *gorm.DB) error {
+					return pi.insert(tx, book)
+				})
+				if rowErr != nil {
+					failed = append(failed, ImportError{Index: indexes[i], BookID: book.Id, Err: rowErr})
+					continue
+				}
+				written = append(written, book)
+			}
+		}
+
+		// A book seen earlier in the same chunk counts as an update.
+		for _, book := range written {
+			before, ok := existing[book.Id]
+			existing[book.Id] = book
+			if !ok {
+				if err := changes.record(tx, pb.BookEvent_CREATED, nil, book); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := changes.record(tx, pb.BookEvent_UPDATED, before, book); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rowErr := range failed {
+		pi.fail(rowErr.Index, rowErr.BookID, rowErr.Err)
+	}
+	for _, event := range changes {
+		if event.Type == pb.BookEvent_CREATED {
+			pi.result.Created++
+			continue
+		}
+		pi.result.Updated++
+	}
+	pi.ps.publish(changes)
+
+	return nil
+}
+
+func (pi *postgresImporter) insert(tx *gorm.DB, books ...*pb.Book) error {
+	insert := tx
+	if pi.opts.Upsert {
+		insert = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns(bookColumns),
+		})
+	}
+
+	if err := insert.CreateInBatches(model.NewBooks(books), batchSize).Error; err != nil {
+		return err
+	}
+	for _, book := range books {
+		if err := setBookLinks(tx, book); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func existingBooks(tx *gorm.DB, chunk []*pb.Book) (map[string]*pb.Book, error) {
+	ids := make([]string, len(chunk))
+	for i, book := range chunk {
+		ids[i] = book.Id
+	}
+
+	var found []*model.Book
+	if err := tx.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+
+	books, err := withBookLinks(tx, model.Protos(found)...)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]*pb.Book, len(books))
+	for _, book := range books {
+		existing[book.Id] = book
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxTagLength is the longest tag accepted, in bytes.
+const maxTagLength = 64
+
+// setBookLinks replaces the authors, categories and tags of a book. The
+// order of the authors is kept. Every author and category must exist.
+func setBookLinks(tx *gorm.DB, book *pb.Book) error {
+	if err := deleteBookLinks(tx, book.Id); err != nil {
+		return err
+	}
+
+	if err := checkExist(tx, &model.Author{}, "author", book.AuthorIds); err != nil {
+		return err
+	}
+	if err := checkExist(tx, &model.Category{}, "category", book.CategoryIds); err != nil {
+		return err
+	}
+
+	if len(book.AuthorIds) > 0 {
+		rows := make([]*model.BookAuthor, len(book.AuthorIds))
+		for i, id := range book.AuthorIds {
+			rows[i] = &model.BookAuthor{BookID: book.Id, AuthorID: id, Position: i}
+		}
+		if err := tx.Create(rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(book.CategoryIds) > 0 {
+		rows := make([]*model.BookCategory, len(book.CategoryIds))
+		for i, id := range book.CategoryIds {
+			rows[i] = &model.BookCategory{BookID: book.Id, CategoryID: id}
+		}
+		if err := tx.Create(rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(book.Tags) > 0 {
+		rows := make([]*model.BookTag, len(book.Tags))
+		for i, tag := range book.Tags {
+			rows[i] = &model.BookTag{BookID: book.Id, Tag: tag}
+		}
+		if err := tx.Create(rows).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkExist(tx *gorm.DB, table any, name string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var found int64
+	if err := tx.Model(table).Where("id IN ?", ids).Count(&found).Error; err != nil {
+		return err
+	}
+	if found != int64(len(ids)) {
+		return fmt.Errorf("%w: unknown %s id", ErrInvalidBook, name)
+	}
+
+	return nil
+}
+
+func deleteBookLinks(tx *gorm.DB, bookIDs ...string) error {
+	for _, table := range []any{&model.BookAuthor{}, &model.BookCategory{}, &model.BookTag{}} {
+		if err := tx.Where("book_id IN ?", bookIDs).Delete(table).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withBookLinks fills the author ids, categories and tags of books.
+func withBookLinks(db *gorm.DB, books ...*pb.Book) ([]*pb.Book, error) {
+	if len(books) == 0 {
+		return books, nil
+	}
+
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.Id
+	}
+
+	var authors []*model.BookAuthor
+	if err := db.Where("book_id IN ?", ids).Order("book_id, position").Find(&authors).Error; err != nil {
+		return nil, err
+	}
+	var categories []*model.BookCategory
+	if err := db.Where("book_id IN ?", ids).Order("book_id, category_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	var tags []*model.BookTag
+	if err := db.Where("book_id IN ?", ids).Order("book_id, tag").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*pb.Book, len(books))
+	for _, book := range books {
+		book.AuthorIds, book.CategoryIds, book.Tags = nil, nil, nil
+		byID[book.Id] = book
+	}
+	for _, link := range authors {
+		byID[link.BookID].AuthorIds = append(byID[link.BookID].AuthorIds, link.AuthorID)
+	}
+	for _, link := range categories {
+		byID[link.BookID].CategoryIds = append(byID[link.BookID].CategoryIds, link.CategoryID)
+	}
+	for _, link := range tags {
+		byID[link.BookID].Tags = append(byID[link.BookID].Tags, link.Tag)
+	}
+
+	return books, nil
+}
+
+// validateLinks drops empty and repeated author and category ids and
+// normalizes the tags.
+func validateLinks(book *pb.Book) error {
+	book.AuthorIds = compactIDs(book.AuthorIds)
+	book.CategoryIds = compactIDs(book.CategoryIds)
+
+	for i, tag := range book.Tags {
+		book.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+		if len(book.Tags[i]) > maxTagLength {
+			return fmt.Errorf("%w: tags must not be longer than %d bytes", ErrInvalidBook, maxTagLength)
+		}
+	}
+	book.Tags = compactIDs(book.Tags)
+	slices.Sort(book.Tags)
+
+	return nil
+}
+
+// compactIDs drops empty and repeated values, keeping the first of each.
+func compactIDs(ids []string) []string {
+	compact := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !slices.Contains(compact, id) {
+			compact = append(compact, id)
+		}
+	}
+	if len(compact) == 0 {
+		return nil
+	}
+
+	return compact
+}
+
+// booksBy is a subquery of the ids of the books written by authors, a list
+// of author ids or a subquery of them.
+func booksBy(db *gorm.DB, authors any) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&model.BookAuthor{}).Select("book_id").Where("author_id IN (?)", authors)
+}
+
+// booksIn is a subquery of the ids of the books filed under any of the
+// categories.
+func booksIn(db *gorm.DB, categories []string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&model.BookCategory{}).Select("book_id").Where("category_id IN ?", categories)
+}
+
+// booksTagged is a subquery of the ids of the books that carry tag.
+func booksTagged(db *gorm.DB, tag string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&model.BookTag{}).Select("book_id").Where("tag = ?", strings.ToLower(strings.TrimSpace(tag)))
+}
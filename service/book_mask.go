@@ -0,0 +1,51 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// replacesBook reports whether an update with mask replaces the whole
+// book: the mask is empty or "*".
+func replacesBook(mask *fieldmaskpb.FieldMask) bool {
+	paths := mask.GetPaths()
+	return len(paths) == 0 || (len(paths) == 1 && paths[0] == "*")
+}
+
+// applyMask returns a copy of current with the fields named by mask taken
+// from patch. A nested path takes its whole top-level field. Setting only
+// the legacy price clears the list price, so that updateBook keeps it if
+// the price is unchanged and otherwise derives it from the new price.
+func applyMask(current, patch *pb.Book, mask *fieldmaskpb.FieldMask) (*pb.Book, error) {
+	book := proto.Clone(current).(*pb.Book)
+	dst, src := book.ProtoReflect(), patch.ProtoReflect()
+	fields := dst.Descriptor().Fields()
+
+	var names []string
+	for _, path := range mask.GetPaths() {
+		name, _, _ := strings.Cut(path, ".")
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil {
+			return nil, fmt.Errorf("%w: unknown field %q in update mask", ErrInvalidBook, path)
+		}
+		names = append(names, name)
+
+		if src.Has(field) {
+			dst.Set(field, src.Get(field))
+		} else {
+			dst.Clear(field)
+		}
+	}
+
+	if slices.Contains(names, "price") && !slices.Contains(names, "list_price") {
+		book.ListPrice = nil
+	}
+
+	return book, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// withEffectivePrices sets the effective price of books from the price
+// rules active at t. Category rules also apply to books of subcategories.
+func withEffectivePrices(db *gorm.DB, t time.Time, books ...*pb.Book) ([]*pb.Book, error) {
+	if len(books) == 0 {
+		return books, nil
+	}
+
+	rules, err := pricing.Active(db, t)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := loadCategoryTree(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, book := range books {
+		var categories []string
+		for _, id := range book.GetCategoryIds() {
+			categories = append(categories, tree.ancestors(id)...)
+		}
+		pricing.Apply(rules, book, categories)
+	}
+
+	return books, nil
+}
+
+// filterEffectivePrice drops the books whose effective price is outside
+// the effective price bounds of filter.
+func filterEffectivePrice(books []*pb.Book, filter *pb.Filter) []*pb.Book {
+	minPrice, maxPrice := filter.GetMinEffectivePrice(), filter.GetMaxEffectivePrice()
+	if minPrice == nil && maxPrice == nil {
+		return books
+	}
+
+	filtered := books[:0]
+	for _, book := range books {
+		price := book.GetEffectivePrice()
+		if price == nil {
+			continue
+		}
+		if minPrice != nil && (price.CurrencyCode != minPrice.CurrencyCode || money.AmountOf(price).Cmp(money.AmountOf(minPrice)) < 0) {
+			continue
+		}
+		if maxPrice != nil && (price.CurrencyCode != maxPrice.CurrencyCode || money.AmountOf(price).Cmp(money.AmountOf(maxPrice)) > 0) {
+			continue
+		}
+		filtered = append(filtered, book)
+	}
+
+	return filtered
+}
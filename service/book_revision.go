@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/revision"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+var ErrRevisionDeleted = errors.New("revision is a deletion and cannot be restored")
+
+// GetBookAsOf returns the book as it was at t, from its revision history.
+func (ps *PostgresStore) GetBookAsOf(ctx context.Context, id string, t time.Time) (book *pb.Book, err error) {
+	log.Println("GETBOOKASOF receive request")
+	ctx, span := startSpan(ctx, "GetBookAsOf")
+	defer func() { endSpan(span, err) }()
+
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	db := ps.db.WithContext(ctx)
+
+	rev, err := revision.AsOf(db, id, t)
+	if err != nil {
+		return nil, err
+	}
+	if rev.Book == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	books, err := withEffectivePrices(db, t, rev.Book)
+	if err != nil {
+		return nil, err
+	}
+
+	return books[0], nil
+}
+
+func (ps *PostgresStore) ListBookRevisions(ctx context.Context, id string) (revisions []*pb.BookRevision, err error) {
+	log.Println("LISTBOOKREVISIONS receive request")
+	ctx, span := startSpan(ctx, "ListBookRevisions")
+	defer func() { endSpan(span, err) }()
+
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	revisions, err = revision.List(ps.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return revisions, nil
+}
+
+func (ps *PostgresStore) GetBookRevision(ctx context.Context, id string, n int64) (rev *pb.BookRevision, err error) {
+	log.Println("GETBOOKREVISION receive request")
+	ctx, span := startSpan(ctx, "GetBookRevision")
+	defer func() { endSpan(span, err) }()
+
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	return revision.Get(ps.db.WithContext(ctx), id, n)
+}
+
+// RollbackBook restores the book stored in revision n. The book is
+// recreated if it has been deleted since.
+func (ps *PostgresStore) RollbackBook(ctx context.Context, id string, n int64) (book *pb.Book, err error) {
+	log.Println("ROLLBACKBOOK receive request")
+	ctx, span := startSpan(ctx, "RollbackBook")
+	defer func() { endSpan(span, err) }()
+
+	ps.lock(span)
+	defer ps.mu.Unlock()
+
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		target, err := revision.Get(tx, id, n)
+		if err != nil {
+			return err
+		}
+		if target.Book == nil {
+			return ErrRevisionDeleted
+		}
+
+		var before *pb.Book
+		before, book, err = updateBook(tx, id, target.Book, nil)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			book = target.Book
+			if err := validateBook(book); err != nil {
+				return err
+			}
+			if err := tx.Create(model.NewBook(book)).Error; err != nil {
+				return err
+			}
+			if err := setBookLinks(tx, book); err != nil {
+				return err
+			}
+
+			return changes.record(tx, pb.BookEvent_CREATED, nil, book)
+		}
+		if err != nil {
+			return err
+		}
+
+		return changes.record(tx, pb.BookEvent_UPDATED, before, book)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ps.publish(changes)
+
+	return book, nil
+}
+
+func (bs *BookServer) ListBookRevisions(ctx context.Context, req *pb.ListBookRevisionsRequest) (*pb.ListBookRevisionsResponse, error) {
+	revisions, err := bs.Store.ListBookRevisions(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(bookErrors.code(err), err.Error())
+	}
+
+	return &pb.ListBookRevisionsResponse{Revisions: revisions}, nil
+}
+
+func (bs *BookServer) GetBookRevision(ctx context.Context, req *pb.GetBookRevisionRequest) (*pb.GetBookRevisionResponse, error) {
+	rev, err := bs.Store.GetBookRevision(ctx, req.GetId(), req.GetRevision())
+	if err != nil {
+		return nil, status.Error(bookErrors.code(err), err.Error())
+	}
+
+	return &pb.GetBookRevisionResponse{Revision: rev}, nil
+}
+
+func (bs *BookServer) RollbackBook(ctx context.Context, req *pb.RollbackBookRequest) (*pb.RollbackBookResponse, error) {
+	book, err := bs.Store.RollbackBook(ctx, req.GetId(), req.GetRevision())
+	if err != nil {
+		return nil, status.Error(bookErrors.code(err), err.Error())
+	}
+
+	return &pb.RollbackBookResponse{Book: book}, nil
+}
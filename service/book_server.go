@@ -3,8 +3,13 @@ package service
 import (
 	"bookstoregrpc/pb"
 	"context"
+	"errors"
+	"io"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type BookServer struct {
@@ -12,16 +17,28 @@ type BookServer struct {
 	pb.UnimplementedBookServiceServer
 }
 
+var bookErrors = errorCodes{
+	{ErrBookIDMismatch, codes.InvalidArgument},
+	{ErrInvalidBook, codes.InvalidArgument},
+	{ErrInvalidFilter, codes.InvalidArgument},
+	{ErrDuplicateID, codes.InvalidArgument},
+	{ErrRevisionDeleted, codes.FailedPrecondition},
+	{ErrBookInStock, codes.FailedPrecondition},
+}
+
 func NewBookServer(store BookStote) *BookServer {
 	return &BookServer{Store: store}
 }
 
 func (bs *BookServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.CreateBookResponse, error) {
 	book := req.GetBook()
+	if book == nil {
+		return nil, status.Error(codes.InvalidArgument, "book is required")
+	}
 
-	id, err := bs.Store.CreateBook(book)
+	id, err := bs.Store.CreateBook(ctx, book)
 	if err != nil {
-		return nil, err
+		return nil, status.Error(bookErrors.code(err), err.Error())
 	}
 
 	res := &pb.CreateBookResponse{
@@ -34,9 +51,15 @@ func (bs *BookServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest)
 func (bs *BookServer) ReadBook(ctx context.Context, req *pb.ReadBookRequest) (*pb.ReadBookResponse, error) {
 	id := req.GetId()
 
-	book, err := bs.Store.GetBook(id)
+	var book *pb.Book
+	var err error
+	if req.GetAsOf() != nil {
+		book, err = bs.Store.GetBookAsOf(ctx, id, req.GetAsOf().AsTime())
+	} else {
+		book, err = bs.Store.GetBook(ctx, id)
+	}
 	if err != nil {
-		return nil, err
+		return nil, status.Error(bookErrors.code(err), err.Error())
 	}
 
 	res := &pb.ReadBookResponse{
@@ -47,9 +70,9 @@ func (bs *BookServer) ReadBook(ctx context.Context, req *pb.ReadBookRequest) (*p
 }
 
 func (bs *BookServer) ReadBooks(_ *emptypb.Empty, stream pb.BookService_ReadBooksServer) error {
-	books, err := bs.Store.GetBooks()
+	books, err := bs.Store.GetBooks(stream.Context())
 	if err != nil {
-		return err
+		return status.Error(bookErrors.code(err), err.Error())
 	}
 
 	for _, book := range books {
@@ -69,10 +92,18 @@ func (bs *BookServer) ReadBooks(_ *emptypb.Empty, stream pb.BookService_ReadBook
 func (bs *BookServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.UpdateBookResponse, error) {
 	id := req.GetId()
 	newBook := req.GetBook()
+	if newBook == nil {
+		return nil, status.Error(codes.InvalidArgument, "book is required")
+	}
 
-	book, err := bs.Store.UpdateBook(id, newBook)
+	// REST clients send the id only in the path (PATCH /v1/books/{id}).
+	if newBook.Id == "" {
+		newBook.Id = id
+	}
+
+	book, err := bs.Store.UpdateBook(ctx, id, newBook, req.GetUpdateMask())
 	if err != nil {
-		return nil, err
+		return nil, status.Error(bookErrors.code(err), err.Error())
 	}
 
 	res := &pb.UpdateBookResponse{
@@ -85,9 +116,9 @@ func (bs *BookServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest)
 func (bs *BookServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*pb.DeleteBookResponse, error) {
 	id := req.GetId()
 
-	book, err := bs.Store.DeleteBook(id)
+	book, err := bs.Store.DeleteBook(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, status.Error(bookErrors.code(err), err.Error())
 	}
 
 	res := &pb.DeleteBookResponse{
@@ -100,9 +131,9 @@ func (bs *BookServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest)
 func (bs *BookServer) SearchBook(req *pb.SearchBookRequest, stream pb.BookService_SearchBookServer) error {
 	filter := req.GetFilter()
 
-	books, err := bs.Store.SearchBook(filter)
+	books, err := bs.Store.SearchBook(stream.Context(), filter, req.GetSort())
 	if err != nil {
-		return err
+		return status.Error(bookErrors.code(err), err.Error())
 	}
 
 	for _, book := range books {
@@ -116,5 +147,163 @@ func (bs *BookServer) SearchBook(req *pb.SearchBookRequest, stream pb.BookServic
 		}
 	}
 
-	return err
+	if !req.GetIncludeFacets() {
+		return err
+	}
+
+	facets, err := bs.Store.BookFacets(stream.Context(), books)
+	if err != nil {
+		return status.Error(bookErrors.code(err), err.Error())
+	}
+
+	return stream.Send(&pb.SearchBookResponse{Facets: facets})
+}
+
+func (bs *BookServer) ListTags(ctx context.Context, _ *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	tags, err := bs.Store.ListTags(ctx)
+	if err != nil {
+		return nil, status.Error(bookErrors.code(err), err.Error())
+	}
+
+	return &pb.ListTagsResponse{Tags: tags}, nil
+}
+
+func (bs *BookServer) BatchCreateBooks(ctx context.Context, req *pb.BatchCreateBooksRequest) (*pb.BatchCreateBooksResponse, error) {
+	books := req.GetBooks()
+
+	errs, err := bs.Store.BatchCreateBooks(ctx, books, req.GetAtomic())
+	if err != nil {
+		return nil, batchError(errs, err)
+	}
+
+	res := &pb.BatchCreateBooksResponse{
+		Statuses: batchStatuses(books, errs),
+	}
+
+	return res, err
+}
+
+func (bs *BookServer) BatchUpdateBooks(ctx context.Context, req *pb.BatchUpdateBooksRequest) (*pb.BatchUpdateBooksResponse, error) {
+	requests := req.GetRequests()
+	ids := make([]string, len(requests))
+	newBooks := make([]*pb.Book, len(requests))
+	masks := make([]*fieldmaskpb.FieldMask, len(requests))
+
+	for i, r := range requests {
+		ids[i] = r.GetId()
+		masks[i] = r.GetUpdateMask()
+		newBooks[i] = r.GetBook()
+		if newBooks[i] == nil {
+			newBooks[i] = &pb.Book{}
+		}
+		if newBooks[i].Id == "" {
+			newBooks[i].Id = ids[i]
+		}
+	}
+
+	books, errs, err := bs.Store.BatchUpdateBooks(ctx, ids, newBooks, masks, req.GetAtomic())
+	if err != nil {
+		return nil, batchError(errs, err)
+	}
+
+	res := &pb.BatchUpdateBooksResponse{
+		Statuses: batchStatuses(books, errs),
+	}
+
+	return res, err
+}
+
+func (bs *BookServer) BatchDeleteBooks(ctx context.Context, req *pb.BatchDeleteBooksRequest) (*pb.BatchDeleteBooksResponse, error) {
+	books, errs, err := bs.Store.BatchDeleteBooks(ctx, req.GetIds(), req.GetAtomic())
+	if err != nil {
+		return nil, batchError(errs, err)
+	}
+
+	res := &pb.BatchDeleteBooksResponse{
+		Statuses: batchStatuses(books, errs),
+	}
+
+	return res, err
+}
+
+func batchStatuses(books []*pb.Book, errs []error) []*pb.BatchItemStatus {
+	statuses := make([]*pb.BatchItemStatus, len(errs))
+
+	for i, err := range errs {
+		statuses[i] = &pb.BatchItemStatus{
+			Index: int32(i),
+		}
+
+		if err != nil {
+			statuses[i].Code = int32(bookErrors.code(err))
+			statuses[i].Message = err.Error()
+			continue
+		}
+		statuses[i].Book = books[i]
+	}
+
+	return statuses
+}
+
+// batchError reports the first failed item of an aborted atomic batch.
+func batchError(errs []error, err error) error {
+	if !errors.Is(err, ErrBatchAborted) {
+		return err
+	}
+
+	for i, itemErr := range errs {
+		if itemErr != nil {
+			return status.Errorf(codes.Aborted, "batch aborted: item %d: %v", i, itemErr)
+		}
+	}
+
+	return status.Error(codes.Aborted, err.Error())
+}
+
+func (bs *BookServer) ImportBooks(stream pb.BookService_ImportBooksServer) error {
+	var importer BookImporter
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if importer == nil {
+			importer = bs.Store.ImportBooks(stream.Context(), ImportOptions{
+				ChunkSize: int(req.GetChunkSize()),
+				Upsert:    req.GetUpsert(),
+			})
+		}
+
+		if err := importer.Add(req.GetBook()); err != nil {
+			return err
+		}
+	}
+
+	res := &pb.ImportBooksResponse{}
+	if importer == nil {
+		return stream.SendAndClose(res)
+	}
+
+	result, err := importer.Close()
+	if err != nil {
+		return err
+	}
+
+	res.Created = result.Created
+	res.Updated = result.Updated
+	res.Failed = result.Failed
+	for _, importErr := range result.Errors {
+		res.Errors = append(res.Errors, &pb.ImportError{
+			Index:   importErr.Index,
+			BookId:  importErr.BookID,
+			Message: importErr.Err.Error(),
+		})
+	}
+
+	return stream.SendAndClose(res)
 }
@@ -11,24 +11,23 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func startTestServer(t *testing.T) (*grpc.Server, *bufconn.Listener) {
-	// init DB
-	db := initTestDB(t)
-	store := service.NewPostgresStore(db)
-	server := service.NewBookServer(store)
-
+// startTestServer serves the services register adds on an in-memory
+// listener.
+func startTestServer(t *testing.T, register func(*grpc.Server), opts ...grpc.ServerOption) (*grpc.Server, *bufconn.Listener) {
 	// create listener with buffer
 	listener := bufconn.Listen(1024 * 1024)
 
 	// create grpc server
-	s := grpc.NewServer()
-	pb.RegisterBookServiceServer(s, server)
+	s := grpc.NewServer(opts...)
+	register(s)
 
 	serverErr := make(chan error)
 
@@ -50,32 +49,51 @@ func startTestServer(t *testing.T) (*grpc.Server, *bufconn.Listener) {
 	}
 }
 
+// dialTestServer starts a test server with the services register adds and
+// connects to it. The server and the connection are closed when the test
+// ends.
+func dialTestServer(t *testing.T, register func(*grpc.Server), opts ...grpc.ServerOption) *grpc.ClientConn {
+	server, listener := startTestServer(t, register, opts...)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		t.Fatalf("Could not dial server %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return conn
+}
+
 type testClient struct {
 	client pb.BookServiceClient
-	server *grpc.Server
 	conn   *grpc.ClientConn
 }
 
 func (tc *testClient) Close() {
-	tc.server.Stop()
 	tc.conn.Close()
 }
 
 func initClient(t *testing.T) *testClient {
-	server, listener := startTestServer(t)
+	// init DB
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
 
-	conn, err := grpc.NewClient(
-		"passthrough:///bufnet",
-		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
-			return listener.DialContext(ctx)
-		}),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	assert.NoError(t, err)
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterBookServiceServer(s, service.NewBookServer(store))
+	})
 
 	return &testClient{
 		client: pb.NewBookServiceClient(conn),
-		server: server,
 		conn:   conn,
 	}
 }
@@ -191,7 +209,12 @@ func TestDeleteBook_server(t *testing.T) {
 		assert.Equal(t, book.Price, deletedBook.Price)
 
 		_, err = client.ReadBook(ctx, &pb.ReadBookRequest{Id: res.GetId()})
-		assert.Error(t, err)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := client.DeleteBook(ctx, &pb.DeleteBookRequest{Id: res.GetId()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
 	})
 }
 
@@ -252,3 +275,283 @@ func TestSearchAndGetAllBooks_server(t *testing.T) {
 		assert.Equal(t, 3, i)
 	})
 }
+
+func TestBatchBooks_server(t *testing.T) {
+	ctx := context.Background()
+	clientSTRUCT := initClient(t)
+	defer clientSTRUCT.Close()
+
+	client := clientSTRUCT.client
+
+	books := []*pb.Book{
+		{Id: "1", Author: "case1", Title: "abc", Price: 130},
+		{Id: "2", Author: "case1", Title: "def", Price: 23},
+		{Id: "1", Author: "case1", Title: "duplicate", Price: 138},
+	}
+
+	t.Run("Atomic Batch Fails", func(t *testing.T) {
+		_, err := client.BatchCreateBooks(ctx, &pb.BatchCreateBooksRequest{Books: books, Atomic: true})
+		status, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.Aborted, status.Code())
+	})
+
+	t.Run("Partial Batch Statuses", func(t *testing.T) {
+		res, err := client.BatchCreateBooks(ctx, &pb.BatchCreateBooksRequest{Books: books})
+		assert.NoError(t, err)
+
+		statuses := res.GetStatuses()
+		assert.Len(t, statuses, 3)
+		assert.Equal(t, int32(codes.OK), statuses[0].GetCode())
+		assert.Equal(t, "1", statuses[0].GetBook().GetId())
+		assert.Equal(t, int32(codes.OK), statuses[1].GetCode())
+		assert.NotEqual(t, int32(codes.OK), statuses[2].GetCode())
+		assert.Equal(t, int32(2), statuses[2].GetIndex())
+	})
+
+	t.Run("Batch Update And Delete", func(t *testing.T) {
+		resUpdate, err := client.BatchUpdateBooks(ctx, &pb.BatchUpdateBooksRequest{
+			Requests: []*pb.UpdateBookRequest{
+				{Id: "1", Book: &pb.Book{Author: "case2", Title: "abc", Price: 150}},
+				{Id: "3", Book: &pb.Book{Author: "case2", Title: "xyz", Price: 150}},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "case2", resUpdate.GetStatuses()[0].GetBook().GetAuthor())
+		assert.Equal(t, int32(codes.NotFound), resUpdate.GetStatuses()[1].GetCode())
+
+		resDelete, err := client.BatchDeleteBooks(ctx, &pb.BatchDeleteBooksRequest{Ids: []string{"1", "2"}, Atomic: true})
+		assert.NoError(t, err)
+		assert.Len(t, resDelete.GetStatuses(), 2)
+	})
+}
+
+func TestImportBooks_server(t *testing.T) {
+	ctx := context.Background()
+	clientSTRUCT := initClient(t)
+	defer clientSTRUCT.Close()
+
+	client := clientSTRUCT.client
+
+	stream, err := client.ImportBooks(ctx)
+	assert.NoError(t, err)
+
+	books := []*pb.Book{
+		{Id: "1", Author: "case1", Title: "abc", Price: 130},
+		{Id: "2", Author: "case1", Title: "def", Price: 23},
+		{Id: "1", Author: "case1", Title: "abc v2", Price: 138},
+	}
+	for i, book := range books {
+		req := &pb.ImportBooksRequest{Book: book}
+		if i == 0 {
+			req.ChunkSize = 2
+			req.Upsert = true
+		}
+		assert.NoError(t, stream.Send(req))
+	}
+
+	res, err := stream.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.GetCreated())
+	assert.Equal(t, int64(1), res.GetUpdated())
+	assert.Equal(t, int64(0), res.GetFailed())
+
+	resRead, err := client.ReadBook(ctx, &pb.ReadBookRequest{Id: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "abc v2", resRead.GetBook().GetTitle())
+}
+
+func TestBookSession_server(t *testing.T) {
+	ctx := context.Background()
+	clientSTRUCT := initClient(t)
+	defer clientSTRUCT.Close()
+
+	client := clientSTRUCT.client
+
+	stream, err := client.BookSession(ctx)
+	assert.NoError(t, err)
+
+	book := &pb.Book{Id: t.Name(), Author: "case1", Title: "abc", Price: 130}
+	commands := []*pb.BookSessionRequest{
+		{Tag: "create", Command: &pb.BookSessionRequest_Create{Create: &pb.CreateBookRequest{Book: book}}},
+		{Tag: "read missing", Command: &pb.BookSessionRequest_Read{Read: &pb.ReadBookRequest{Id: "missing"}}},
+		{Tag: "update", Command: &pb.BookSessionRequest_Update{Update: &pb.UpdateBookRequest{
+			Id:   book.Id,
+			Book: &pb.Book{Id: book.Id, Author: "case2", Title: "abc", Price: 150},
+		}}},
+		{Tag: "empty"},
+		{Tag: "read", Command: &pb.BookSessionRequest_Read{Read: &pb.ReadBookRequest{Id: book.Id}}},
+		{Tag: "delete", Command: &pb.BookSessionRequest_Delete{Delete: &pb.DeleteBookRequest{Id: book.Id}}},
+	}
+
+	for _, cmd := range commands {
+		assert.NoError(t, stream.Send(cmd))
+	}
+	assert.NoError(t, stream.CloseSend())
+
+	var responses []*pb.BookSessionResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		responses = append(responses, res)
+	}
+
+	if assert.Len(t, responses, len(commands)) {
+		for i, res := range responses {
+			assert.Equal(t, commands[i].GetTag(), res.GetTag())
+		}
+
+		assert.Equal(t, book.Id, responses[0].GetCreate().GetId())
+		assert.Equal(t, int32(codes.NotFound), responses[1].GetCode())
+		assert.Equal(t, int32(codes.InvalidArgument), responses[3].GetCode())
+		assert.Equal(t, "case2", responses[4].GetRead().GetBook().GetAuthor())
+		assert.Equal(t, int32(codes.OK), responses[5].GetCode())
+	}
+}
+
+func TestWatchBooks_server(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clientSTRUCT := initClient(t)
+	defer clientSTRUCT.Close()
+
+	client := clientSTRUCT.client
+
+	first, err := client.WatchBooks(ctx, &pb.WatchBooksRequest{})
+	assert.NoError(t, err)
+
+	for _, book := range []*pb.Book{
+		{Id: "1", Author: "case1", Title: "abc", Price: 130},
+		{Id: "2", Author: "case1", Title: "def", Price: 23},
+		{Id: "3", Author: "otherCase", Title: "xyz", Price: 138},
+	} {
+		_, err := client.CreateBook(ctx, &pb.CreateBookRequest{Book: book})
+		assert.NoError(t, err)
+	}
+
+	res, err := first.Recv()
+	assert.NoError(t, err)
+	epoch := res.GetEvent().GetEpoch()
+	assert.NotEmpty(t, epoch)
+
+	stream, err := client.WatchBooks(ctx, &pb.WatchBooksRequest{
+		Filter:        &pb.Filter{Author: "case1"},
+		SinceSequence: 1,
+		SinceEpoch:    epoch,
+	})
+	assert.NoError(t, err)
+
+	res, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), res.GetEvent().GetSequence())
+	assert.Equal(t, pb.BookEvent_CREATED, res.GetEvent().GetType())
+
+	_, err = client.UpdateBook(ctx, &pb.UpdateBookRequest{
+		Id:   "1",
+		Book: &pb.Book{Id: "1", Author: "case1", Title: "abc", Price: 150},
+	})
+	assert.NoError(t, err)
+	_, err = client.DeleteBook(ctx, &pb.DeleteBookRequest{Id: "3"})
+	assert.NoError(t, err)
+	_, err = client.DeleteBook(ctx, &pb.DeleteBookRequest{Id: "2"})
+	assert.NoError(t, err)
+
+	res, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BookEvent_UPDATED, res.GetEvent().GetType())
+	assert.Equal(t, int32(130), res.GetEvent().GetBefore().GetPrice())
+	assert.Equal(t, int32(150), res.GetEvent().GetAfter().GetPrice())
+
+	res, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BookEvent_DELETED, res.GetEvent().GetType())
+	assert.Equal(t, "2", res.GetEvent().GetBefore().GetId())
+	assert.Equal(t, uint64(6), res.GetEvent().GetSequence())
+
+	staleStream, err := client.WatchBooks(ctx, &pb.WatchBooksRequest{SinceSequence: 100, SinceEpoch: epoch})
+	assert.NoError(t, err)
+	_, err = staleStream.Recv()
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+
+	restartedStream, err := client.WatchBooks(ctx, &pb.WatchBooksRequest{SinceSequence: 1, SinceEpoch: "before-restart"})
+	assert.NoError(t, err)
+	_, err = restartedStream.Recv()
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
+
+func TestRevisions_server(t *testing.T) {
+	ctx := context.Background()
+	clientSTRUCT := initClient(t)
+	defer clientSTRUCT.Close()
+
+	client := clientSTRUCT.client
+
+	book := &pb.Book{Id: "1", Author: "case 1", Title: "first", Price: 100}
+	_, err := client.CreateBook(ctx, &pb.CreateBookRequest{Book: book})
+	assert.NoError(t, err)
+	afterCreate := timestamppb.Now()
+
+	_, err = client.UpdateBook(ctx, &pb.UpdateBookRequest{
+		Id:   "1",
+		Book: &pb.Book{Id: "1", Author: "case 1", Title: "second", Price: 200},
+	})
+	assert.NoError(t, err)
+
+	t.Run("Read As Of", func(t *testing.T) {
+		res, err := client.ReadBook(ctx, &pb.ReadBookRequest{Id: "1", AsOf: afterCreate})
+		assert.NoError(t, err)
+		assert.Equal(t, "first", res.GetBook().GetTitle())
+
+		res, err = client.ReadBook(ctx, &pb.ReadBookRequest{Id: "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "second", res.GetBook().GetTitle())
+	})
+
+	t.Run("Revisions", func(t *testing.T) {
+		list, err := client.ListBookRevisions(ctx, &pb.ListBookRevisionsRequest{Id: "1"})
+		assert.NoError(t, err)
+		assert.Len(t, list.GetRevisions(), 2)
+
+		rev, err := client.GetBookRevision(ctx, &pb.GetBookRevisionRequest{Id: "1", Revision: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "first", rev.GetRevision().GetBook().GetTitle())
+
+		_, err = client.GetBookRevision(ctx, &pb.GetBookRevisionRequest{Id: "1", Revision: 9})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		res, err := client.RollbackBook(ctx, &pb.RollbackBookRequest{Id: "1", Revision: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "first", res.GetBook().GetTitle())
+
+		list, err := client.ListBookRevisions(ctx, &pb.ListBookRevisionsRequest{Id: "1"})
+		assert.NoError(t, err)
+		assert.Len(t, list.GetRevisions(), 3)
+
+		_, err = client.DeleteBook(ctx, &pb.DeleteBookRequest{Id: "1"})
+		assert.NoError(t, err)
+		_, err = client.RollbackBook(ctx, &pb.RollbackBookRequest{Id: "1", Revision: 4})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestInvalidBook_server(t *testing.T) {
+	ctx := context.Background()
+	clientSTRUCT := initClient(t)
+	defer clientSTRUCT.Close()
+
+	client := clientSTRUCT.client
+
+	_, err := client.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: "1", Isbn: "12345"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: "1", Isbn: "0306406152"}})
+	assert.NoError(t, err)
+
+	_, err = client.UpdateBook(ctx, &pb.UpdateBookRequest{Id: "1", Book: &pb.Book{Id: "1", PageCount: -5}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
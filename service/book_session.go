@@ -0,0 +1,88 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"context"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sessionWindow is how many commands BookSession reads ahead of the
+// responses it has sent. When a client stops reading responses, Send
+// blocks, the window fills up and the server stops reading commands, so
+// the backpressure reaches the client through HTTP/2 flow control.
+const sessionWindow = 32
+
+func (bs *BookServer) BookSession(stream pb.BookService_BookSessionServer) error {
+	ctx := stream.Context()
+	commands := make(chan *pb.BookSessionRequest, sessionWindow)
+	recvErr := make(chan error, 1)
+
+	go func() {
+		defer close(commands)
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					recvErr <- err
+				}
+				return
+			}
+
+			select {
+			case commands <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for req := range commands {
+		if err := stream.Send(bs.runCommand(ctx, req)); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case err := <-recvErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+func (bs *BookServer) runCommand(ctx context.Context, req *pb.BookSessionRequest) *pb.BookSessionResponse {
+	res := &pb.BookSessionResponse{Tag: req.GetTag()}
+
+	var err error
+	switch cmd := req.GetCommand().(type) {
+	case *pb.BookSessionRequest_Create:
+		var result *pb.CreateBookResponse
+		result, err = bs.CreateBook(ctx, cmd.Create)
+		res.Result = &pb.BookSessionResponse_Create{Create: result}
+	case *pb.BookSessionRequest_Read:
+		var result *pb.ReadBookResponse
+		result, err = bs.ReadBook(ctx, cmd.Read)
+		res.Result = &pb.BookSessionResponse_Read{Read: result}
+	case *pb.BookSessionRequest_Update:
+		var result *pb.UpdateBookResponse
+		result, err = bs.UpdateBook(ctx, cmd.Update)
+		res.Result = &pb.BookSessionResponse_Update{Update: result}
+	case *pb.BookSessionRequest_Delete:
+		var result *pb.DeleteBookResponse
+		result, err = bs.DeleteBook(ctx, cmd.Delete)
+		res.Result = &pb.BookSessionResponse_Delete{Delete: result}
+	default:
+		err = status.Error(codes.InvalidArgument, "command is required")
+	}
+
+	if err != nil {
+		res.Result = nil
+		res.Code = int32(bookErrors.code(err))
+		res.Message = status.Convert(err).Message()
+	}
+
+	return res
+}
@@ -1,128 +1,632 @@
 package service
 
 import (
+	"bookstoregrpc/events"
+	"bookstoregrpc/model"
+	"bookstoregrpc/money"
 	"bookstoregrpc/pb"
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"gorm.io/gorm"
 )
 
+const tracerName = "bookstoregrpc/service"
+
+var ErrBookIDMismatch = errors.New("Book ID must be similar")
+
 type BookStote interface {
-	GetBook(string) (*pb.Book, error)
-	GetBooks() ([]*pb.Book, error)
-	CreateBook(*pb.Book) (string, error)
-	UpdateBook(string, *pb.Book) (*pb.Book, error)
-	DeleteBook(string) (*pb.Book, error)
-	SearchBook(*pb.Filter) ([]*pb.Book, error)
+	GetBook(context.Context, string) (*pb.Book, error)
+	GetBooks(context.Context) ([]*pb.Book, error)
+	CreateBook(context.Context, *pb.Book) (string, error)
+	UpdateBook(context.Context, string, *pb.Book, *fieldmaskpb.FieldMask) (*pb.Book, error)
+	DeleteBook(context.Context, string) (*pb.Book, error)
+	SearchBook(context.Context, *pb.Filter, pb.SearchBookRequest_Sort) ([]*pb.Book, error)
+	BatchCreateBooks(context.Context, []*pb.Book, bool) ([]error, error)
+	BatchUpdateBooks(context.Context, []string, []*pb.Book, []*fieldmaskpb.FieldMask, bool) ([]*pb.Book, []error, error)
+	BatchDeleteBooks(context.Context, []string, bool) ([]*pb.Book, []error, error)
+	ImportBooks(context.Context, ImportOptions) BookImporter
+	WatchBooks(context.Context, uint64, string) (*events.Subscription, error)
+	GetBookAsOf(context.Context, string, time.Time) (*pb.Book, error)
+	ListBookRevisions(context.Context, string) ([]*pb.BookRevision, error)
+	GetBookRevision(context.Context, string, int64) (*pb.BookRevision, error)
+	RollbackBook(context.Context, string, int64) (*pb.Book, error)
+	BookFacets(context.Context, []*pb.Book) (*pb.Facets, error)
+	ListTags(context.Context) ([]*pb.FacetCount, error)
 }
 
 type PostgresStore struct {
-	mu sync.RWMutex
-	db *gorm.DB
+	mu     sync.RWMutex
+	db     *gorm.DB
+	events *events.Bus
 }
 
 func NewPostgresStore(db *gorm.DB) BookStote {
-	return &PostgresStore{db: db}
+	return &PostgresStore{
+		db:     db,
+		events: events.NewBus(events.DefaultHistorySize),
+	}
 }
 
-func (ps *PostgresStore) GetBook(id string) (*pb.Book, error) {
-	log.Println("GETBOOK receive request")
-	var book *pb.Book
+// startSpan opens a span for a store method. Lock acquisition is recorded
+// as a span event, so the time spent waiting on ps.mu is the gap between
+// the span start and that event.
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "PostgresStore."+method)
+}
 
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (ps *PostgresStore) rlock(span trace.Span) {
 	ps.mu.RLock()
-	err := ps.db.Where("id = ?", id).First(&book).Error
-	ps.mu.RUnlock()
+	span.AddEvent("read lock acquired")
+}
 
-	return book, err
+func (ps *PostgresStore) lock(span trace.Span) {
+	ps.mu.Lock()
+	span.AddEvent("write lock acquired")
+}
+
+func (ps *PostgresStore) GetBook(ctx context.Context, id string) (book *pb.Book, err error) {
+	log.Println("GETBOOK receive request")
+	ctx, span := startSpan(ctx, "GetBook")
+	defer func() { endSpan(span, err) }()
+
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	db := ps.db.WithContext(ctx)
+
+	var row model.Book
+	if err := db.Where("id = ?", id).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	books, err := withBookLinks(db, row.Proto())
+	if err != nil {
+		return nil, err
+	}
+	books, err = withEffectivePrices(db, time.Now(), books...)
+	if err != nil {
+		return nil, err
+	}
+
+	return books[0], nil
 }
 
-func (ps *PostgresStore) GetBooks() ([]*pb.Book, error) {
+func (ps *PostgresStore) GetBooks(ctx context.Context) (books []*pb.Book, err error) {
 	log.Println("GETBOOKS receive request")
-	var books []*pb.Book
+	ctx, span := startSpan(ctx, "GetBooks")
+	defer func() { endSpan(span, err) }()
 
-	ps.mu.RLock()
-	err := ps.db.Find(&books).Error
-	ps.mu.RUnlock()
+	ps.rlock(span)
+	defer ps.mu.RUnlock()
+
+	db := ps.db.WithContext(ctx)
+
+	var rows []*model.Book
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	books, err = withBookLinks(db, model.Protos(rows)...)
+	if err != nil {
+		return nil, err
+	}
 
-	return books, err
+	return withEffectivePrices(db, time.Now(), books...)
 }
 
-func (ps *PostgresStore) CreateBook(book *pb.Book) (string, error) {
+func (ps *PostgresStore) CreateBook(ctx context.Context, book *pb.Book) (id string, err error) {
 	log.Println("CREATEBOOK receive request")
-	ps.mu.Lock()
-	err := ps.db.Create(&book).Error
-	ps.mu.Unlock()
+	ctx, span := startSpan(ctx, "CreateBook")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateBook(book); err != nil {
+		return book.Id, err
+	}
+
+	ps.lock(span)
+	defer ps.mu.Unlock()
+
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model.NewBook(book)).Error; err != nil {
+			return err
+		}
+		if err := setBookLinks(tx, book); err != nil {
+			return err
+		}
+
+		return changes.record(tx, pb.BookEvent_CREATED, nil, book)
+	})
+	if err != nil {
+		return book.Id, err
+	}
+
+	ps.publish(changes)
 
 	return book.Id, err
 }
 
-func (ps *PostgresStore) UpdateBook(id string, newBook *pb.Book) (*pb.Book, error) {
+// UpdateBook changes the fields of the book named by mask, or replaces the
+// whole book if the mask is empty.
+func (ps *PostgresStore) UpdateBook(ctx context.Context, id string, newBook *pb.Book, mask *fieldmaskpb.FieldMask) (book *pb.Book, err error) {
 	log.Println("UPDATEBOOK receive request")
-	ps.mu.Lock()
+	ctx, span := startSpan(ctx, "UpdateBook")
+	defer func() { endSpan(span, err) }()
+
+	ps.lock(span)
 	defer ps.mu.Unlock()
 
-	var book *pb.Book
-	err := ps.db.Where("id = ?", id).First(&book).Error
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before *pb.Book
+		before, book, err = updateBook(tx, id, newBook, mask)
+		if err != nil {
+			return err
+		}
+
+		return changes.record(tx, pb.BookEvent_UPDATED, before, book)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if book.Id != newBook.Id {
-		return nil, errors.New("Book ID must be similar")
-	}
+	ps.publish(changes)
+
+	return book, err
+}
+
+func (ps *PostgresStore) DeleteBook(ctx context.Context, id string) (book *pb.Book, err error) {
+	log.Println("DELETEBOOK receive request")
+	ctx, span := startSpan(ctx, "DeleteBook")
+	defer func() { endSpan(span, err) }()
 
-	book.Author = newBook.Author
-	book.Title = newBook.Title
-	book.Price = newBook.Price
+	ps.lock(span)
+	defer ps.mu.Unlock()
+
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		book, err = deleteBook(tx, id)
+		if err != nil {
+			return err
+		}
 
-	err = ps.db.Model(&pb.Book{}).Where("id = ?", id).Select("Title", "Author", "Price").Updates(book).Error
+		return changes.record(tx, pb.BookEvent_DELETED, book, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	ps.publish(changes)
+
 	return book, err
 }
 
-func (ps *PostgresStore) DeleteBook(id string) (*pb.Book, error) {
-	log.Println("DELETEBOOK receive request")
-	ps.mu.Lock()
-	defer ps.mu.Unlock()
+// updateBook returns the book as it was before the update and after it.
+func updateBook(db *gorm.DB, id string, newBook *pb.Book, mask *fieldmaskpb.FieldMask) (*pb.Book, *pb.Book, error) {
+	var row model.Book
+	err := db.Where("id = ?", id).First(&row).Error
+	if err != nil {
+		return nil, nil, err
+	}
 
-	var book *pb.Book
-	err := ps.db.Where("id = ?", id).First(&book).Error
+	before, err := withBookLinks(db, row.Proto())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !replacesBook(mask) {
+		if newBook, err = applyMask(before[0], newBook, mask); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if row.ID != newBook.Id {
+		return nil, nil, ErrBookIDMismatch
+	}
+
+	// An older client sends the legacy price only. If it did not change,
+	// the stored list price keeps its currency and fraction.
+	if stored := before[0].GetListPrice(); stored != nil && newBook.ListPrice == nil && newBook.Price == before[0].GetPrice() {
+		newBook.ListPrice = proto.Clone(stored).(*pb.Money)
+	}
+
+	if err := validateBook(newBook); err != nil {
+		return nil, nil, err
+	}
+
+	after := model.NewBook(newBook)
+	err = db.Model(&model.Book{}).Where("id = ?", id).Select(bookColumns).Updates(after).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := setBookLinks(db, newBook); err != nil {
+		return nil, nil, err
+	}
+
+	book := after.Proto()
+	book.AuthorIds, book.CategoryIds, book.Tags = newBook.AuthorIds, newBook.CategoryIds, newBook.Tags
+	book.RatingAverage, book.RatingCount = row.RatingAverage, row.RatingCount
+
+	return before[0], book, nil
+}
+
+func deleteBook(db *gorm.DB, id string) (*pb.Book, error) {
+	var row model.Book
+	err := db.Where("id = ?", id).First(&row).Error
 	if err != nil {
 		return nil, err
 	}
 
-	err = ps.db.Unscoped().Where("id = ?", id).Delete(&pb.Book{}).Error
+	books, err := withBookLinks(db, row.Proto())
 	if err != nil {
 		return nil, err
 	}
 
-	return book, err
+	stocked, err := stockedBooks(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if stocked[id] {
+		return nil, ErrBookInStock
+	}
+
+	if err := deleteBookLinks(db, id); err != nil {
+		return nil, err
+	}
+	if err := deleteBookDependents(db, id); err != nil {
+		return nil, err
+	}
+	err = db.Unscoped().Where("id = ?", id).Delete(&model.Book{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return books[0], nil
 }
 
-func (ps *PostgresStore) SearchBook(filter *pb.Filter) ([]*pb.Book, error) {
+func (ps *PostgresStore) SearchBook(ctx context.Context, filter *pb.Filter, sort pb.SearchBookRequest_Sort) (books []*pb.Book, err error) {
 	log.Println("SEARCHBOOK receive request")
-	ps.mu.RLock()
+	ctx, span := startSpan(ctx, "SearchBook")
+	defer func() { endSpan(span, err) }()
+
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	ps.rlock(span)
 	defer ps.mu.RUnlock()
 
-	query := ps.db.Model(&pb.Book{})
+	db := ps.db.WithContext(ctx)
+	query := db.Model(&model.Book{})
 
-	if filter.GetAuthor() != "" {
-		query = query.Where("author = ?", filter.GetAuthor())
+	if author := filter.GetAuthor(); author != "" {
+		query = query.Where("author = ? OR id IN (?)", author, booksBy(db, authorsNamed(db, author)))
+	}
+	if filter.GetAuthorId() != "" {
+		query = query.Where("id IN (?)", booksBy(db, []string{filter.GetAuthorId()}))
+	}
+	if filter.GetCategoryId() != "" {
+		tree, err := loadCategoryTree(db)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("id IN (?)", booksIn(db, tree.descendants(filter.GetCategoryId())))
+	}
+	if filter.GetTag() != "" {
+		query = query.Where("id IN (?)", booksTagged(db, filter.GetTag()))
+	}
+	if filter.GetMinRating() > 0 {
+		query = query.Where("rating_average >= ?", filter.GetMinRating())
+	}
+
+	switch sort {
+	case pb.SearchBookRequest_RATING_DESC:
+		query = query.Order("rating_average DESC, rating_count DESC, id")
+	case pb.SearchBookRequest_RATING_ASC:
+		query = query.Order("rating_average, rating_count, id")
 	}
 	if filter.GetPrice() > 0 {
-		query = query.Where("price > ?", filter.GetPrice())
+		query = query.Where("price_currency = ? AND price_amount > ?", money.DefaultCurrency, filter.GetPrice())
+	}
+	if minPrice := filter.GetMinPrice(); minPrice != nil {
+		query = query.Where("price_currency = ? AND price_amount >= ?", minPrice.CurrencyCode, money.AmountOf(minPrice))
+	}
+	if maxPrice := filter.GetMaxPrice(); maxPrice != nil {
+		query = query.Where("price_currency = ? AND price_amount <= ?", maxPrice.CurrencyCode, money.AmountOf(maxPrice))
+	}
+	if filter.GetIsbn() != "" {
+		query = query.Where("isbn = ?", normalizeISBN(filter.GetIsbn()))
+	}
+	if filter.GetPublisher() != "" {
+		query = query.Where("publisher = ?", filter.GetPublisher())
+	}
+	if filter.GetPublicationYear() > 0 {
+		query = query.Where("publication_year = ?", filter.GetPublicationYear())
+	}
+	if filter.GetLanguage() != "" {
+		query = query.Where("language = ?", strings.ToLower(filter.GetLanguage()))
 	}
 
-	var books []*pb.Book
-	if err := query.Find(&books).Error; err != nil {
+	var rows []*model.Book
+	if err := query.Find(&rows).Error; err != nil {
 		return nil, fmt.Errorf("failed to search books: %w", err)
 	}
 
-	return books, nil
+	books, err = withBookLinks(db, model.Protos(rows)...)
+	if err != nil {
+		return nil, err
+	}
+	books, err = withEffectivePrices(db, time.Now(), books...)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterEffectivePrice(books, filter), nil
+}
+
+// batchSize is the number of rows sent per INSERT by BatchCreateBooks.
+const batchSize = 100
+
+// ErrBatchAborted is returned by the batch methods in atomic mode when at
+// least one item failed and the whole batch was rolled back. The per-item
+// errors still say which items failed.
+var ErrBatchAborted = errors.New("batch aborted")
+
+// ErrDuplicateID is the item error of a repeated id in BatchDeleteBooks;
+// the first occurrence is deleted as usual.
+var ErrDuplicateID = errors.New("id is repeated in the batch")
+
+// BatchCreateBooks inserts books with bulk INSERTs under a single lock.
+// If the bulk insert fails, the books are retried one by one in savepoints
+// to find the failing ones: in atomic mode nothing is committed, otherwise
+// the valid books are kept. errs[i] is the error for books[i].
+func (ps *PostgresStore) BatchCreateBooks(ctx context.Context, books []*pb.Book, atomic bool) (errs []error, err error) {
+	log.Println("BATCHCREATEBOOKS receive request")
+	ctx, span := startSpan(ctx, "BatchCreateBooks")
+	defer func() { endSpan(span, err) }()
+
+	ps.lock(span)
+	defer ps.mu.Unlock()
+
+	errs = make([]error, len(books))
+	valid := make([]*pb.Book, 0, len(books))
+	for i, book := range books {
+		errs[i] = validateBook(book)
+		if errs[i] == nil {
+			valid = append(valid, book)
+		}
+	}
+	if err := batchResult(errs, atomic); err != nil {
+		return errs, err
+	}
+
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		bulkErr := tx.Transaction(func(tx *gorm.DB) error {
+			if len(valid) == 0 {
+				return nil
+			}
+			if err := tx.CreateInBatches(model.NewBooks(valid), batchSize).Error; err != nil {
+				return err
+			}
+			for _, book := range valid {
+				if err := setBookLinks(tx, book); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if bulkErr != nil {
+			for i, book := range books {
+				if errs[i] != nil {
+					continue
+				}
+				errs[i] = tx.Transaction(func(tx *gorm.DB) error {
+					if err := tx.Create(model.NewBook(book)).Error; err != nil {
+						return err
+					}
+					return setBookLinks(tx, book)
+				})
+			}
+		}
+
+		if err := batchResult(errs, atomic); err != nil {
+			return err
+		}
+
+		for i, book := range books {
+			if errs[i] != nil {
+				continue
+			}
+			if err := changes.record(tx, pb.BookEvent_CREATED, nil, book); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errs, err
+	}
+
+	ps.publish(changes)
+
+	return errs, err
+}
+
+// BatchUpdateBooks applies UpdateBook(ids[i], newBooks[i], masks[i]) for
+// every i in one transaction. With nil masks every book is replaced.
+func (ps *PostgresStore) BatchUpdateBooks(ctx context.Context, ids []string, newBooks []*pb.Book, masks []*fieldmaskpb.FieldMask, atomic bool) (books []*pb.Book, errs []error, err error) {
+	log.Println("BATCHUPDATEBOOKS receive request")
+	ctx, span := startSpan(ctx, "BatchUpdateBooks")
+	defer func() { endSpan(span, err) }()
+
+	ps.lock(span)
+	defer ps.mu.Unlock()
+
+	books = make([]*pb.Book, len(newBooks))
+	errs = make([]error, len(newBooks))
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before := make([]*pb.Book, len(newBooks))
+		for i, newBook := range newBooks {
+			errs[i] = tx.Transaction(func(tx *gorm.DB) error {
+				var mask *fieldmaskpb.FieldMask
+				if masks != nil {
+					mask = masks[i]
+				}
+				var err error
+				before[i], books[i], err = updateBook(tx, ids[i], newBook, mask)
+				return err
+			})
+		}
+
+		if err := batchResult(errs, atomic); err != nil {
+			return err
+		}
+
+		for i, book := range books {
+			if errs[i] != nil {
+				continue
+			}
+			if err := changes.record(tx, pb.BookEvent_UPDATED, before[i], book); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return books, errs, err
+	}
+
+	ps.publish(changes)
+
+	return books, errs, err
+}
+
+// BatchDeleteBooks deletes the books with a single DELETE ... WHERE id IN.
+// Unknown ids fail with gorm.ErrRecordNotFound.
+func (ps *PostgresStore) BatchDeleteBooks(ctx context.Context, ids []string, atomic bool) (books []*pb.Book, errs []error, err error) {
+	log.Println("BATCHDELETEBOOKS receive request")
+	ctx, span := startSpan(ctx, "BatchDeleteBooks")
+	defer func() { endSpan(span, err) }()
+
+	ps.lock(span)
+	defer ps.mu.Unlock()
+
+	books = make([]*pb.Book, len(ids))
+	errs = make([]error, len(ids))
+	var changes changeSet
+	err = ps.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var found []*model.Book
+		if err := tx.Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return err
+		}
+
+		foundBooks, err := withBookLinks(tx, model.Protos(found)...)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[string]*pb.Book, len(foundBooks))
+		for _, book := range foundBooks {
+			byID[book.Id] = book
+		}
+
+		stocked, err := stockedBooks(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		var existing []string
+		seen := make(map[string]bool, len(ids))
+		for i, id := range ids {
+			if seen[id] {
+				errs[i] = ErrDuplicateID
+				continue
+			}
+			seen[id] = true
+
+			book, ok := byID[id]
+			if !ok {
+				errs[i] = gorm.ErrRecordNotFound
+				continue
+			}
+			if stocked[id] {
+				errs[i] = ErrBookInStock
+				continue
+			}
+			books[i] = book
+			existing = append(existing, id)
+		}
+
+		if err := batchResult(errs, atomic); err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			return nil
+		}
+
+		if err := deleteBookLinks(tx, existing...); err != nil {
+			return err
+		}
+		if err := deleteBookDependents(tx, existing...); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("id IN ?", existing).Delete(&model.Book{}).Error; err != nil {
+			return err
+		}
+
+		for i, book := range books {
+			if errs[i] != nil {
+				continue
+			}
+			if err := changes.record(tx, pb.BookEvent_DELETED, book, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return books, errs, err
+	}
+
+	ps.publish(changes)
+
+	return books, errs, err
+}
+
+func batchResult(errs []error, atomic bool) error {
+	if !atomic {
+		return nil
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return ErrBatchAborted
+		}
+	}
+
+	return nil
 }
@@ -1,37 +1,38 @@
 package service_test
 
 import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/database"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/money"
+	"bookstoregrpc/order"
+	"bookstoregrpc/outbox"
 	"bookstoregrpc/pb"
+	"bookstoregrpc/review"
 	"bookstoregrpc/service"
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/sqlite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"gorm.io/gorm"
 )
 
 func initTestDB(t *testing.T) *gorm.DB {
-	dbname := "file:testdb_" + t.Name() + "?mode=memory&cache=private"
-	db, err := gorm.Open(sqlite.Open(dbname), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("failed to connect database: %v", err)
-	}
-
-	if err := db.AutoMigrate(&pb.Book{}); err != nil {
-		t.Fatalf("failed to migrate: %v", err)
-	}
-
-	t.Cleanup(func() {
-		sqlDB, _ := db.DB()
-		sqlDB.Close()
-	})
-
-	return db
+	return dbtest.New(t).DB
 }
 
 func TestCreateAndReadBook_store(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	db := initTestDB(t)
 	store := service.NewPostgresStore(db)
 
@@ -42,18 +43,18 @@ func TestCreateAndReadBook_store(t *testing.T) {
 		Price:  123,
 	}
 
-	id, err := store.CreateBook(book)
+	id, err := store.CreateBook(ctx, book)
 	assert.NoError(t, err)
 	assert.Equal(t, book.Id, id)
 
 	t.Run("Success Get Book", func(t *testing.T) {
-		get, err := store.GetBook(id)
+		get, err := store.GetBook(ctx, id)
 		assert.NoError(t, err)
 		assert.Equal(t, book.Title, get.Title)
 	})
 
 	t.Run("Failed Get Book", func(t *testing.T) {
-		get, err := store.GetBook("wrong")
+		get, err := store.GetBook(ctx, "wrong")
 		assert.Error(t, err)
 		assert.Empty(t, get)
 	})
@@ -61,6 +62,7 @@ func TestCreateAndReadBook_store(t *testing.T) {
 
 func TestUpdateBook_store(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	db := initTestDB(t)
 	store := service.NewPostgresStore(db)
 
@@ -84,19 +86,21 @@ func TestUpdateBook_store(t *testing.T) {
 		Price:  321,
 	}
 
-	id, err := store.CreateBook(book)
+	id, err := store.CreateBook(ctx, book)
 	assert.NoError(t, err)
 
 	t.Run("Success Update", func(t *testing.T) {
-		_, err := store.UpdateBook(id, NewBook_Success)
+		_, err := store.UpdateBook(ctx, id, NewBook_Success, nil)
 		assert.NoError(t, err)
-		alterBook, err := store.GetBook(id)
+		alterBook, err := store.GetBook(ctx, id)
 		assert.NoError(t, err)
+		// Without price rules the effective price is the list price.
+		NewBook_Success.EffectivePrice = NewBook_Success.ListPrice
 		assert.Equal(t, NewBook_Success, alterBook)
 	})
 
 	t.Run("Failed Update", func(t *testing.T) {
-		alterBook, err := store.UpdateBook(id, NewBook_Failed)
+		alterBook, err := store.UpdateBook(ctx, id, NewBook_Failed, nil)
 		assert.Empty(t, alterBook)
 		assert.Equal(t, errors.New("Book ID must be similar"), err)
 	})
@@ -105,6 +109,7 @@ func TestUpdateBook_store(t *testing.T) {
 
 func TestDeleteBook_store(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	db := initTestDB(t)
 	store := service.NewPostgresStore(db)
 
@@ -115,23 +120,73 @@ func TestDeleteBook_store(t *testing.T) {
 		Price:  123,
 	}
 
-	id, err := store.CreateBook(book)
+	id, err := store.CreateBook(ctx, book)
 	assert.NoError(t, err)
 
 	t.Run("Success Delete", func(t *testing.T) {
-		deletedBook, err := store.DeleteBook(id)
+		deletedBook, err := store.DeleteBook(ctx, id)
 		assert.NoError(t, err)
 		assert.Equal(t, book, deletedBook)
 	})
 	t.Run("Failed Delete", func(t *testing.T) {
-		deletedBook, err := store.DeleteBook(id)
+		deletedBook, err := store.DeleteBook(ctx, id)
 		assert.Error(t, err)
 		assert.Empty(t, deletedBook)
 	})
 }
 
+func TestDeleteBook_dependents(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+	stock := inventory.NewStore(db)
+	orders := order.NewStore(db)
+
+	_, err := store.CreateBook(ctx, &pb.Book{Id: "oblomov", Title: "Обломов", Price: 500})
+	assert.NoError(t, err)
+	_, err = stock.Adjust(ctx, "oblomov", "", 2)
+	assert.NoError(t, err)
+
+	alice := audit.NewContext(ctx, audit.Info{Principal: "alice"})
+	_, err = review.NewStore(db, nil).Create(alice, "oblomov", &pb.Review{Rating: 5})
+	assert.NoError(t, err)
+	cart, err := orders.CreateCart(ctx)
+	assert.NoError(t, err)
+	_, err = orders.SetItem(ctx, cart.Id, "oblomov", 1)
+	assert.NoError(t, err)
+
+	t.Run("In Stock", func(t *testing.T) {
+		_, err := store.DeleteBook(ctx, "oblomov")
+		assert.ErrorIs(t, err, service.ErrBookInStock)
+
+		_, errs, err := store.BatchDeleteBooks(ctx, []string{"oblomov"}, false)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, errs[0], service.ErrBookInStock)
+	})
+
+	t.Run("Out Of Stock", func(t *testing.T) {
+		_, err := stock.Adjust(ctx, "oblomov", "", -2)
+		assert.NoError(t, err)
+
+		_, err = store.DeleteBook(ctx, "oblomov")
+		assert.NoError(t, err)
+
+		for _, table := range []any{&inventory.Stock{}, &review.Review{}, &order.CartItem{}} {
+			var count int64
+			assert.NoError(t, db.Model(table).Where("book_id = ?", "oblomov").Count(&count).Error)
+			assert.Zero(t, count)
+		}
+
+		// The cart lost the deleted book instead of failing to be placed.
+		_, err = orders.Place(ctx, cart.Id)
+		assert.ErrorIs(t, err, order.ErrEmptyCart)
+	})
+}
+
 func TestSearchAndGetAllBooks_store(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	db := initTestDB(t)
 	store := service.NewPostgresStore(db)
 
@@ -142,12 +197,12 @@ func TestSearchAndGetAllBooks_store(t *testing.T) {
 	}
 
 	for _, book := range books {
-		_, err := store.CreateBook(book)
+		_, err := store.CreateBook(ctx, book)
 		assert.NoError(t, err)
 	}
 
 	t.Run("Get All Books", func(t *testing.T) {
-		books, err := store.GetBooks()
+		books, err := store.GetBooks(ctx)
 		assert.NoError(t, err)
 		assert.Len(t, books, 3)
 	})
@@ -158,7 +213,7 @@ func TestSearchAndGetAllBooks_store(t *testing.T) {
 			Price:  123,
 		}
 
-		books, err := store.SearchBook(filter)
+		books, err := store.SearchBook(ctx, filter, pb.SearchBookRequest_SORT_UNSPECIFIED)
 		assert.NoError(t, err)
 		assert.Len(t, books, 1)
 	})
@@ -169,8 +224,509 @@ func TestSearchAndGetAllBooks_store(t *testing.T) {
 			Price:  300,
 		}
 
-		books, err := store.SearchBook(filter)
+		books, err := store.SearchBook(ctx, filter, pb.SearchBookRequest_SORT_UNSPECIFIED)
 		assert.NoError(t, err)
 		assert.Empty(t, books)
 	})
 }
+
+func TestTracing_store(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	_, err := store.SearchBook(ctx, &pb.Filter{Author: "traced"}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	var storeSpan, sqlSpan sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		switch {
+		case span.Name() == "PostgresStore.SearchBook":
+			storeSpan = span
+		case span.SpanKind() == trace.SpanKindClient:
+			sqlSpan = span
+		}
+	}
+
+	if assert.NotNil(t, storeSpan) && assert.NotNil(t, sqlSpan) {
+		assert.Equal(t, storeSpan.SpanContext().SpanID(), sqlSpan.Parent().SpanID())
+		assert.Equal(t, "read lock acquired", storeSpan.Events()[0].Name)
+	}
+}
+
+func TestBatchBooks_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	_, err := store.CreateBook(ctx, &pb.Book{Id: "existing", Author: "case 0", Title: "test 0", Price: 100})
+	assert.NoError(t, err)
+
+	t.Run("Atomic Create Rolls Back", func(t *testing.T) {
+		books := []*pb.Book{
+			{Id: "atomic_1", Author: "case 1", Title: "test 1", Price: 123},
+			{Id: "existing", Author: "case 1", Title: "duplicate", Price: 123},
+		}
+
+		errs, err := store.BatchCreateBooks(ctx, books, true)
+		assert.ErrorIs(t, err, service.ErrBatchAborted)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+
+		_, err = store.GetBook(ctx, "atomic_1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Partial Create", func(t *testing.T) {
+		books := []*pb.Book{
+			{Id: "partial_1", Author: "case 1", Title: "test 1", Price: 123},
+			{Id: "existing", Author: "case 1", Title: "duplicate", Price: 123},
+			{Id: "partial_2", Author: "case 2", Title: "test 2", Price: 234},
+		}
+
+		errs, err := store.BatchCreateBooks(ctx, books, false)
+		assert.NoError(t, err)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+		assert.NoError(t, errs[2])
+
+		all, err := store.GetBooks(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("Partial Update", func(t *testing.T) {
+		ids := []string{"partial_1", "missing", "partial_2"}
+		newBooks := []*pb.Book{
+			{Id: "partial_1", Author: "case 1", Title: "new 1", Price: 321},
+			{Id: "missing", Author: "case 1", Title: "new", Price: 321},
+			{Id: "wrong", Author: "case 2", Title: "new 2", Price: 432},
+		}
+
+		books, errs, err := store.BatchUpdateBooks(ctx, ids, newBooks, nil, false)
+		assert.NoError(t, err)
+		assert.NoError(t, errs[0])
+		assert.Equal(t, "new 1", books[0].Title)
+		assert.ErrorIs(t, errs[1], gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, errs[2], service.ErrBookIDMismatch)
+	})
+
+	t.Run("Atomic Delete Rolls Back", func(t *testing.T) {
+		_, errs, err := store.BatchDeleteBooks(ctx, []string{"partial_1", "missing"}, true)
+		assert.ErrorIs(t, err, service.ErrBatchAborted)
+		assert.ErrorIs(t, errs[1], gorm.ErrRecordNotFound)
+
+		_, err = store.GetBook(ctx, "partial_1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Duplicate Delete", func(t *testing.T) {
+		_, errs, err := store.BatchDeleteBooks(ctx, []string{"partial_1", "partial_1"}, true)
+		assert.ErrorIs(t, err, service.ErrBatchAborted)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, errs[1], service.ErrDuplicateID)
+
+		_, err = store.GetBook(ctx, "partial_1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Partial Delete", func(t *testing.T) {
+		books, errs, err := store.BatchDeleteBooks(ctx, []string{"partial_1", "missing", "partial_2"}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "new 1", books[0].Title)
+		assert.Error(t, errs[1])
+		assert.Equal(t, "partial_2", books[2].Id)
+
+		all, err := store.GetBooks(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, all, 1)
+	})
+}
+
+func TestImportBooks_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	books := []*pb.Book{
+		{Id: "1", Author: "case 1", Title: "test 1", Price: 100},
+		{Id: "2", Author: "case 1", Title: "test 2", Price: 200},
+		{Id: "1", Author: "case 1", Title: "duplicate", Price: 300},
+		{Author: "case 1", Title: "no id", Price: 400},
+		{Id: "3", Author: "case 3", Title: "test 3", Price: 500},
+	}
+
+	t.Run("Insert", func(t *testing.T) {
+		importer := store.ImportBooks(ctx, service.ImportOptions{ChunkSize: 2})
+		for _, book := range books {
+			assert.NoError(t, importer.Add(book))
+		}
+
+		result, err := importer.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Created)
+		assert.Equal(t, int64(0), result.Updated)
+		assert.Equal(t, int64(2), result.Failed)
+		assert.Equal(t, int64(2), result.Errors[0].Index)
+		assert.Equal(t, int64(3), result.Errors[1].Index)
+
+		book, err := store.GetBook(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "test 1", book.Title)
+
+		_, err = importer.Close()
+		assert.ErrorIs(t, err, service.ErrImportClosed)
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		importer := store.ImportBooks(ctx, service.ImportOptions{ChunkSize: 10, Upsert: true})
+		assert.NoError(t, importer.Add(&pb.Book{Id: "1", Author: "case 1", Title: "new 1", Price: 111}))
+		assert.NoError(t, importer.Add(&pb.Book{Id: "4", Author: "case 4", Title: "test 4", Price: 444}))
+
+		result, err := importer.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Created)
+		assert.Equal(t, int64(1), result.Updated)
+		assert.Equal(t, int64(0), result.Failed)
+
+		book, err := store.GetBook(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "new 1", book.Title)
+	})
+}
+
+func TestImportBooks_rollback(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	_, err := store.CreateBook(ctx, &pb.Book{Id: "1", Author: "case 1", Title: "test 1", Price: 100})
+	assert.NoError(t, err)
+	// Recording the change of "2" fails, so the chunk is rolled back.
+	assert.NoError(t, db.Migrator().DropTable("audit_events"))
+
+	importer := store.ImportBooks(ctx, service.ImportOptions{ChunkSize: 2})
+	assert.NoError(t, importer.Add(&pb.Book{Id: "1", Author: "case 1", Title: "duplicate", Price: 100}))
+	assert.Error(t, importer.Add(&pb.Book{Id: "2", Author: "case 2", Title: "test 2", Price: 200}))
+
+	result, err := importer.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), result.Created)
+	assert.Equal(t, int64(0), result.Failed)
+	assert.Empty(t, result.Errors)
+}
+
+func TestOutbox_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	countMessages := func() int64 {
+		var count int64
+		assert.NoError(t, db.Model(&outbox.Message{}).Count(&count).Error)
+		return count
+	}
+
+	book := &pb.Book{Id: "1", Author: "case 1", Title: "test 1", Price: 100}
+	_, err := store.CreateBook(ctx, book)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), countMessages())
+
+	t.Run("Rolled back changes", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "1", Title: "duplicate"})
+		assert.Error(t, err)
+
+		_, err = store.BatchCreateBooks(ctx, []*pb.Book{{Id: "2"}, {Id: "1"}}, true)
+		assert.ErrorIs(t, err, service.ErrBatchAborted)
+
+		_, err = store.UpdateBook(ctx, "missing", &pb.Book{Id: "missing"}, nil)
+		assert.Error(t, err)
+
+		assert.Equal(t, int64(1), countMessages())
+	})
+
+	t.Run("Committed changes", func(t *testing.T) {
+		_, err := store.UpdateBook(ctx, "1", &pb.Book{Id: "1", Author: "case 1", Title: "new 1", Price: 150}, nil)
+		assert.NoError(t, err)
+		_, err = store.DeleteBook(ctx, "1")
+		assert.NoError(t, err)
+
+		var messages []outbox.Message
+		assert.NoError(t, db.Order("id").Find(&messages).Error)
+		if assert.Len(t, messages, 3) {
+			event, err := messages[1].Event()
+			assert.NoError(t, err)
+			assert.Equal(t, pb.BookEvent_UPDATED, event.Type)
+			assert.Equal(t, "test 1", event.Before.Title)
+			assert.Equal(t, "new 1", event.After.Title)
+			assert.Equal(t, pb.BookEvent_DELETED.String(), messages[2].Type)
+			assert.Equal(t, "1", messages[2].BookID)
+		}
+	})
+}
+
+func TestRevisions_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	book := &pb.Book{Id: "1", Author: "case 1", Title: "first", Price: 100}
+	_, err := store.CreateBook(ctx, book)
+	assert.NoError(t, err)
+	afterCreate := time.Now()
+
+	_, err = store.UpdateBook(ctx, "1", &pb.Book{Id: "1", Author: "case 1", Title: "second", Price: 200}, nil)
+	assert.NoError(t, err)
+	afterUpdate := time.Now()
+
+	_, err = store.DeleteBook(ctx, "1")
+	assert.NoError(t, err)
+
+	t.Run("List and Get", func(t *testing.T) {
+		revisions, err := store.ListBookRevisions(ctx, "1")
+		assert.NoError(t, err)
+		if assert.Len(t, revisions, 3) {
+			assert.Equal(t, int64(1), revisions[0].Revision)
+			assert.Equal(t, "first", revisions[0].Book.Title)
+			assert.Equal(t, pb.BookEvent_DELETED, revisions[2].Type)
+			assert.Nil(t, revisions[2].Book)
+		}
+
+		rev, err := store.GetBookRevision(ctx, "1", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", rev.Book.Title)
+
+		_, err = store.GetBookRevision(ctx, "1", 4)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = store.ListBookRevisions(ctx, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("As Of", func(t *testing.T) {
+		got, err := store.GetBookAsOf(ctx, "1", afterCreate)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", got.Title)
+
+		got, err = store.GetBookAsOf(ctx, "1", afterUpdate)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", got.Title)
+
+		_, err = store.GetBookAsOf(ctx, "1", time.Now())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = store.GetBookAsOf(ctx, "1", afterCreate.Add(-time.Hour))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		_, err := store.RollbackBook(ctx, "1", 3)
+		assert.ErrorIs(t, err, service.ErrRevisionDeleted)
+
+		restored, err := store.RollbackBook(ctx, "1", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", restored.Title)
+
+		restored, err = store.RollbackBook(ctx, "1", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", restored.Title)
+
+		got, err := store.GetBook(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(200), got.Price)
+
+		revisions, err := store.ListBookRevisions(ctx, "1")
+		assert.NoError(t, err)
+		if assert.Len(t, revisions, 5) {
+			assert.Equal(t, pb.BookEvent_CREATED, revisions[3].Type)
+			assert.Equal(t, pb.BookEvent_UPDATED, revisions[4].Type)
+		}
+	})
+}
+
+func TestBookDetails_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	book := &pb.Book{
+		Id:              "1",
+		Author:          "case 1",
+		Title:           "test 1",
+		Price:           100,
+		Isbn:            "0-306-40615-2",
+		Description:     "description",
+		Publisher:       "publisher 1",
+		PublicationYear: 2001,
+		Language:        "RU",
+		PageCount:       320,
+		CoverUrl:        "https://example.com/1.jpg",
+	}
+	_, err := store.CreateBook(ctx, book)
+	assert.NoError(t, err)
+
+	got, err := store.GetBook(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "9780306406157", got.Isbn)
+	assert.Equal(t, "ru", got.Language)
+	assert.Equal(t, int32(320), got.PageCount)
+
+	t.Run("Invalid Details", func(t *testing.T) {
+		invalid := []*pb.Book{
+			{Id: "2", Isbn: "978-0-306-40615-8"},
+			{Id: "2", PublicationYear: 3000},
+			{Id: "2", PageCount: -1},
+			{Id: "2", Language: "russian"},
+			{Id: "2", CoverUrl: "cover.jpg"},
+		}
+		for _, book := range invalid {
+			_, err := store.CreateBook(ctx, book)
+			assert.ErrorIs(t, err, service.ErrInvalidBook)
+		}
+
+		_, err := store.UpdateBook(ctx, "1", &pb.Book{Id: "1", Isbn: "123"}, nil)
+		assert.ErrorIs(t, err, service.ErrInvalidBook)
+	})
+
+	t.Run("Duplicate ISBN", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "3", Isbn: "9780306406157"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "4", Publisher: "publisher 1", PublicationYear: 2010, Language: "en"})
+		assert.NoError(t, err)
+
+		books, err := store.SearchBook(ctx, &pb.Filter{Isbn: "978-0-306-40615-7"}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, books, 1)
+
+		books, err = store.SearchBook(ctx, &pb.Filter{Publisher: "publisher 1"}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, books, 2)
+
+		books, err = store.SearchBook(ctx, &pb.Filter{Publisher: "publisher 1", Language: "EN"}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		if assert.Len(t, books, 1) {
+			assert.Equal(t, "4", books[0].Id)
+		}
+
+		books, err = store.SearchBook(ctx, &pb.Filter{PublicationYear: 2001}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, books, 1)
+	})
+}
+
+func TestMoney_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	store := service.NewPostgresStore(db)
+
+	books := []*pb.Book{
+		{Id: "1", Author: "case 1", ListPrice: money.New("RUB", 499, 990_000_000)},
+		{Id: "2", Author: "case 1", ListPrice: money.New("usd", 12, 500_000_000)},
+		{Id: "3", Author: "case 1", Price: 700},
+	}
+	for _, book := range books {
+		_, err := store.CreateBook(ctx, book)
+		assert.NoError(t, err)
+	}
+
+	t.Run("Legacy Price", func(t *testing.T) {
+		book, err := store.GetBook(ctx, "3")
+		assert.NoError(t, err)
+		assert.Equal(t, "RUB", book.ListPrice.CurrencyCode)
+		assert.Equal(t, int64(700), book.ListPrice.Units)
+
+		book, err = store.GetBook(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(499), book.Price)
+		assert.Equal(t, int32(990_000_000), book.ListPrice.Nanos)
+
+		found, err := store.SearchBook(ctx, &pb.Filter{Price: 400}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, found, 2)
+
+		// Legacy prices are in rubles, so the legacy filter skips the
+		// dollar book.
+		found, err = store.SearchBook(ctx, &pb.Filter{Price: 10}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, found, 2)
+	})
+
+	t.Run("Currency Filter", func(t *testing.T) {
+		found, err := store.SearchBook(ctx, &pb.Filter{MinPrice: money.New("RUB", 499, 990_000_000)}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, found, 2)
+
+		found, err = store.SearchBook(ctx, &pb.Filter{
+			MinPrice: money.New("rub", 0, 0),
+			MaxPrice: money.New("RUB", 499, 990_000_000),
+		}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, "1", found[0].Id)
+		}
+
+		found, err = store.SearchBook(ctx, &pb.Filter{MinPrice: money.New("USD", 10, 0)}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, "2", found[0].Id)
+		}
+
+		_, err = store.SearchBook(ctx, &pb.Filter{MinPrice: money.New("RUB", 1, 0), MaxPrice: money.New("USD", 2, 0)}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.ErrorIs(t, err, service.ErrInvalidFilter)
+	})
+
+	t.Run("Invalid Price", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "4", ListPrice: money.New("RUB", -1, 0)})
+		assert.ErrorIs(t, err, service.ErrInvalidBook)
+		_, err = store.CreateBook(ctx, &pb.Book{Id: "4", ListPrice: money.New("RUBLES", 1, 0)})
+		assert.ErrorIs(t, err, service.ErrInvalidBook)
+	})
+
+	t.Run("Migration", func(t *testing.T) {
+		err := db.Exec("INSERT INTO books (id, author, title, price) VALUES ('old', 'case 2', 'legacy', 250)").Error
+		assert.NoError(t, err)
+		assert.NoError(t, database.Migrate(db))
+
+		book, err := store.GetBook(ctx, "old")
+		assert.NoError(t, err)
+		assert.Equal(t, "RUB", book.ListPrice.CurrencyCode)
+		assert.Equal(t, int64(250), book.ListPrice.Units)
+		assert.Equal(t, int32(250), book.Price)
+	})
+
+	t.Run("Legacy Update", func(t *testing.T) {
+		_, err := store.CreateBook(ctx, &pb.Book{Id: "5", Author: "case 3", ListPrice: money.New("USD", 12, 500_000_000)})
+		assert.NoError(t, err)
+
+		// An older client echoes the legacy price it read.
+		book, err := store.UpdateBook(ctx, "5", &pb.Book{Id: "5", Author: "case 3", Title: "renamed", Price: 12}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", book.ListPrice.CurrencyCode)
+		assert.Equal(t, int32(500_000_000), book.ListPrice.Nanos)
+
+		book, err = store.UpdateBook(ctx, "5", &pb.Book{Id: "5", Price: 12}, &fieldmaskpb.FieldMask{Paths: []string{"price"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", book.ListPrice.CurrencyCode)
+		assert.Equal(t, "renamed", book.Title)
+
+		// A new legacy price is in rubles.
+		book, err = store.UpdateBook(ctx, "5", &pb.Book{Id: "5", Author: "case 3", Price: 15}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "RUB", book.ListPrice.CurrencyCode)
+		assert.Equal(t, int64(15), book.ListPrice.Units)
+		assert.Equal(t, int32(0), book.ListPrice.Nanos)
+	})
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bookstoregrpc/isbn"
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidBook   = errors.New("invalid book")
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// bookColumns are the columns written when a book is updated.
+var bookColumns = []string{
+	"author", "title", "price", "price_currency", "price_amount", "isbn",
+	"description", "publisher", "publication_year", "language", "page_count",
+	"cover_url",
+}
+
+// validateBook checks the book and normalizes it in place: the ISBN, the
+// language and the list price, which is filled from the legacy price for
+// older clients, while price is derived from the list price.
+func validateBook(book *pb.Book) error {
+	if book.ListPrice == nil {
+		book.ListPrice = money.New(money.DefaultCurrency, int64(book.Price), 0)
+	}
+	if err := money.Validate(book.ListPrice); err != nil {
+		return fmt.Errorf("%w: list price: %v", ErrInvalidBook, err)
+	}
+	if book.ListPrice.Units < 0 || book.ListPrice.Nanos < 0 {
+		return fmt.Errorf("%w: list price must not be negative", ErrInvalidBook)
+	}
+	book.Price = money.AmountOf(book.ListPrice).Int32()
+
+	if book.Isbn != "" {
+		normalized, err := isbn.Normalize(book.Isbn)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBook, err)
+		}
+		book.Isbn = normalized
+	}
+
+	if book.PublicationYear < 0 || book.PublicationYear > int32(time.Now().Year()+1) {
+		return fmt.Errorf("%w: publication year %d is out of range", ErrInvalidBook, book.PublicationYear)
+	}
+
+	if book.PageCount < 0 {
+		return fmt.Errorf("%w: page count must not be negative", ErrInvalidBook)
+	}
+
+	book.Language = strings.ToLower(book.Language)
+	if book.Language != "" && !isLanguageCode(book.Language) {
+		return fmt.Errorf("%w: language must be an ISO 639 code", ErrInvalidBook)
+	}
+
+	if err := validateLinks(book); err != nil {
+		return err
+	}
+
+	if book.CoverUrl != "" {
+		u, err := url.Parse(book.CoverUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: cover url must be an absolute http or https url", ErrInvalidBook)
+		}
+	}
+
+	return nil
+}
+
+// validateFilter checks the price bounds of a search filter and
+// normalizes their currency codes.
+func validateFilter(filter *pb.Filter) error {
+	for _, bound := range []*pb.Money{filter.GetMinPrice(), filter.GetMaxPrice(), filter.GetMinEffectivePrice(), filter.GetMaxEffectivePrice()} {
+		if bound == nil {
+			continue
+		}
+		if err := money.Validate(bound); err != nil {
+			return fmt.Errorf("%w: price: %v", ErrInvalidFilter, err)
+		}
+	}
+
+	minPrice, maxPrice := filter.GetMinPrice(), filter.GetMaxPrice()
+	if minPrice != nil && maxPrice != nil && minPrice.CurrencyCode != maxPrice.CurrencyCode {
+		return fmt.Errorf("%w: min and max price must use the same currency", ErrInvalidFilter)
+	}
+	minPrice, maxPrice = filter.GetMinEffectivePrice(), filter.GetMaxEffectivePrice()
+	if minPrice != nil && maxPrice != nil && minPrice.CurrencyCode != maxPrice.CurrencyCode {
+		return fmt.Errorf("%w: min and max effective price must use the same currency", ErrInvalidFilter)
+	}
+
+	return nil
+}
+
+// normalizeISBN returns s as ISBN-13 if it is a valid ISBN and unchanged
+// otherwise, so that an invalid ISBN in a filter simply matches nothing.
+func normalizeISBN(s string) string {
+	if normalized, err := isbn.Normalize(s); err == nil {
+		return normalized
+	}
+
+	return s
+}
+
+func isLanguageCode(s string) bool {
+	if len(s) < 2 || len(s) > 3 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/events"
+	"bookstoregrpc/money"
+	"bookstoregrpc/outbox"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/revision"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func (ps *PostgresStore) WatchBooks(ctx context.Context, since uint64, epoch string) (*events.Subscription, error) {
+	return ps.events.Subscribe(since, epoch, events.DefaultBufferSize)
+}
+
+// changeSet collects the book changes of one transaction. Every change is
+// written to the outbox, the audit log and the revision history inside the
+// transaction, so it survives a crash right after the commit, and is
+// published on the in-process bus once the transaction has committed.
+type changeSet []*pb.BookEvent
+
+func (cs *changeSet) record(tx *gorm.DB, typ pb.BookEvent_Type, before, after *pb.Book) error {
+	event := &pb.BookEvent{
+		Type:   typ,
+		Before: cloneBook(before),
+		After:  cloneBook(after),
+		Time:   timestamppb.Now(),
+	}
+
+	if err := outbox.Write(tx, event); err != nil {
+		return err
+	}
+	if err := audit.Write(tx, event); err != nil {
+		return err
+	}
+	if err := revision.Write(tx, event); err != nil {
+		return err
+	}
+	*cs = append(*cs, event)
+
+	return nil
+}
+
+// publish must be called with ps.mu held, so that event sequence numbers
+// follow the order in which the changes were committed.
+func (ps *PostgresStore) publish(changes changeSet) {
+	for _, event := range changes {
+		ps.events.Publish(event)
+	}
+}
+
+func cloneBook(book *pb.Book) *pb.Book {
+	if book == nil {
+		return nil
+	}
+
+	return proto.Clone(book).(*pb.Book)
+}
+
+func (bs *BookServer) WatchBooks(req *pb.WatchBooksRequest, stream pb.BookService_WatchBooksServer) error {
+	ctx := stream.Context()
+	filter := req.GetFilter()
+	if err := validateFilter(filter); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := bs.Store.WatchBooks(ctx, req.GetSinceSequence(), req.GetSinceEpoch())
+	if errors.Is(err, events.ErrSequenceUnavailable) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, sub.Err().Error())
+			}
+
+			if !matchesFilter(filter, event.GetBefore()) && !matchesFilter(filter, event.GetAfter()) {
+				continue
+			}
+
+			if err := stream.Send(&pb.WatchBooksResponse{Event: event}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// matchesFilter applies the same conditions as SearchBook to a single book,
+// except that the author filter is not matched against author names and
+// aliases, and the category filter not against subcategories, which the
+// event does not carry.
+func matchesFilter(filter *pb.Filter, book *pb.Book) bool {
+	if book == nil {
+		return false
+	}
+	if filter.GetAuthor() != "" && book.GetAuthor() != filter.GetAuthor() {
+		return false
+	}
+	if filter.GetAuthorId() != "" && !slices.Contains(book.GetAuthorIds(), filter.GetAuthorId()) {
+		return false
+	}
+	if filter.GetCategoryId() != "" && !slices.Contains(book.GetCategoryIds(), filter.GetCategoryId()) {
+		return false
+	}
+	if filter.GetTag() != "" && !slices.Contains(book.GetTags(), strings.ToLower(strings.TrimSpace(filter.GetTag()))) {
+		return false
+	}
+	if filter.GetMinRating() > 0 && book.GetRatingAverage() < filter.GetMinRating() {
+		return false
+	}
+	price := money.AmountOf(book.GetListPrice())
+	if filter.GetPrice() > 0 &&
+		(book.GetListPrice().GetCurrencyCode() != money.DefaultCurrency || price.Cmp(money.Amount{Units: int64(filter.GetPrice())}) <= 0) {
+		return false
+	}
+	if minPrice := filter.GetMinPrice(); minPrice != nil &&
+		(book.GetListPrice().GetCurrencyCode() != minPrice.CurrencyCode || price.Cmp(money.AmountOf(minPrice)) < 0) {
+		return false
+	}
+	if maxPrice := filter.GetMaxPrice(); maxPrice != nil &&
+		(book.GetListPrice().GetCurrencyCode() != maxPrice.CurrencyCode || price.Cmp(money.AmountOf(maxPrice)) > 0) {
+		return false
+	}
+	if filter.GetIsbn() != "" && book.GetIsbn() != normalizeISBN(filter.GetIsbn()) {
+		return false
+	}
+	if filter.GetPublisher() != "" && book.GetPublisher() != filter.GetPublisher() {
+		return false
+	}
+	if filter.GetPublicationYear() > 0 && book.GetPublicationYear() != filter.GetPublicationYear() {
+		return false
+	}
+	if filter.GetLanguage() != "" && book.GetLanguage() != strings.ToLower(filter.GetLanguage()) {
+		return false
+	}
+
+	return true
+}
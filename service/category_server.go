@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type CategoryServer struct {
+	Store CategoryStore
+	pb.UnimplementedCategoryServiceServer
+}
+
+var categoryErrors = errorCodes{
+	{ErrInvalidCategory, codes.InvalidArgument},
+	{ErrCategoryInUse, codes.FailedPrecondition},
+}
+
+func NewCategoryServer(store CategoryStore) *CategoryServer {
+	return &CategoryServer{Store: store}
+}
+
+func (cs *CategoryServer) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error) {
+	if req.GetCategory() == nil {
+		return nil, status.Error(codes.InvalidArgument, "category is required")
+	}
+
+	category, err := cs.Store.CreateCategory(ctx, req.GetCategory())
+	if err != nil {
+		return nil, status.Error(categoryErrors.code(err), err.Error())
+	}
+
+	return &pb.CreateCategoryResponse{Category: category}, nil
+}
+
+func (cs *CategoryServer) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error) {
+	category, err := cs.Store.GetCategory(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(categoryErrors.code(err), err.Error())
+	}
+
+	return &pb.GetCategoryResponse{Category: category}, nil
+}
+
+func (cs *CategoryServer) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categories, err := cs.Store.ListCategories(ctx, req.GetParentId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListCategoriesResponse{Categories: categories}, nil
+}
+
+func (cs *CategoryServer) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.UpdateCategoryResponse, error) {
+	if req.GetCategory() == nil {
+		return nil, status.Error(codes.InvalidArgument, "category is required")
+	}
+
+	category, err := cs.Store.UpdateCategory(ctx, req.GetId(), req.GetCategory())
+	if err != nil {
+		return nil, status.Error(categoryErrors.code(err), err.Error())
+	}
+
+	return &pb.UpdateCategoryResponse{Category: category}, nil
+}
+
+func (cs *CategoryServer) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	category, err := cs.Store.DeleteCategory(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(categoryErrors.code(err), err.Error())
+	}
+
+	return &pb.DeleteCategoryResponse{Category: category}, nil
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCategories_server(t *testing.T) {
+	ctx := context.Background()
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterCategoryServiceServer(s, service.NewCategoryServer(service.NewPostgresCategoryStore(db)))
+		pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+	})
+
+	categories := pb.NewCategoryServiceClient(conn)
+	books := pb.NewBookServiceClient(conn)
+
+	created, err := categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Category: &pb.Category{Name: "Фантастика"}})
+	assert.NoError(t, err)
+	id := created.GetCategory().GetId()
+
+	_, err = categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Category: &pb.Category{Name: "Киберпанк", ParentId: "missing"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: "solaris", Title: "Солярис", Price: 700, CategoryIds: []string{id}, Tags: []string{"space"}}})
+	assert.NoError(t, err)
+
+	search, err := books.SearchBook(ctx, &pb.SearchBookRequest{Filter: &pb.Filter{CategoryId: id}, IncludeFacets: true})
+	assert.NoError(t, err)
+
+	var found []*pb.Book
+	var facets *pb.Facets
+	for {
+		res, err := search.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if res.GetFacets() != nil {
+			facets = res.GetFacets()
+			continue
+		}
+		found = append(found, res.GetBook())
+	}
+	assert.Len(t, found, 1)
+	if assert.NotNil(t, facets) && assert.Len(t, facets.Categories, 1) {
+		assert.Equal(t, "Фантастика", facets.Categories[0].Label)
+		assert.Equal(t, int64(1), facets.PriceBuckets[0].Count)
+	}
+
+	tags, err := books.ListTags(ctx, &pb.ListTagsRequest{})
+	assert.NoError(t, err)
+	if assert.Len(t, tags.GetTags(), 1) {
+		assert.Equal(t, "space", tags.GetTags()[0].Value)
+	}
+
+	_, err = categories.DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: id})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
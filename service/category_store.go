@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bookstoregrpc/model"
+	"bookstoregrpc/pb"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCategory = errors.New("invalid category")
+	ErrCategoryInUse   = errors.New("category has subcategories or books")
+)
+
+type CategoryStore interface {
+	CreateCategory(context.Context, *pb.Category) (*pb.Category, error)
+	GetCategory(context.Context, string) (*pb.Category, error)
+	ListCategories(context.Context, string) ([]*pb.Category, error)
+	UpdateCategory(context.Context, string, *pb.Category) (*pb.Category, error)
+	DeleteCategory(context.Context, string) (*pb.Category, error)
+}
+
+type PostgresCategoryStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresCategoryStore(db *gorm.DB) CategoryStore {
+	return &PostgresCategoryStore{db: db}
+}
+
+func (cs *PostgresCategoryStore) CreateCategory(ctx context.Context, category *pb.Category) (_ *pb.Category, err error) {
+	log.Println("CREATECATEGORY receive request")
+	ctx, span := startSpan(ctx, "CreateCategory")
+	defer func() { endSpan(span, err) }()
+
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+	if category.Id == "" {
+		category.Id = uuid.New().String()
+	}
+
+	err = cs.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tree, err := loadCategoryTree(tx)
+		if err != nil {
+			return err
+		}
+		if category.ParentId != "" && tree.get(category.ParentId) == nil {
+			return fmt.Errorf("%w: unknown parent id", ErrInvalidCategory)
+		}
+
+		return tx.Create(&model.Category{ID: category.Id, Name: category.Name, ParentID: category.ParentId}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (cs *PostgresCategoryStore) GetCategory(ctx context.Context, id string) (_ *pb.Category, err error) {
+	log.Println("GETCATEGORY receive request")
+	ctx, span := startSpan(ctx, "GetCategory")
+	defer func() { endSpan(span, err) }()
+
+	var row model.Category
+	if err := cs.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	return row.Proto(), nil
+}
+
+// ListCategories returns every category, or the direct children of parentID.
+func (cs *PostgresCategoryStore) ListCategories(ctx context.Context, parentID string) (_ []*pb.Category, err error) {
+	log.Println("LISTCATEGORIES receive request")
+	ctx, span := startSpan(ctx, "ListCategories")
+	defer func() { endSpan(span, err) }()
+
+	query := cs.db.WithContext(ctx).Order("name, id")
+	if parentID != "" {
+		query = query.Where("parent_id = ?", parentID)
+	}
+
+	var rows []*model.Category
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	categories := make([]*pb.Category, len(rows))
+	for i, row := range rows {
+		categories[i] = row.Proto()
+	}
+
+	return categories, nil
+}
+
+func (cs *PostgresCategoryStore) UpdateCategory(ctx context.Context, id string, category *pb.Category) (_ *pb.Category, err error) {
+	log.Println("UPDATECATEGORY receive request")
+	ctx, span := startSpan(ctx, "UpdateCategory")
+	defer func() { endSpan(span, err) }()
+
+	if category.Id != "" && category.Id != id {
+		return nil, fmt.Errorf("%w: id must match the request id", ErrInvalidCategory)
+	}
+	category.Id = id
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+
+	err = cs.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tree, err := loadCategoryTree(tx)
+		if err != nil {
+			return err
+		}
+		if tree.get(id) == nil {
+			return gorm.ErrRecordNotFound
+		}
+		if category.ParentId != "" {
+			if tree.get(category.ParentId) == nil {
+				return fmt.Errorf("%w: unknown parent id", ErrInvalidCategory)
+			}
+			if slices.Contains(tree.ancestors(category.ParentId), id) {
+				return fmt.Errorf("%w: a category cannot be moved under itself", ErrInvalidCategory)
+			}
+		}
+
+		return tx.Model(&model.Category{}).Where("id = ?", id).
+			Updates(map[string]any{"name": category.Name, "parent_id": category.ParentId}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (cs *PostgresCategoryStore) DeleteCategory(ctx context.Context, id string) (category *pb.Category, err error) {
+	log.Println("DELETECATEGORY receive request")
+	ctx, span := startSpan(ctx, "DeleteCategory")
+	defer func() { endSpan(span, err) }()
+
+	err = cs.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row model.Category
+		if err := tx.Where("id = ?", id).First(&row).Error; err != nil {
+			return err
+		}
+		category = row.Proto()
+
+		var children, books int64
+		if err := tx.Model(&model.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.BookCategory{}).Where("category_id = ?", id).Count(&books).Error; err != nil {
+			return err
+		}
+		if children > 0 || books > 0 {
+			return ErrCategoryInUse
+		}
+
+		return tx.Where("id = ?", id).Delete(&model.Category{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// categoryTree is the whole category table, which is small enough to walk
+// in memory.
+type categoryTree struct {
+	byID     map[string]*model.Category
+	children map[string][]string
+}
+
+func loadCategoryTree(db *gorm.DB) (*categoryTree, error) {
+	var rows []*model.Category
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	tree := &categoryTree{
+		byID:     make(map[string]*model.Category, len(rows)),
+		children: make(map[string][]string),
+	}
+	for _, row := range rows {
+		tree.byID[row.ID] = row
+		tree.children[row.ParentID] = append(tree.children[row.ParentID], row.ID)
+	}
+
+	return tree, nil
+}
+
+func (t *categoryTree) get(id string) *model.Category {
+	return t.byID[id]
+}
+
+// descendants returns id and the ids of all categories below it.
+func (t *categoryTree) descendants(id string) []string {
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, t.children[ids[i]]...)
+	}
+
+	return ids
+}
+
+// ancestors returns id and the ids of all categories above it, nearest
+// first.
+func (t *categoryTree) ancestors(id string) []string {
+	var ids []string
+	for category := t.byID[id]; category != nil && !slices.Contains(ids, category.ID); category = t.byID[category.ParentID] {
+		ids = append(ids, category.ID)
+	}
+
+	return ids
+}
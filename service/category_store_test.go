@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"bookstoregrpc/money"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCategories_store(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := initTestDB(t)
+	categories := service.NewPostgresCategoryStore(db)
+	authors := service.NewPostgresAuthorStore(db)
+	store := service.NewPostgresStore(db)
+
+	for _, category := range []*pb.Category{
+		{Id: "fiction", Name: "Художественная литература"},
+		{Id: "novel", Name: "Роман", ParentId: "fiction"},
+		{Id: "historical", Name: "Исторический роман", ParentId: "novel"},
+		{Id: "poetry", Name: "Поэзия", ParentId: "fiction"},
+	} {
+		_, err := categories.CreateCategory(ctx, category)
+		assert.NoError(t, err)
+	}
+
+	_, err := categories.CreateCategory(ctx, &pb.Category{Name: "orphan", ParentId: "missing"})
+	assert.ErrorIs(t, err, service.ErrInvalidCategory)
+
+	children, err := categories.ListCategories(ctx, "fiction")
+	assert.NoError(t, err)
+	assert.Len(t, children, 2)
+
+	t.Run("Tree", func(t *testing.T) {
+		_, err := categories.UpdateCategory(ctx, "fiction", &pb.Category{Name: "Художественная литература", ParentId: "historical"})
+		assert.ErrorIs(t, err, service.ErrInvalidCategory)
+		_, err = categories.UpdateCategory(ctx, "novel", &pb.Category{Name: "Роман", ParentId: "novel"})
+		assert.ErrorIs(t, err, service.ErrInvalidCategory)
+
+		_, err = categories.DeleteCategory(ctx, "novel")
+		assert.ErrorIs(t, err, service.ErrCategoryInUse)
+	})
+
+	tolstoy, err := authors.CreateAuthor(ctx, &pb.Author{Id: "tolstoy", Name: "Лев Толстой"})
+	assert.NoError(t, err)
+
+	books := []*pb.Book{
+		{Id: "war", Title: "Война и мир", ListPrice: money.New("RUB", 1200, 0), AuthorIds: []string{tolstoy.Id}, CategoryIds: []string{"historical", "novel"}, Tags: []string{" Classic", "epic", "classic"}},
+		{Id: "anna", Title: "Анна Каренина", ListPrice: money.New("RUB", 450, 0), AuthorIds: []string{tolstoy.Id}, CategoryIds: []string{"novel"}, Tags: []string{"classic"}},
+		{Id: "poems", Title: "Стихи", ListPrice: money.New("EUR", 9, 990_000_000), CategoryIds: []string{"poetry"}},
+	}
+	for _, book := range books {
+		_, err := store.CreateBook(ctx, book)
+		assert.NoError(t, err)
+	}
+
+	_, err = store.CreateBook(ctx, &pb.Book{Id: "lost", CategoryIds: []string{"missing"}})
+	assert.ErrorIs(t, err, service.ErrInvalidBook)
+
+	t.Run("Search", func(t *testing.T) {
+		book, err := store.GetBook(ctx, "war")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"historical", "novel"}, book.CategoryIds)
+		assert.Equal(t, []string{"classic", "epic"}, book.Tags)
+
+		found, err := store.SearchBook(ctx, &pb.Filter{CategoryId: "fiction"}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		assert.Len(t, found, 3)
+
+		found, err = store.SearchBook(ctx, &pb.Filter{CategoryId: "novel", Tag: "Epic"}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+		if assert.Len(t, found, 1) {
+			assert.Equal(t, "war", found[0].Id)
+		}
+
+		tags, err := store.ListTags(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*pb.FacetCount{{Value: "classic", Count: 2}, {Value: "epic", Count: 1}}, tags)
+	})
+
+	t.Run("Facets", func(t *testing.T) {
+		found, err := store.SearchBook(ctx, &pb.Filter{}, pb.SearchBookRequest_SORT_UNSPECIFIED)
+		assert.NoError(t, err)
+
+		facets, err := store.BookFacets(ctx, found)
+		assert.NoError(t, err)
+
+		counts := make(map[string]int64)
+		for _, count := range facets.Categories {
+			counts[count.Value] = count.Count
+		}
+		assert.Equal(t, map[string]int64{"fiction": 3, "novel": 2, "historical": 1, "poetry": 1}, counts)
+		assert.Equal(t, "fiction", facets.Categories[0].Value)
+
+		if assert.Len(t, facets.Authors, 1) {
+			assert.True(t, proto.Equal(&pb.FacetCount{Value: "tolstoy", Label: "Лев Толстой", Count: 2}, facets.Authors[0]))
+		}
+
+		if assert.Len(t, facets.PriceBuckets, 3) {
+			assert.Equal(t, "EUR", facets.PriceBuckets[0].Min.CurrencyCode)
+			assert.Equal(t, int64(500), facets.PriceBuckets[0].Max.Units)
+			assert.Equal(t, int64(0), facets.PriceBuckets[1].Min.Units)
+			assert.Equal(t, int64(1000), facets.PriceBuckets[2].Min.Units)
+			assert.Equal(t, int64(2000), facets.PriceBuckets[2].Max.Units)
+		}
+	})
+}
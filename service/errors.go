@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// errorCodes maps the sentinel errors of one store to status codes. Each
+// server keeps the table of its own store, so the servers do not depend on
+// each other's packages.
+type errorCodes []errorCode
+
+type errorCode struct {
+	err  error
+	code codes.Code
+}
+
+// code returns the code of the first sentinel in the table err wraps, so
+// an error wrapping several always maps the same way, or the code of the
+// database errors every store can return.
+func (ec errorCodes) code(err error) codes.Code {
+	for _, c := range ec {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return codes.NotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return codes.AlreadyExists
+	case errors.Is(err, gorm.ErrForeignKeyViolated), errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return codes.FailedPrecondition
+	default:
+		return status.Code(err)
+	}
+}
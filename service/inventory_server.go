@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type InventoryServer struct {
+	Store *inventory.Store
+	pb.UnimplementedInventoryServiceServer
+}
+
+var inventoryErrors = errorCodes{
+	{inventory.ErrInvalidQuantity, codes.InvalidArgument},
+	{inventory.ErrInsufficientStock, codes.FailedPrecondition},
+}
+
+func NewInventoryServer(store *inventory.Store) *InventoryServer {
+	return &InventoryServer{Store: store}
+}
+
+func (is *InventoryServer) GetStock(ctx context.Context, req *pb.GetStockRequest) (*pb.GetStockResponse, error) {
+	levels, err := is.Store.Levels(ctx, req.GetBookId())
+	if err != nil {
+		return nil, status.Error(inventoryErrors.code(err), err.Error())
+	}
+
+	res := &pb.GetStockResponse{Levels: levels}
+	for _, level := range levels {
+		res.OnHand += level.OnHand
+		res.Reserved += level.Reserved
+		res.Available += level.Available
+	}
+
+	return res, nil
+}
+
+func (is *InventoryServer) AdjustStock(ctx context.Context, req *pb.AdjustStockRequest) (*pb.AdjustStockResponse, error) {
+	level, err := is.Store.Adjust(ctx, req.GetBookId(), req.GetWarehouse(), req.GetDelta())
+	if err != nil {
+		return nil, status.Error(inventoryErrors.code(err), err.Error())
+	}
+
+	return &pb.AdjustStockResponse{Stock: level}, nil
+}
+
+func (is *InventoryServer) SetLowStockThreshold(ctx context.Context, req *pb.SetLowStockThresholdRequest) (*pb.SetLowStockThresholdResponse, error) {
+	level, err := is.Store.SetThreshold(ctx, req.GetBookId(), req.GetWarehouse(), req.GetThreshold())
+	if err != nil {
+		return nil, status.Error(inventoryErrors.code(err), err.Error())
+	}
+
+	return &pb.SetLowStockThresholdResponse{Stock: level}, nil
+}
+
+func (is *InventoryServer) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	reservation, err := is.Store.Reserve(ctx, req.GetBookId(), req.GetWarehouse(), req.GetQuantity())
+	if err != nil {
+		return nil, status.Error(inventoryErrors.code(err), err.Error())
+	}
+
+	return &pb.ReserveStockResponse{Reservation: reservation}, nil
+}
+
+func (is *InventoryServer) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.ReleaseReservationResponse, error) {
+	reservation, err := is.Store.Release(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(inventoryErrors.code(err), err.Error())
+	}
+
+	return &pb.ReleaseReservationResponse{Reservation: reservation}, nil
+}
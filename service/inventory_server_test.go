@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInventory_server(t *testing.T) {
+	ctx := context.Background()
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterInventoryServiceServer(s, service.NewInventoryServer(inventory.NewStore(db)))
+	})
+
+	client := pb.NewInventoryServiceClient(conn)
+
+	_, err := service.NewPostgresStore(db).CreateBook(ctx, &pb.Book{Id: "1", Title: "Белая гвардия"})
+	assert.NoError(t, err)
+
+	_, err = client.AdjustStock(ctx, &pb.AdjustStockRequest{BookId: "1", Warehouse: "msk", Delta: 4})
+	assert.NoError(t, err)
+	_, err = client.AdjustStock(ctx, &pb.AdjustStockRequest{BookId: "1", Warehouse: "spb", Delta: 1})
+	assert.NoError(t, err)
+
+	res, err := client.ReserveStock(ctx, &pb.ReserveStockRequest{BookId: "1", Quantity: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "msk", res.GetReservation().GetWarehouse())
+
+	_, err = client.ReserveStock(ctx, &pb.ReserveStockRequest{BookId: "1", Quantity: 2})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.ReserveStock(ctx, &pb.ReserveStockRequest{BookId: "1", Quantity: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetStock(ctx, &pb.GetStockRequest{BookId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	stock, err := client.GetStock(ctx, &pb.GetStockRequest{BookId: "1"})
+	assert.NoError(t, err)
+	assert.Len(t, stock.GetLevels(), 2)
+	assert.Equal(t, int64(5), stock.GetOnHand())
+	assert.Equal(t, int64(3), stock.GetReserved())
+	assert.Equal(t, int64(2), stock.GetAvailable())
+
+	_, err = client.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{Id: res.GetReservation().GetId()})
+	assert.NoError(t, err)
+}
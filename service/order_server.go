@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/order"
+	"bookstoregrpc/pb"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type OrderServer struct {
+	Store *order.Store
+	pb.UnimplementedOrderServiceServer
+}
+
+var orderErrors = errorCodes{
+	{order.ErrCartPlaced, codes.FailedPrecondition},
+	{order.ErrEmptyCart, codes.FailedPrecondition},
+	{order.ErrMixedCurrencies, codes.FailedPrecondition},
+	{order.ErrInvalidTransition, codes.FailedPrecondition},
+	{inventory.ErrInvalidQuantity, codes.InvalidArgument},
+	{inventory.ErrInsufficientStock, codes.FailedPrecondition},
+}
+
+func NewOrderServer(store *order.Store) *OrderServer {
+	return &OrderServer{Store: store}
+}
+
+func (or *OrderServer) CreateCart(ctx context.Context, _ *pb.CreateCartRequest) (*pb.CreateCartResponse, error) {
+	cart, err := or.Store.CreateCart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateCartResponse{Cart: cart}, nil
+}
+
+func (or *OrderServer) GetCart(ctx context.Context, req *pb.GetCartRequest) (*pb.GetCartResponse, error) {
+	cart, err := or.Store.GetCart(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.GetCartResponse{Cart: cart}, nil
+}
+
+func (or *OrderServer) SetCartItem(ctx context.Context, req *pb.SetCartItemRequest) (*pb.SetCartItemResponse, error) {
+	cart, err := or.Store.SetItem(ctx, req.GetCartId(), req.GetBookId(), req.GetQuantity())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.SetCartItemResponse{Cart: cart}, nil
+}
+
+func (or *OrderServer) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	placed, err := or.Store.Place(ctx, req.GetCartId())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.PlaceOrderResponse{Order: placed}, nil
+}
+
+func (or *OrderServer) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
+	found, err := or.Store.GetOrder(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.GetOrderResponse{Order: found}, nil
+}
+
+func (or *OrderServer) PayOrder(ctx context.Context, req *pb.PayOrderRequest) (*pb.PayOrderResponse, error) {
+	paid, err := or.Store.Pay(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.PayOrderResponse{Order: paid}, nil
+}
+
+func (or *OrderServer) ShipOrder(ctx context.Context, req *pb.ShipOrderRequest) (*pb.ShipOrderResponse, error) {
+	shipped, err := or.Store.Ship(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.ShipOrderResponse{Order: shipped}, nil
+}
+
+func (or *OrderServer) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
+	cancelled, err := or.Store.Cancel(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(orderErrors.code(err), err.Error())
+	}
+
+	return &pb.CancelOrderResponse{Order: cancelled}, nil
+}
@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"bookstoregrpc/inventory"
+	"bookstoregrpc/order"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOrders_server(t *testing.T) {
+	ctx := context.Background()
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterOrderServiceServer(s, service.NewOrderServer(order.NewStore(db)))
+	})
+
+	client := pb.NewOrderServiceClient(conn)
+
+	_, err := service.NewPostgresStore(db).CreateBook(ctx, &pb.Book{Id: "1", Title: "Мёртвые души", Price: 400})
+	assert.NoError(t, err)
+	_, err = inventory.NewStore(db).Adjust(ctx, "1", "", 1)
+	assert.NoError(t, err)
+
+	cart, err := client.CreateCart(ctx, &pb.CreateCartRequest{})
+	assert.NoError(t, err)
+	cartID := cart.GetCart().GetId()
+
+	_, err = client.SetCartItem(ctx, &pb.SetCartItemRequest{CartId: cartID, BookId: "1", Quantity: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SetCartItem(ctx, &pb.SetCartItemRequest{CartId: cartID, BookId: "1", Quantity: 2})
+	assert.NoError(t, err)
+
+	_, err = client.PlaceOrder(ctx, &pb.PlaceOrderRequest{CartId: cartID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.SetCartItem(ctx, &pb.SetCartItemRequest{CartId: cartID, BookId: "1", Quantity: 1})
+	assert.NoError(t, err)
+	placed, err := client.PlaceOrder(ctx, &pb.PlaceOrderRequest{CartId: cartID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(400), placed.GetOrder().GetTotal().GetUnits())
+
+	again, err := client.PlaceOrder(ctx, &pb.PlaceOrderRequest{CartId: cartID})
+	assert.NoError(t, err)
+	assert.Equal(t, placed.GetOrder().GetId(), again.GetOrder().GetId())
+
+	_, err = client.ShipOrder(ctx, &pb.ShipOrderRequest{Id: placed.GetOrder().GetId()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.GetOrder(ctx, &pb.GetOrderRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PricingServer struct {
+	Store *pricing.Store
+	pb.UnimplementedPricingServiceServer
+}
+
+var pricingErrors = errorCodes{
+	{pricing.ErrInvalidRule, codes.InvalidArgument},
+}
+
+func NewPricingServer(store *pricing.Store) *PricingServer {
+	return &PricingServer{Store: store}
+}
+
+func (ps *PricingServer) CreatePriceRule(ctx context.Context, req *pb.CreatePriceRuleRequest) (*pb.CreatePriceRuleResponse, error) {
+	rule, err := ps.Store.Create(ctx, req.GetPriceRule())
+	if err != nil {
+		return nil, status.Error(pricingErrors.code(err), err.Error())
+	}
+
+	return &pb.CreatePriceRuleResponse{PriceRule: rule}, nil
+}
+
+func (ps *PricingServer) ListPriceRules(ctx context.Context, req *pb.ListPriceRulesRequest) (*pb.ListPriceRulesResponse, error) {
+	rules, err := ps.Store.List(ctx, req.GetActiveOnly())
+	if err != nil {
+		return nil, status.Error(pricingErrors.code(err), err.Error())
+	}
+
+	return &pb.ListPriceRulesResponse{PriceRules: rules}, nil
+}
+
+func (ps *PricingServer) DeletePriceRule(ctx context.Context, req *pb.DeletePriceRuleRequest) (*pb.DeletePriceRuleResponse, error) {
+	rule, err := ps.Store.Delete(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(pricingErrors.code(err), err.Error())
+	}
+
+	return &pb.DeletePriceRuleResponse{PriceRule: rule}, nil
+}
@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/pricing"
+	"bookstoregrpc/service"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestPriceRules_server(t *testing.T) {
+	ctx := context.Background()
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterPricingServiceServer(s, service.NewPricingServer(pricing.NewStore(db)))
+		pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+		pb.RegisterCategoryServiceServer(s, service.NewCategoryServer(service.NewPostgresCategoryStore(db)))
+	})
+
+	rules := pb.NewPricingServiceClient(conn)
+	books := pb.NewBookServiceClient(conn)
+	categories := pb.NewCategoryServiceClient(conn)
+
+	for _, category := range []*pb.Category{{Id: "fiction", Name: "Художественная литература"}, {Id: "novels", Name: "Романы", ParentId: "fiction"}} {
+		_, err := categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Category: category})
+		assert.NoError(t, err)
+	}
+	for _, book := range []*pb.Book{
+		{Id: "karamazov", Title: "Братья Карамазовы", ListPrice: &pb.Money{CurrencyCode: "RUB", Units: 1000}, CategoryIds: []string{"novels"}},
+		{Id: "poems", Title: "Стихотворения", ListPrice: &pb.Money{CurrencyCode: "RUB", Units: 400}},
+	} {
+		_, err := books.CreateBook(ctx, &pb.CreateBookRequest{Book: book})
+		assert.NoError(t, err)
+	}
+
+	_, err := rules.CreatePriceRule(ctx, &pb.CreatePriceRuleRequest{PriceRule: &pb.PriceRule{Scope: pb.PriceRule_CATEGORY, ScopeId: "fiction"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := rules.CreatePriceRule(ctx, &pb.CreatePriceRuleRequest{PriceRule: &pb.PriceRule{
+		Name:     "Неделя художественной литературы",
+		Discount: &pb.PriceRule_PercentOff{PercentOff: 25},
+		Scope:    pb.PriceRule_CATEGORY,
+		ScopeId:  "fiction",
+	}})
+	assert.NoError(t, err)
+
+	got, err := books.ReadBook(ctx, &pb.ReadBookRequest{Id: "karamazov"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(750), got.GetBook().GetEffectivePrice().GetUnits())
+	assert.Equal(t, created.GetPriceRule().GetId(), got.GetBook().GetPriceRuleId())
+
+	got, err = books.ReadBook(ctx, &pb.ReadBookRequest{Id: "poems"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(400), got.GetBook().GetEffectivePrice().GetUnits())
+	assert.Empty(t, got.GetBook().GetPriceRuleId())
+
+	got, err = books.ReadBook(ctx, &pb.ReadBookRequest{Id: "karamazov", AsOf: timestamppb.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(750), got.GetBook().GetEffectivePrice().GetUnits())
+
+	search := func(filter *pb.Filter) []string {
+		stream, err := books.SearchBook(ctx, &pb.SearchBookRequest{Filter: filter})
+		assert.NoError(t, err)
+
+		var ids []string
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				return ids
+			}
+			if err != nil {
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+				return nil
+			}
+			ids = append(ids, res.GetBook().GetId())
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"karamazov"}, search(&pb.Filter{MinEffectivePrice: &pb.Money{CurrencyCode: "RUB", Units: 500}}))
+	assert.Empty(t, search(&pb.Filter{MaxEffectivePrice: &pb.Money{CurrencyCode: "RUB", Units: 700}, MinPrice: &pb.Money{CurrencyCode: "RUB", Units: 900}}))
+	assert.Empty(t, search(&pb.Filter{MinEffectivePrice: &pb.Money{CurrencyCode: "RUB"}, MaxEffectivePrice: &pb.Money{CurrencyCode: "USD", Units: 1}}))
+
+	_, err = rules.DeletePriceRule(ctx, &pb.DeletePriceRuleRequest{Id: created.GetPriceRule().GetId()})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"karamazov", "poems"}, search(&pb.Filter{MaxEffectivePrice: &pb.Money{CurrencyCode: "RUB", Units: 1000}}))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/review"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ReviewServer struct {
+	Store *review.Store
+	pb.UnimplementedReviewServiceServer
+}
+
+var reviewErrors = errorCodes{
+	{review.ErrInvalidReview, codes.InvalidArgument},
+	{review.ErrAnonymous, codes.Unauthenticated},
+	{review.ErrNotAuthor, codes.PermissionDenied},
+	{review.ErrNotModerator, codes.PermissionDenied},
+	{review.ErrConflict, codes.Aborted},
+}
+
+func NewReviewServer(store *review.Store) *ReviewServer {
+	return &ReviewServer{Store: store}
+}
+
+func (rs *ReviewServer) CreateReview(ctx context.Context, req *pb.CreateReviewRequest) (*pb.CreateReviewResponse, error) {
+	if req.GetReview() == nil {
+		return nil, status.Error(codes.InvalidArgument, "review is required")
+	}
+
+	created, err := rs.Store.Create(ctx, req.GetBookId(), req.GetReview())
+	if err != nil {
+		return nil, status.Error(reviewErrors.code(err), err.Error())
+	}
+
+	return &pb.CreateReviewResponse{Review: created}, nil
+}
+
+func (rs *ReviewServer) ListReviews(ctx context.Context, req *pb.ListReviewsRequest) (*pb.ListReviewsResponse, error) {
+	reviews, err := rs.Store.List(ctx, req.GetBookId(), req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListReviewsResponse{Reviews: reviews}, nil
+}
+
+func (rs *ReviewServer) UpdateReview(ctx context.Context, req *pb.UpdateReviewRequest) (*pb.UpdateReviewResponse, error) {
+	if req.GetReview() == nil {
+		return nil, status.Error(codes.InvalidArgument, "review is required")
+	}
+
+	updated, err := rs.Store.Update(ctx, req.GetId(), req.GetReview())
+	if err != nil {
+		return nil, status.Error(reviewErrors.code(err), err.Error())
+	}
+
+	return &pb.UpdateReviewResponse{Review: updated}, nil
+}
+
+func (rs *ReviewServer) DeleteReview(ctx context.Context, req *pb.DeleteReviewRequest) (*pb.DeleteReviewResponse, error) {
+	deleted, err := rs.Store.Delete(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(reviewErrors.code(err), err.Error())
+	}
+
+	return &pb.DeleteReviewResponse{Review: deleted}, nil
+}
+
+func (rs *ReviewServer) ModerateReview(ctx context.Context, req *pb.ModerateReviewRequest) (*pb.ModerateReviewResponse, error) {
+	moderated, err := rs.Store.Moderate(ctx, req.GetId(), req.GetStatus())
+	if err != nil {
+		return nil, status.Error(reviewErrors.code(err), err.Error())
+	}
+
+	return &pb.ModerateReviewResponse{Review: moderated}, nil
+}
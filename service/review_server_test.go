@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/review"
+	"bookstoregrpc/service"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestReviews_server(t *testing.T) {
+	ctx := context.Background()
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		pb.RegisterReviewServiceServer(s, service.NewReviewServer(review.NewStore(db, audit.ParsePrincipals("moderator"))))
+		pb.RegisterBookServiceServer(s, service.NewBookServer(service.NewPostgresStore(db)))
+	}, grpc.UnaryInterceptor(audit.UnaryServerInterceptor(principalFromMetadata)), grpc.StreamInterceptor(audit.StreamServerInterceptor(principalFromMetadata)))
+
+	reviews := pb.NewReviewServiceClient(conn)
+	books := pb.NewBookServiceClient(conn)
+	as := func(principal string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, principalKey, principal)
+	}
+
+	for _, id := range []string{"karamazov", "idiot", "demons"} {
+		_, err := books.CreateBook(ctx, &pb.CreateBookRequest{Book: &pb.Book{Id: id, Title: id}})
+		assert.NoError(t, err)
+	}
+
+	_, err := reviews.CreateReview(ctx, &pb.CreateReviewRequest{BookId: "idiot", Review: &pb.Review{Rating: 3}})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	for _, r := range []struct {
+		book      string
+		principal string
+		rating    int32
+	}{
+		{"karamazov", "alice", 5}, {"karamazov", "bob", 4}, {"idiot", "alice", 3}, {"demons", "bob", 2},
+	} {
+		created, err := reviews.CreateReview(as(r.principal), &pb.CreateReviewRequest{BookId: r.book, Review: &pb.Review{Rating: r.rating}})
+		assert.NoError(t, err)
+		_, err = reviews.ModerateReview(as(r.principal), &pb.ModerateReviewRequest{Id: created.GetReview().GetId(), Status: pb.Review_APPROVED})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = reviews.ModerateReview(as("moderator"), &pb.ModerateReviewRequest{Id: created.GetReview().GetId(), Status: pb.Review_APPROVED})
+		assert.NoError(t, err)
+
+		if r.book == "demons" {
+			_, err = reviews.DeleteReview(as("alice"), &pb.DeleteReviewRequest{Id: created.GetReview().GetId()})
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		}
+	}
+
+	search := func(req *pb.SearchBookRequest) []string {
+		stream, err := books.SearchBook(ctx, req)
+		assert.NoError(t, err)
+
+		var ids []string
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				return ids
+			}
+			assert.NoError(t, err)
+			ids = append(ids, res.GetBook().GetId())
+		}
+	}
+
+	assert.Equal(t, []string{"karamazov", "idiot", "demons"}, search(&pb.SearchBookRequest{Sort: pb.SearchBookRequest_RATING_DESC}))
+	assert.Equal(t, []string{"idiot", "karamazov"}, search(&pb.SearchBookRequest{Filter: &pb.Filter{MinRating: 3}, Sort: pb.SearchBookRequest_RATING_ASC}))
+
+	// Writing a book leaves its rating alone.
+	updated, err := books.UpdateBook(ctx, &pb.UpdateBookRequest{Id: "karamazov", Book: &pb.Book{Id: "karamazov", Title: "Братья Карамазовы", RatingCount: 100}})
+	assert.NoError(t, err)
+	assert.Equal(t, 4.5, updated.GetBook().GetRatingAverage())
+	assert.Equal(t, int64(2), updated.GetBook().GetRatingCount())
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bookstoregrpc/pb"
+	"bookstoregrpc/webhook"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type WebhookServer struct {
+	Store *webhook.Store
+	pb.UnimplementedWebhookServiceServer
+}
+
+var webhookErrors = errorCodes{
+	{webhook.ErrInvalidURL, codes.InvalidArgument},
+	{webhook.ErrPrivateURL, codes.InvalidArgument},
+	{webhook.ErrNotAdmin, codes.PermissionDenied},
+}
+
+func NewWebhookServer(store *webhook.Store) *WebhookServer {
+	return &WebhookServer{Store: store}
+}
+
+func (ws *WebhookServer) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.CreateWebhookResponse, error) {
+	if req.GetWebhook() == nil {
+		return nil, status.Error(codes.InvalidArgument, "webhook is required")
+	}
+
+	hook, err := ws.Store.Create(ctx, req.GetWebhook())
+	if err != nil {
+		return nil, status.Error(webhookErrors.code(err), err.Error())
+	}
+
+	return &pb.CreateWebhookResponse{Webhook: hook}, nil
+}
+
+func (ws *WebhookServer) ListWebhooks(ctx context.Context, _ *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	hooks, err := ws.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListWebhooksResponse{Webhooks: hooks}, nil
+}
+
+func (ws *WebhookServer) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteWebhookResponse, error) {
+	if err := ws.Store.Delete(ctx, req.GetId()); err != nil {
+		return nil, status.Error(webhookErrors.code(err), err.Error())
+	}
+
+	return &pb.DeleteWebhookResponse{}, nil
+}
+
+func (ws *WebhookServer) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	letters, err := ws.Store.DeadLetters(ctx, req.GetWebhookId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListDeadLettersResponse{DeadLetters: letters}, nil
+}
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/service"
+	"bookstoregrpc/webhook"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestWebhooks_server(t *testing.T) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), principalKey, "admin")
+	db := initTestDB(t)
+
+	conn := dialTestServer(t, func(s *grpc.Server) {
+		store := webhook.NewStore(db, webhook.StoreOptions{Admins: audit.ParsePrincipals("admin")})
+		pb.RegisterWebhookServiceServer(s, service.NewWebhookServer(store))
+	}, grpc.UnaryInterceptor(audit.UnaryServerInterceptor(principalFromMetadata)))
+
+	client := pb.NewWebhookServiceClient(conn)
+
+	created, err := client.CreateWebhook(ctx, &pb.CreateWebhookRequest{Webhook: &pb.Webhook{
+		Url:        "https://example.com/hook",
+		EventTypes: []pb.BookEvent_Type{pb.BookEvent_UPDATED, pb.BookEvent_DELETED},
+	}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.Webhook.Secret)
+
+	t.Run("Invalid Webhook", func(t *testing.T) {
+		_, err := client.CreateWebhook(ctx, &pb.CreateWebhookRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.CreateWebhook(ctx, &pb.CreateWebhookRequest{Webhook: &pb.Webhook{Url: "ftp://example.com"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.CreateWebhook(ctx, &pb.CreateWebhookRequest{Webhook: &pb.Webhook{Url: "http://169.254.169.254/latest/meta-data"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Not Admin", func(t *testing.T) {
+		bob := metadata.AppendToOutgoingContext(context.Background(), principalKey, "bob")
+		_, err := client.CreateWebhook(bob, &pb.CreateWebhookRequest{Webhook: &pb.Webhook{Url: "https://example.com/hook"}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = client.DeleteWebhook(context.Background(), &pb.DeleteWebhookRequest{Id: created.Webhook.Id})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("List Webhooks", func(t *testing.T) {
+		res, err := client.ListWebhooks(ctx, &pb.ListWebhooksRequest{})
+		assert.NoError(t, err)
+		if assert.Len(t, res.Webhooks, 1) {
+			assert.Equal(t, created.Webhook.Id, res.Webhooks[0].Id)
+			assert.Len(t, res.Webhooks[0].EventTypes, 2)
+			assert.Empty(t, res.Webhooks[0].Secret)
+		}
+
+		letters, err := client.ListDeadLetters(ctx, &pb.ListDeadLettersRequest{})
+		assert.NoError(t, err)
+		assert.Empty(t, letters.DeadLetters)
+	})
+
+	t.Run("Delete Webhook", func(t *testing.T) {
+		_, err := client.DeleteWebhook(ctx, &pb.DeleteWebhookRequest{Id: created.Webhook.Id})
+		assert.NoError(t, err)
+
+		_, err = client.DeleteWebhook(ctx, &pb.DeleteWebhookRequest{Id: created.Webhook.Id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// DefaultMetricInterval is how often metrics are exported when
+// OTEL_METRIC_EXPORT_INTERVAL is not set.
+const DefaultMetricInterval = time.Minute
+
+// InitMetrics installs the global meter provider, so that the counters
+// of the service, e.g. the book cache hits and misses, are exported. The
+// exporter is chosen by OTEL_METRICS_EXPORTER (otlp, stdout or none), the
+// period by OTEL_METRIC_EXPORT_INTERVAL, e.g. "30s"; the OTLP exporter
+// reads OTEL_EXPORTER_OTLP_* variables like the trace exporter.
+// The returned function flushes and stops the provider.
+func InitMetrics(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	exporterName := os.Getenv("OTEL_METRICS_EXPORTER")
+	if exporterName == "" || exporterName == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newMetricExporter(ctx, exporterName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := time.ParseDuration(os.Getenv("OTEL_METRIC_EXPORT_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = DefaultMetricInterval
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, nil
+}
+
+func newMetricExporter(ctx context.Context, name string) (sdkmetric.Exporter, error) {
+	switch name {
+	case ExporterStdout:
+		return stdoutmetric.New(stdoutmetric.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		return otlpmetricgrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown metric exporter %q", name)
+	}
+}
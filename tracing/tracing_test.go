@@ -0,0 +1,115 @@
+package tracing_test
+
+import (
+	"bookstoregrpc/tracing"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", tracing.ExporterNone)
+		shutdown, err := tracing.Init(context.Background(), t.Name())
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", tracing.ExporterStdout)
+		shutdown, err := tracing.Init(context.Background(), t.Name())
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown exporter", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+		_, err := tracing.Init(context.Background(), t.Name())
+		assert.Error(t, err)
+	})
+}
+
+func TestInitMetrics(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		t.Setenv("OTEL_METRICS_EXPORTER", tracing.ExporterNone)
+		shutdown, err := tracing.InitMetrics(context.Background(), t.Name())
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		t.Setenv("OTEL_METRICS_EXPORTER", tracing.ExporterStdout)
+		t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "1h")
+		shutdown, err := tracing.InitMetrics(context.Background(), t.Name())
+		assert.NoError(t, err)
+
+		counter, err := otel.Meter(t.Name()).Int64Counter("test.counter")
+		assert.NoError(t, err)
+		counter.Add(context.Background(), 1)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown exporter", func(t *testing.T) {
+		t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+		_, err := tracing.InitMetrics(context.Background(), t.Name())
+		assert.Error(t, err)
+	})
+}
+
+func TestPropagation(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", tracing.ExporterNone)
+	_, err := tracing.Init(context.Background(), t.Name())
+	assert.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(listener)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	s.Stop()
+	assert.NoError(t, tp.Shutdown(context.Background()))
+
+	var client, server sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.SpanKind() {
+		case trace.SpanKindClient:
+			client = span
+		case trace.SpanKindServer:
+			server = span
+		}
+	}
+
+	if assert.NotNil(t, client) && assert.NotNil(t, server) {
+		assert.Equal(t, client.SpanContext().TraceID(), server.SpanContext().TraceID())
+		assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+	}
+}
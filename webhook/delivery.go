@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"bookstoregrpc/pb"
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Delivery is an event waiting to be sent to a subscription. Every
+// subscription's deliveries are retried on their own schedule, so a
+// webhook that is down delays only its own events.
+type Delivery struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement"`
+	SubscriptionID string `gorm:"uniqueIndex:idx_webhook_deliveries_event"`
+	// Sequence is the event sequence; the relay may hand an event over
+	// twice, but it is queued once per subscription.
+	Sequence  uint64 `gorm:"uniqueIndex:idx_webhook_deliveries_event"`
+	EventType string
+	Payload   []byte
+	// Attempts counts the attempts started, including the one in flight.
+	Attempts int32
+	// NextAttemptAt is when the delivery is due. While an attempt is in
+	// flight it is the end of the attempt's lease, after which a crashed
+	// attempt is retried.
+	NextAttemptAt time.Time `gorm:"index"`
+	LastError     string
+	CreatedAt     time.Time
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// enqueue queues event for every subscription that wants it.
+func (s *Store) enqueue(ctx context.Context, event *pb.BookEvent) error {
+	subs, err := s.Subscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := protojson.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []*Delivery
+	for _, sub := range subs {
+		if !sub.Matches(event.GetType()) {
+			continue
+		}
+
+		deliveries = append(deliveries, &Delivery{
+			SubscriptionID: sub.ID,
+			Sequence:       event.GetSequence(),
+			EventType:      event.GetType().String(),
+			Payload:        payload,
+			NextAttemptAt:  now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// due returns up to limit deliveries whose next attempt is due, oldest
+// first.
+func (s *Store) due(ctx context.Context, limit int) ([]*Delivery, error) {
+	var deliveries []*Delivery
+	err := s.db.WithContext(ctx).
+		Where("next_attempt_at <= ?", time.Now()).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	return deliveries, err
+}
+
+// claim starts an attempt of delivery that lasts until lease. It reports
+// false if another worker started an attempt first.
+func (s *Store) claim(ctx context.Context, delivery *Delivery, lease time.Time) (bool, error) {
+	res := s.db.WithContext(ctx).Model(&Delivery{}).
+		Where("id = ? AND attempts = ?", delivery.ID, delivery.Attempts).
+		Updates(map[string]any{"attempts": delivery.Attempts + 1, "next_attempt_at": lease})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	delivery.Attempts++
+
+	return res.RowsAffected == 1, nil
+}
+
+// delivered removes a delivery the subscription accepted.
+func (s *Store) delivered(ctx context.Context, delivery *Delivery) error {
+	return s.db.WithContext(ctx).Delete(&Delivery{}, delivery.ID).Error
+}
+
+// retry schedules the next attempt of a failed delivery.
+func (s *Store) retry(ctx context.Context, delivery *Delivery, at time.Time, cause error) error {
+	return s.db.WithContext(ctx).Model(&Delivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{"next_attempt_at": at, "last_error": cause.Error()}).Error
+}
+
+// bury moves a delivery that failed its last attempt to the dead letters.
+func (s *Store) bury(ctx context.Context, delivery *Delivery, cause error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Delivery{}, delivery.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&DeadLetter{
+			SubscriptionID: delivery.SubscriptionID,
+			Payload:        delivery.Payload,
+			Attempts:       delivery.Attempts,
+			Error:          cause.Error(),
+		}).Error
+	})
+}
+
+// Pending returns the number of deliveries not yet sent or buried.
+func (s *Store) Pending(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.WithContext(ctx).Model(&Delivery{}).Count(&n).Error
+
+	return n, err
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"bookstoregrpc/pb"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderSignature = "X-Bookstore-Signature"
+	HeaderEvent     = "X-Bookstore-Event"
+	HeaderDelivery  = "X-Bookstore-Delivery"
+)
+
+type DispatcherOptions struct {
+	// MaxAttempts is the number of deliveries tried before an event is
+	// moved to the dead letters.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the delay between attempts; the
+	// delay doubles after every failure.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout limits a single attempt.
+	Timeout time.Duration
+	// PollInterval is how long the worker waits when no delivery is due.
+	PollInterval time.Duration
+	// BatchSize is the number of deliveries attempted in parallel.
+	BatchSize int
+}
+
+func DefaultDispatcherOptions() DispatcherOptions {
+	return DispatcherOptions{
+		MaxAttempts:  5,
+		MinBackoff:   time.Second,
+		MaxBackoff:   30 * time.Second,
+		Timeout:      10 * time.Second,
+		PollInterval: time.Second,
+		BatchSize:    20,
+	}
+}
+
+// Dispatcher sends events to the webhooks subscribed to their type. As an
+// outbox sink it only queues a delivery per webhook, so the relay never
+// waits for a webhook; Run sends the queued deliveries and retries every
+// one independently. An event a webhook keeps rejecting becomes a dead
+// letter for that webhook instead of delaying the others.
+type Dispatcher struct {
+	store  *Store
+	client *http.Client
+	opts   DispatcherOptions
+}
+
+func NewDispatcher(store *Store, opts DispatcherOptions) *Dispatcher {
+	defaults := DefaultDispatcherOptions()
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaults.MinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaults.MaxBackoff, opts.MinBackoff)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !store.opts.AllowPrivateNetworks {
+		// Checked on the resolved address, so a host name that resolves
+		// to a private address after the webhook was registered, or a
+		// redirect to one, is refused too.
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || privateIP(ip) {
+				return ErrPrivateURL
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Dispatcher{
+		store:  store,
+		client: &http.Client{Timeout: opts.Timeout, Transport: transport},
+		opts:   opts,
+	}
+}
+
+func (d *Dispatcher) Name() string {
+	return "webhooks"
+}
+
+// Deliver queues event for the webhooks subscribed to its type.
+func (d *Dispatcher) Deliver(ctx context.Context, event *pb.BookEvent) error {
+	return d.store.enqueue(ctx, event)
+}
+
+// Run sends queued deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		attempted, err := d.RunOnce(ctx)
+
+		wait := d.opts.PollInterval
+		switch {
+		case err != nil:
+			log.Printf("webhook dispatcher: %v", err)
+		case attempted > 0:
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RunOnce attempts up to BatchSize due deliveries in parallel and returns
+// how many it attempted. A failed delivery is scheduled again after a
+// backoff, or moved to the dead letters after MaxAttempts.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.store.due(ctx, d.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subs, err := d.store.Subscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[string]*Subscription, len(subs))
+	for _, sub := range subs {
+		byID[sub.ID] = sub
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(deliveries))
+	attempted := make([]bool, len(deliveries))
+	for i, delivery := range deliveries {
+		sub, ok := byID[delivery.SubscriptionID]
+		if !ok {
+			// Deleted after the deliveries were read.
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempted[i], errs[i] = d.attempt(ctx, sub, delivery)
+		}()
+	}
+	wg.Wait()
+
+	n := 0
+	for i := range deliveries {
+		if attempted[i] {
+			n++
+		}
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// attempt sends delivery once and records the outcome. It reports false if
+// another worker claimed the delivery first.
+func (d *Dispatcher) attempt(ctx context.Context, sub *Subscription, delivery *Delivery) (bool, error) {
+	// The lease outlasts the attempt, so a worker that crashes mid-send
+	// leaves the delivery to be retried, and no other worker sends it
+	// meanwhile.
+	claimed, err := d.store.claim(ctx, delivery, time.Now().Add(2*d.opts.Timeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	err = d.send(ctx, sub, delivery)
+	switch {
+	case err == nil:
+		return true, d.store.delivered(ctx, delivery)
+	case int(delivery.Attempts) >= d.opts.MaxAttempts:
+		log.Printf("webhook %s: event %d moved to dead letters: %v", sub.ID, delivery.Sequence, err)
+		return true, d.store.bury(ctx, delivery, err)
+	default:
+		return true, d.store.retry(ctx, delivery, time.Now().Add(d.backoff(delivery.Attempts)), err)
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int32) time.Duration {
+	backoff := d.opts.MinBackoff
+	for i := int32(1); i < attempts && backoff < d.opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, d.opts.MaxBackoff)
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *Subscription, delivery *Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, delivery.Payload))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(delivery.Sequence, 10))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+
+	return nil
+}
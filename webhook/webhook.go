@@ -0,0 +1,258 @@
+package webhook
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/pb"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidURL = errors.New("webhook url must be an absolute http or https url")
+	ErrPrivateURL = errors.New("webhook url must not point to a loopback, link-local or private address")
+	ErrNotAdmin   = errors.New("only webhook admins can register and delete webhooks")
+)
+
+// Subscription is a registered webhook.
+type Subscription struct {
+	ID     string `gorm:"primaryKey"`
+	URL    string
+	Secret string
+	// EventTypes is a comma-separated list of BookEvent_Type names, empty
+	// for every type.
+	EventTypes string
+	CreatedAt  time.Time
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Matches reports whether the subscription wants events of type typ.
+func (s *Subscription) Matches(typ pb.BookEvent_Type) bool {
+	if s.EventTypes == "" {
+		return true
+	}
+
+	return slices.Contains(strings.Split(s.EventTypes, ","), typ.String())
+}
+
+// DeadLetter is an event that was not delivered to a subscription.
+type DeadLetter struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement"`
+	SubscriptionID string `gorm:"index"`
+	Payload        []byte
+	Attempts       int32
+	Error          string
+	CreatedAt      time.Time
+}
+
+func (DeadLetter) TableName() string {
+	return "webhook_dead_letters"
+}
+
+func Models() []any {
+	return []any{&Subscription{}, &Delivery{}, &DeadLetter{}}
+}
+
+// Sign returns the X-Bookstore-Signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type StoreOptions struct {
+	// Admins are the principals allowed to register and delete webhooks.
+	Admins audit.Principals
+	// AllowPrivateNetworks lets webhooks call loopback, link-local and
+	// private addresses. The server calls every registered URL, so this is
+	// only for tests and deployments whose receivers all live inside.
+	AllowPrivateNetworks bool
+}
+
+type Store struct {
+	db   *gorm.DB
+	opts StoreOptions
+}
+
+func NewStore(db *gorm.DB, opts StoreOptions) *Store {
+	return &Store{db: db, opts: opts}
+}
+
+// privateIP reports whether ip is an address webhooks must not call.
+func privateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// checkHost rejects the host names of private addresses. A public name
+// may still resolve to one, so the dispatcher checks the address again
+// when it connects.
+func (s *Store) checkHost(host string) error {
+	if s.opts.AllowPrivateNetworks {
+		return nil
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateURL
+	}
+	if ip := net.ParseIP(host); ip != nil && privateIP(ip) {
+		return ErrPrivateURL
+	}
+
+	return nil
+}
+
+// Create registers a webhook and returns it with its secret. Only admins
+// can register webhooks, and not to private addresses.
+func (s *Store) Create(ctx context.Context, webhook *pb.Webhook) (*pb.Webhook, error) {
+	if !s.opts.Admins.Allows(ctx) {
+		return nil, ErrNotAdmin
+	}
+
+	u, err := url.Parse(webhook.GetUrl())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	if err := s.checkHost(u.Hostname()); err != nil {
+		return nil, err
+	}
+
+	secret := webhook.GetSecret()
+	if secret == "" {
+		key := make([]byte, 32)
+		rand.Read(key)
+		secret = hex.EncodeToString(key)
+	}
+
+	types := make([]string, len(webhook.GetEventTypes()))
+	for i, typ := range webhook.GetEventTypes() {
+		types[i] = typ.String()
+	}
+
+	sub := &Subscription{
+		ID:         uuid.New().String(),
+		URL:        u.String(),
+		Secret:     secret,
+		EventTypes: strings.Join(types, ","),
+	}
+	if err := s.db.WithContext(ctx).Create(sub).Error; err != nil {
+		return nil, err
+	}
+
+	res := toProto(sub)
+	res.Secret = secret
+
+	return res, nil
+}
+
+// List returns the webhooks without their secrets.
+func (s *Store) List(ctx context.Context) ([]*pb.Webhook, error) {
+	subs, err := s.Subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*pb.Webhook, len(subs))
+	for i, sub := range subs {
+		webhooks[i] = toProto(sub)
+	}
+
+	return webhooks, nil
+}
+
+// Delete removes the webhook, its pending deliveries and its dead letters.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if !s.opts.Admins.Allows(ctx) {
+		return ErrNotAdmin
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ?", id).Delete(&Subscription{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("subscription_id = ?", id).Delete(&Delivery{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("subscription_id = ?", id).Delete(&DeadLetter{}).Error
+	})
+}
+
+func (s *Store) Subscriptions(ctx context.Context) ([]*Subscription, error) {
+	var subs []*Subscription
+	err := s.db.WithContext(ctx).Order("created_at, id").Find(&subs).Error
+
+	return subs, err
+}
+
+// DeadLetters lists the dead letters of a webhook, or of every webhook
+// when subscriptionID is empty, oldest first.
+func (s *Store) DeadLetters(ctx context.Context, subscriptionID string) ([]*pb.DeadLetter, error) {
+	query := s.db.WithContext(ctx).Order("id")
+	if subscriptionID != "" {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+
+	var letters []*DeadLetter
+	if err := query.Find(&letters).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.DeadLetter, len(letters))
+	for i, letter := range letters {
+		event := &pb.BookEvent{}
+		if err := protojson.Unmarshal(letter.Payload, event); err != nil {
+			return nil, err
+		}
+
+		res[i] = &pb.DeadLetter{
+			Id:         letter.ID,
+			WebhookId:  letter.SubscriptionID,
+			Event:      event,
+			Attempts:   letter.Attempts,
+			Error:      letter.Error,
+			CreateTime: timestamppb.New(letter.CreatedAt),
+		}
+	}
+
+	return res, nil
+}
+
+func toProto(sub *Subscription) *pb.Webhook {
+	webhook := &pb.Webhook{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		CreateTime: timestamppb.New(sub.CreatedAt),
+	}
+
+	if sub.EventTypes != "" {
+		for _, name := range strings.Split(sub.EventTypes, ",") {
+			webhook.EventTypes = append(webhook.EventTypes, pb.BookEvent_Type(pb.BookEvent_Type_value[name]))
+		}
+	}
+
+	return webhook
+}
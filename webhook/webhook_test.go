@@ -0,0 +1,269 @@
+package webhook_test
+
+import (
+	"bookstoregrpc/audit"
+	"bookstoregrpc/database"
+	"bookstoregrpc/database/dbtest"
+	"bookstoregrpc/outbox"
+	"bookstoregrpc/pb"
+	"bookstoregrpc/webhook"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+)
+
+func initTestDB(t *testing.T) *gorm.DB {
+	opts := database.DefaultOptions()
+	opts.Migrations = []func(*gorm.DB) error{
+		func(db *gorm.DB) error { return db.AutoMigrate(append(webhook.Models(), outbox.Models()...)...) },
+	}
+
+	return dbtest.Open(t, opts).DB
+}
+
+// receiver is a webhook endpoint that verifies signatures and fails the
+// first failures requests.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	mu       sync.Mutex
+	failures int
+	events   []*pb.BookEvent
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(rc.t, err)
+	assert.Equal(rc.t, webhook.Sign(rc.secret, body), r.Header.Get(webhook.HeaderSignature))
+
+	event := &pb.BookEvent{}
+	assert.NoError(rc.t, protojson.Unmarshal(body, event))
+	assert.Equal(rc.t, event.Type.String(), r.Header.Get(webhook.HeaderEvent))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	rc.events = append(rc.events, event)
+}
+
+// admin is the context of a webhook admin's call.
+var admin = audit.NewContext(context.Background(), audit.Info{Principal: "admin"})
+
+func testOptions() webhook.StoreOptions {
+	return webhook.StoreOptions{Admins: audit.ParsePrincipals("admin"), AllowPrivateNetworks: true}
+}
+
+func (rc *receiver) received() []*pb.BookEvent {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.events
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	ctx := admin
+	store := webhook.NewStore(initTestDB(t), webhook.StoreOptions{Admins: audit.ParsePrincipals("admin")})
+
+	created, err := store.Create(ctx, &pb.Webhook{
+		Url:        "https://hooks.example.com/hook",
+		EventTypes: []pb.BookEvent_Type{pb.BookEvent_UPDATED},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.Id)
+	assert.Len(t, created.Secret, 64)
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := store.Create(ctx, &pb.Webhook{Url: "localhost/hook"})
+		assert.ErrorIs(t, err, webhook.ErrInvalidURL)
+	})
+
+	t.Run("Private URL", func(t *testing.T) {
+		for _, url := range []string{
+			"http://localhost/hook",
+			"http://api.localhost/hook",
+			"http://127.0.0.1:8080/hook",
+			"http://[::1]/hook",
+			"http://169.254.169.254/latest/meta-data",
+			"http://10.0.0.7/hook",
+			"http://192.168.1.1/hook",
+			"http://0.0.0.0/hook",
+		} {
+			_, err := store.Create(ctx, &pb.Webhook{Url: url})
+			assert.ErrorIs(t, err, webhook.ErrPrivateURL, url)
+		}
+	})
+
+	t.Run("Not Admin", func(t *testing.T) {
+		_, err := store.Create(context.Background(), &pb.Webhook{Url: "https://hooks.example.com/hook"})
+		assert.ErrorIs(t, err, webhook.ErrNotAdmin)
+
+		mallory := audit.NewContext(context.Background(), audit.Info{Principal: "mallory"})
+		_, err = store.Create(mallory, &pb.Webhook{Url: "https://hooks.example.com/hook"})
+		assert.ErrorIs(t, err, webhook.ErrNotAdmin)
+		assert.ErrorIs(t, store.Delete(mallory, created.Id), webhook.ErrNotAdmin)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		hooks, err := store.List(ctx)
+		assert.NoError(t, err)
+		if assert.Len(t, hooks, 1) {
+			assert.Equal(t, created.Id, hooks[0].Id)
+			assert.Equal(t, []pb.BookEvent_Type{pb.BookEvent_UPDATED}, hooks[0].EventTypes)
+			assert.Empty(t, hooks[0].Secret)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.NoError(t, store.Delete(ctx, created.Id))
+		assert.ErrorIs(t, store.Delete(ctx, created.Id), gorm.ErrRecordNotFound)
+
+		hooks, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, hooks)
+	})
+}
+
+// deliverAll runs the dispatcher until no delivery is pending.
+func deliverAll(t *testing.T, store *webhook.Store, dispatcher *webhook.Dispatcher) {
+	for range 100 {
+		_, err := dispatcher.RunOnce(context.Background())
+		assert.NoError(t, err)
+
+		pending, err := store.Pending(context.Background())
+		assert.NoError(t, err)
+		if pending == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("deliveries still pending")
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+	ctx := admin
+	db := initTestDB(t)
+	store := webhook.NewStore(db, testOptions())
+
+	prices := &receiver{t: t, secret: "prices", failures: 2}
+	pricesServer := httptest.NewServer(prices)
+	defer pricesServer.Close()
+
+	all := &receiver{t: t, secret: "all"}
+	allServer := httptest.NewServer(all)
+	defer allServer.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	_, err := store.Create(ctx, &pb.Webhook{
+		Url:        pricesServer.URL,
+		Secret:     prices.secret,
+		EventTypes: []pb.BookEvent_Type{pb.BookEvent_UPDATED},
+	})
+	assert.NoError(t, err)
+	_, err = store.Create(ctx, &pb.Webhook{Url: allServer.URL, Secret: all.secret})
+	assert.NoError(t, err)
+	brokenHook, err := store.Create(ctx, &pb.Webhook{Url: broken.URL})
+	assert.NoError(t, err)
+
+	events := []*pb.BookEvent{
+		{Type: pb.BookEvent_CREATED, After: &pb.Book{Id: "1", Price: 100}},
+		{Type: pb.BookEvent_UPDATED, Before: &pb.Book{Id: "1", Price: 100}, After: &pb.Book{Id: "1", Price: 90}},
+	}
+	for _, event := range events {
+		assert.NoError(t, outbox.Write(db, event))
+	}
+
+	dispatcher := webhook.NewDispatcher(store, webhook.DispatcherOptions{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+	})
+	relay := outbox.NewRelay(db, dispatcher, outbox.DefaultRelayOptions())
+	n, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	t.Run("Relay Only Queues", func(t *testing.T) {
+		assert.Empty(t, all.received())
+
+		pending, err := store.Pending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), pending)
+
+		// An event the relay hands over again is not queued twice.
+		assert.NoError(t, dispatcher.Deliver(ctx, &pb.BookEvent{Type: pb.BookEvent_CREATED, Sequence: 1}))
+		pending, err = store.Pending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), pending)
+	})
+
+	deliverAll(t, store, dispatcher)
+
+	t.Run("Event Filter", func(t *testing.T) {
+		received := prices.received()
+		if assert.Len(t, received, 1) {
+			assert.Equal(t, int32(90), received[0].After.Price)
+			assert.Equal(t, uint64(2), received[0].Sequence)
+		}
+		assert.Len(t, all.received(), 2)
+	})
+
+	t.Run("Dead Letters", func(t *testing.T) {
+		letters, err := store.DeadLetters(ctx, "")
+		assert.NoError(t, err)
+		if assert.Len(t, letters, 2) {
+			assert.Equal(t, brokenHook.Id, letters[0].WebhookId)
+			assert.Equal(t, int32(3), letters[0].Attempts)
+			assert.Equal(t, pb.BookEvent_CREATED, letters[0].Event.Type)
+			assert.Contains(t, letters[0].Error, "500")
+		}
+
+		letters, err = store.DeadLetters(ctx, "unknown")
+		assert.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+}
+
+func TestDispatcher_privateAddress(t *testing.T) {
+	t.Parallel()
+	db := initTestDB(t)
+
+	hook := &receiver{t: t, secret: "internal"}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	// A host name can resolve to a private address after it was
+	// registered; the dispatcher checks the address it connects to.
+	assert.NoError(t, db.Create(&webhook.Subscription{ID: "internal", URL: server.URL, Secret: hook.secret}).Error)
+
+	store := webhook.NewStore(db, webhook.StoreOptions{Admins: audit.ParsePrincipals("admin")})
+	dispatcher := webhook.NewDispatcher(store, webhook.DispatcherOptions{MaxAttempts: 1})
+	assert.NoError(t, dispatcher.Deliver(admin, &pb.BookEvent{Type: pb.BookEvent_CREATED, Sequence: 1}))
+	deliverAll(t, store, dispatcher)
+
+	assert.Empty(t, hook.received())
+	letters, err := store.DeadLetters(admin, "internal")
+	assert.NoError(t, err)
+	if assert.Len(t, letters, 1) {
+		assert.True(t, strings.Contains(letters[0].Error, webhook.ErrPrivateURL.Error()), letters[0].Error)
+	}
+}